@@ -42,6 +42,6 @@ func BenchmarkCheckRules(b *testing.B) {
 	cfg, _ := config.Load("", false)
 	gen := config.NewPrometheusGenerator(cfg, prometheus.NewRegistry())
 	for n := 0; n < b.N; n++ {
-		_, _ = checkRules(ctx, 10, true, gen, cfg, entries)
+		_, _ = checkRules(ctx, 10, true, gen, cfg, entries, nil)
 	}
 }
@@ -54,6 +54,18 @@ var ciCmd = &cli.Command{
 			Value:   false,
 			Usage:   "Report problems using TeamCity Service Messages",
 		},
+		&cli.StringFlag{
+			Name:  metricsFileFlag,
+			Usage: "Write metrics to this file using Prometheus text format",
+		},
+		&cli.StringFlag{
+			Name:  shardFlag,
+			Usage: "Only run checks on files from the N-th of M shards, using N/M format",
+		},
+		&cli.StringFlag{
+			Name:  reportFileFlag,
+			Usage: "Write all problems to this file as JSON, so it can be merged with pint merge-reports",
+		},
 	},
 }
 
@@ -63,6 +75,11 @@ func actionCI(c *cli.Context) error {
 		return err
 	}
 
+	sh, err := parseShard(c.String(shardFlag))
+	if err != nil {
+		return err
+	}
+
 	includeRe := []*regexp.Regexp{}
 	for _, pattern := range meta.cfg.CI.Include {
 		includeRe = append(includeRe, regexp.MustCompile("^"+pattern+"$"))
@@ -85,6 +102,10 @@ func actionCI(c *cli.Context) error {
 	slog.Debug("Got branch information", slog.String("base", baseBranch), slog.String("current", currentBranch))
 	if currentBranch == strings.Split(baseBranch, "/")[len(strings.Split(baseBranch, "/"))-1] {
 		slog.Info("Running from base branch, skipping checks", slog.String("branch", currentBranch))
+		if path := c.String(reportFileFlag); path != "" {
+			// Write an empty report so merge-reports can still be run after all shards.
+			return writeReportFile(path, reporter.NewSummary(nil))
+		}
 		return nil
 	}
 
@@ -114,13 +135,14 @@ func actionCI(c *cli.Context) error {
 
 	slog.Debug("Generated all Prometheus servers", slog.Int("count", gen.Count()))
 
-	summary, err := checkRules(ctx, meta.workers, meta.isOffline, gen, meta.cfg, entries)
+	selected := sh.selectEntries(entries)
+	summary, err := checkRules(ctx, meta.workers, meta.isOffline, gen, meta.cfg, entries, selected)
 	if err != nil {
 		return err
 	}
 
 	if c.Bool(requireOwnerFlag) {
-		summary.Report(verifyOwners(entries, meta.cfg.Owners.CompileAllowed())...)
+		summary.Report(verifyOwners(filterEntries(entries, selected), meta.cfg.Owners.CompileAllowed())...)
 	}
 
 	reps := []reporter.Reporter{}
@@ -131,69 +153,103 @@ func actionCI(c *cli.Context) error {
 		reps = append(reps, reporter.NewConsoleReporter(os.Stderr, checks.Information))
 	}
 
-	if meta.cfg.Repository != nil && meta.cfg.Repository.BitBucket != nil {
+	if sh.isEnabled() {
+		slog.Info("Sharding enabled, results will not be reported to BitBucket or GitHub, use pint merge-reports to report merged results")
+	} else {
+		var repoReps []reporter.Reporter
+		if repoReps, err = newRepositoryReporters(&meta.cfg); err != nil {
+			return err
+		}
+		reps = append(reps, repoReps...)
+	}
+
+	minSeverity, err := checks.ParseSeverity(c.String(failOnFlag))
+	if err != nil {
+		return fmt.Errorf("invalid --%s value: %w", failOnFlag, err)
+	}
+
+	if path := c.String(metricsFileFlag); path != "" {
+		if err = writeMetricsFile(path, meta.cfg, entries, summary, checks.Information); err != nil {
+			return err
+		}
+	}
+
+	if path := c.String(reportFileFlag); path != "" {
+		if err = writeReportFile(path, summary); err != nil {
+			return err
+		}
+	}
+
+	return reportProblems(reps, summary, minSeverity)
+}
+
+// newRepositoryReporters returns reporters for all configured
+// source control systems.
+func newRepositoryReporters(cfg *config.Config) (reps []reporter.Reporter, err error) {
+	if cfg.Repository != nil && cfg.Repository.BitBucket != nil {
 		token, ok := os.LookupEnv("BITBUCKET_AUTH_TOKEN")
 		if !ok {
-			return fmt.Errorf("BITBUCKET_AUTH_TOKEN env variable is required when reporting to BitBucket")
+			return nil, fmt.Errorf("BITBUCKET_AUTH_TOKEN env variable is required when reporting to BitBucket")
 		}
 
-		timeout, _ := time.ParseDuration(meta.cfg.Repository.BitBucket.Timeout)
+		timeout, _ := time.ParseDuration(cfg.Repository.BitBucket.Timeout)
 		br := reporter.NewBitBucketReporter(
 			version,
-			meta.cfg.Repository.BitBucket.URI,
+			cfg.Repository.BitBucket.URI,
 			timeout,
 			token,
-			meta.cfg.Repository.BitBucket.Project,
-			meta.cfg.Repository.BitBucket.Repository,
+			cfg.Repository.BitBucket.Project,
+			cfg.Repository.BitBucket.Repository,
 			git.RunGit,
 		)
 		reps = append(reps, br)
 	}
 
-	meta.cfg.Repository = detectRepository(meta.cfg.Repository)
-	if meta.cfg.Repository != nil && meta.cfg.Repository.GitHub != nil {
+	cfg.Repository = detectRepository(cfg.Repository)
+	if cfg.Repository != nil && cfg.Repository.GitHub != nil {
 		token, ok := os.LookupEnv("GITHUB_AUTH_TOKEN")
 		if !ok {
-			return fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
+			return nil, fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
 		}
 
 		prVal, ok := os.LookupEnv("GITHUB_PULL_REQUEST_NUMBER")
 		if !ok {
-			return fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
+			return nil, fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
 		}
 
 		var prNum int
 		if prNum, err = strconv.Atoi(prVal); err != nil {
-			return fmt.Errorf("got not a valid number via GITHUB_PULL_REQUEST_NUMBER: %w", err)
+			return nil, fmt.Errorf("got not a valid number via GITHUB_PULL_REQUEST_NUMBER: %w", err)
 		}
 
-		timeout, _ := time.ParseDuration(meta.cfg.Repository.GitHub.Timeout)
+		timeout, _ := time.ParseDuration(cfg.Repository.GitHub.Timeout)
 		var gr reporter.GithubReporter
 		if gr, err = reporter.NewGithubReporter(
 			version,
-			meta.cfg.Repository.GitHub.BaseURI,
-			meta.cfg.Repository.GitHub.UploadURI,
+			cfg.Repository.GitHub.BaseURI,
+			cfg.Repository.GitHub.UploadURI,
 			timeout,
 			token,
-			meta.cfg.Repository.GitHub.Owner,
-			meta.cfg.Repository.GitHub.Repo,
+			cfg.Repository.GitHub.Owner,
+			cfg.Repository.GitHub.Repo,
 			prNum,
 			git.RunGit,
 		); err != nil {
-			return err
+			return nil, err
 		}
 		reps = append(reps, gr)
 	}
 
-	minSeverity, err := checks.ParseSeverity(c.String(failOnFlag))
-	if err != nil {
-		return fmt.Errorf("invalid --%s value: %w", failOnFlag, err)
-	}
+	return reps, nil
+}
 
+// reportProblems submits the summary to all reporters and returns an error
+// if there are any problems with failOn severity or higher.
+func reportProblems(reps []reporter.Reporter, summary reporter.Summary, failOn checks.Severity) error {
 	problemsFound := false
 	bySeverity := summary.CountBySeverity()
 	for s := range bySeverity {
-		if s >= minSeverity {
+		if s >= failOn {
 			problemsFound = true
 			break
 		}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/drift"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/promapi"
+
+	"github.com/urfave/cli/v2"
+)
+
+var driftCmd = &cli.Command{
+	Name:   "drift",
+	Usage:  "Compare rules in specified files with rules loaded by Prometheus servers",
+	Action: actionDrift,
+}
+
+func actionDrift(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	if meta.isOffline {
+		return fmt.Errorf("drift detection needs to query Prometheus servers and cannot be used with --%s", offlineFlag)
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	slog.Info("Finding all rules to compare", slog.Any("paths", paths))
+	entries, err := discovery.NewGlobFinder(paths, git.NewPathFilter(nil, nil, meta.cfg.Parser.CompileRelaxed())).Find()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	gen := config.NewPrometheusGenerator(meta.cfg, metricsRegistry)
+	defer gen.Stop()
+
+	if err = gen.GenerateStatic(); err != nil {
+		return err
+	}
+	if err = gen.GenerateDynamic(ctx); err != nil {
+		return err
+	}
+
+	var diffs []driftReport
+	var failed int
+	for _, prom := range gen.Servers() {
+		found, err := findDrift(ctx, prom, entries)
+		if err != nil {
+			slog.Error("Failed to compare rules with Prometheus server", slog.String("name", prom.Name()), slog.Any("err", err))
+			failed++
+			continue
+		}
+		diffs = append(diffs, found...)
+	}
+
+	printDrift(os.Stderr, diffs)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to compare rules with %d Prometheus server(s)", failed)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("found %d difference(s) between rule files and Prometheus servers", len(diffs))
+	}
+	slog.Info("All rules are in sync with Prometheus servers", slog.Int("servers", gen.Count()))
+	return nil
+}
+
+type driftReport struct {
+	prometheus string
+	diff       drift.Difference
+}
+
+func findDrift(ctx context.Context, prom *promapi.FailoverGroup, entries []discovery.Entry) ([]driftReport, error) {
+	var local []drift.Rule
+	seen := map[string]struct{}{}
+	for _, entry := range entries {
+		if entry.State == discovery.Excluded || !prom.IsEnabledForPath(entry.SourcePath) {
+			continue
+		}
+		if _, ok := seen[entry.SourcePath]; ok {
+			continue
+		}
+		seen[entry.SourcePath] = struct{}{}
+
+		content, err := os.ReadFile(entry.SourcePath)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := drift.ReadRules(entry.SourcePath, content)
+		if err != nil {
+			return nil, err
+		}
+		local = append(local, rules...)
+	}
+
+	if len(seen) == 0 {
+		slog.Info("No rule files for Prometheus server, skipping", slog.String("name", prom.Name()))
+		return nil, nil
+	}
+
+	slog.Info("Comparing rules with Prometheus server", slog.String("name", prom.Name()), slog.Int("files", len(seen)), slog.Int("rules", len(local)))
+	result, err := prom.Rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := drift.Compare(local, drift.FromAPI(result.Groups))
+	reports := make([]driftReport, 0, len(diffs))
+	for _, diff := range diffs {
+		reports = append(reports, driftReport{prometheus: prom.Name(), diff: diff})
+	}
+	return reports, nil
+}
+
+func (dr driftReport) location() (path string, line int) {
+	if dr.diff.Local != nil {
+		return dr.diff.Local.Path, dr.diff.Local.Line
+	}
+	return discovery.RulesAPIPath(dr.prometheus, dr.diff.Remote.Path), 0
+}
+
+func (dr driftReport) text() string {
+	r := dr.diff.Local
+	if r == nil {
+		r = dr.diff.Remote
+	}
+	switch dr.diff.Kind {
+	case drift.Missing:
+		return fmt.Sprintf("%s rule `%s` from group `%s` is not loaded by `%s` Prometheus server.",
+			r.Type, r.Name, r.Group, dr.prometheus)
+	case drift.Extra:
+		return fmt.Sprintf("%s rule `%s` from group `%s` is loaded by `%s` Prometheus server but it's not present in any rule file.",
+			r.Type, r.Name, r.Group, dr.prometheus)
+	default:
+		return fmt.Sprintf("%s rule `%s` from group `%s` is different on `%s` Prometheus server: %s.",
+			r.Type, r.Name, r.Group, dr.prometheus, strings.Join(dr.diff.Details, ", "))
+	}
+}
+
+func printDrift(w io.Writer, reports []driftReport) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		pi, li := reports[i].location()
+		pj, lj := reports[j].location()
+		if pi != pj {
+			return pi < pj
+		}
+		return li < lj
+	})
+
+	for _, dr := range reports {
+		path, line := dr.location()
+		loc := path
+		if line > 0 {
+			loc = fmt.Sprintf("%s:%d", path, line)
+		}
+		fmt.Fprintln(w,
+			color.CyanString("%s", loc)+" "+
+				color.RedString("Bug: %s", dr.text())+
+				color.MagentaString(" (drift/%s)", dr.diff.Kind),
+		)
+	}
+}
@@ -47,6 +47,18 @@ var lintCmd = &cli.Command{
 			Value:   false,
 			Usage:   "Report problems using TeamCity Service Messages",
 		},
+		&cli.StringFlag{
+			Name:  metricsFileFlag,
+			Usage: "Write metrics to this file using Prometheus text format",
+		},
+		&cli.StringFlag{
+			Name:  shardFlag,
+			Usage: "Only run checks on files from the N-th of M shards, using N/M format",
+		},
+		&cli.StringFlag{
+			Name:  reportFileFlag,
+			Usage: "Write all problems to this file as JSON, so it can be merged with pint merge-reports",
+		},
 	},
 }
 
@@ -61,6 +73,11 @@ func actionLint(c *cli.Context) error {
 		return fmt.Errorf("at least one file or directory required")
 	}
 
+	sh, err := parseShard(c.String(shardFlag))
+	if err != nil {
+		return err
+	}
+
 	slog.Info("Finding all rules to check", slog.Any("paths", paths))
 	finder := discovery.NewGlobFinder(paths, git.NewPathFilter(nil, nil, meta.cfg.Parser.CompileRelaxed()))
 	entries, err := finder.Find()
@@ -77,13 +94,14 @@ func actionLint(c *cli.Context) error {
 		return err
 	}
 
-	summary, err := checkRules(ctx, meta.workers, meta.isOffline, gen, meta.cfg, entries)
+	selected := sh.selectEntries(entries)
+	summary, err := checkRules(ctx, meta.workers, meta.isOffline, gen, meta.cfg, entries, selected)
 	if err != nil {
 		return err
 	}
 
 	if c.Bool(requireOwnerFlag) {
-		summary.Report(verifyOwners(entries, meta.cfg.Owners.CompileAllowed())...)
+		summary.Report(verifyOwners(filterEntries(entries, selected), meta.cfg.Owners.CompileAllowed())...)
 	}
 
 	minSeverity, err := checks.ParseSeverity(c.String(minSeverityFlag))
@@ -95,6 +113,18 @@ func actionLint(c *cli.Context) error {
 		return fmt.Errorf("invalid --%s value: %w", failOnFlag, err)
 	}
 
+	if path := c.String(metricsFileFlag); path != "" {
+		if err = writeMetricsFile(path, meta.cfg, entries, summary, minSeverity); err != nil {
+			return err
+		}
+	}
+
+	if path := c.String(reportFileFlag); path != "" {
+		if err = writeReportFile(path, summary); err != nil {
+			return err
+		}
+	}
+
 	var r reporter.Reporter
 	if c.Bool(teamCityFlag) {
 		r = reporter.NewTeamCityReporter(os.Stderr)
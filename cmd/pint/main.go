@@ -9,6 +9,7 @@ import (
 	"go.uber.org/automaxprocs/maxprocs"
 
 	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/promapi"
 )
 
 const (
@@ -18,6 +19,8 @@ const (
 	offlineFlag  = "offline"
 	noColorFlag  = "no-color"
 	workersFlag  = "workers"
+	recordFlag   = "record"
+	replayFlag   = "replay"
 )
 
 var (
@@ -65,12 +68,35 @@ func newApp() *cli.App {
 				Value:   false,
 				Usage:   "Disable all check that send live queries to Prometheus servers",
 			},
+			&cli.PathFlag{
+				Name:  recordFlag,
+				Usage: "Record all Prometheus API responses into this directory",
+			},
+			&cli.PathFlag{
+				Name:  replayFlag,
+				Usage: "Replay Prometheus API responses recorded with --record from this directory instead of sending any requests",
+			},
+			&cli.PathFlag{
+				Name:  traceFileFlag,
+				Usage: "Write OpenTelemetry traces to this file as JSON",
+			},
+			&cli.BoolFlag{
+				Name:  traceOTLPFlag,
+				Value: false,
+				Usage: "Export OpenTelemetry traces via OTLP, configured using OTEL_EXPORTER_OTLP_* env variables",
+			},
+		},
+		After: func(_ *cli.Context) error {
+			return stopTracing()
 		},
 		Commands: []*cli.Command{
 			versionCmd,
 			lintCmd,
 			ciCmd,
+			mergeReportsCmd,
 			watchCmd,
+			driftCmd,
+			serveCmd,
 			configCmd,
 			parseCmd,
 		},
@@ -95,24 +121,58 @@ func actionSetup(c *cli.Context) (meta actionMeta, err error) {
 		slog.Error("failed to set GOMAXPROCS", slog.Any("err", err))
 	}
 
+	if err = initTracing(c); err != nil {
+		return meta, err
+	}
+
 	meta.workers = c.Int(workersFlag)
 	if meta.workers < 1 {
 		return meta, fmt.Errorf("--%s flag must be > 0", workersFlag)
 	}
 
-	meta.cfg, err = config.Load(c.Path(configFlag), c.IsSet(configFlag))
+	meta.isOffline = c.Bool(offlineFlag)
+	meta.cfg, err = loadConfig(c, meta.isOffline)
 	if err != nil {
-		return meta, fmt.Errorf("failed to load config file %q: %w", c.Path(configFlag), err)
+		return meta, err
+	}
+	if c.IsSet(recordFlag) && c.IsSet(replayFlag) {
+		return meta, fmt.Errorf("--%s and --%s flags cannot be used together", recordFlag, replayFlag)
+	}
+	if dir := c.Path(recordFlag); dir != "" {
+		if _, err = promapi.RecordTo(dir); err != nil {
+			return meta, err
+		}
 	}
-	meta.cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
-	if c.Bool(offlineFlag) {
-		meta.isOffline = true
-		meta.cfg.DisableOnlineChecks()
+	if dir := c.Path(replayFlag); dir != "" {
+		if _, err = promapi.ReplayFrom(dir); err != nil {
+			return meta, err
+		}
+	}
+	if meta.cfg.Cache != nil {
+		if c.IsSet(recordFlag) || c.IsSet(replayFlag) {
+			slog.Info("On-disk query cache is disabled when recording or replaying Prometheus API responses")
+		} else if _, err = promapi.UseDiskCache(meta.cfg.Cache.Dir, meta.cfg.Cache.MaxSizeBytes()); err != nil {
+			return meta, err
+		}
 	}
 
 	return meta, nil
 }
 
+// loadConfig reads and validates the config file and applies
+// all command line flags that modify it.
+func loadConfig(c *cli.Context, isOffline bool) (cfg config.Config, err error) {
+	cfg, err = config.Load(c.Path(configFlag), c.IsSet(configFlag))
+	if err != nil {
+		return cfg, fmt.Errorf("failed to load config file %q: %w", c.Path(configFlag), err)
+	}
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+	if isOffline {
+		cfg.DisableOnlineChecks()
+	}
+	return cfg, nil
+}
+
 func main() {
 	app := newApp()
 	err := app.Run(os.Args)
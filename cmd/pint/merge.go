@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/urfave/cli/v2"
+)
+
+var mergeReportsCmd = &cli.Command{
+	Name:      "merge-reports",
+	Usage:     "Merge report files written by sharded lint or ci runs and report all problems",
+	ArgsUsage: "FILE...",
+	Action:    actionMergeReports,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    failOnFlag,
+			Aliases: []string{"w"},
+			Value:   "bug",
+			Usage:   "Exit with non-zero code if there are problems with given severity (or higher) detected",
+		},
+		&cli.BoolFlag{
+			Name:    teamCityFlag,
+			Aliases: []string{"t"},
+			Value:   false,
+			Usage:   "Report problems using TeamCity Service Messages",
+		},
+	},
+}
+
+func actionMergeReports(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one report file required")
+	}
+
+	failOn, err := checks.ParseSeverity(c.String(failOnFlag))
+	if err != nil {
+		return fmt.Errorf("invalid --%s value: %w", failOnFlag, err)
+	}
+
+	summaries := make([]reporter.Summary, 0, len(paths))
+	for _, path := range paths {
+		var s reporter.Summary
+		if s, err = readReportFile(path); err != nil {
+			return err
+		}
+		slog.Debug("Loaded report file", slog.String("path", path), slog.Int("problems", len(s.Reports())))
+		summaries = append(summaries, s)
+	}
+	summary := reporter.MergeSummaries(summaries...)
+	slog.Info("Merged report files", slog.Int("files", len(paths)), slog.Int("problems", len(summary.Reports())))
+
+	reps := []reporter.Reporter{}
+	if c.Bool(teamCityFlag) {
+		reps = append(reps, reporter.NewTeamCityReporter(os.Stderr))
+	} else {
+		reps = append(reps, reporter.NewConsoleReporter(os.Stderr, checks.Information))
+	}
+
+	repoReps, err := newRepositoryReporters(&meta.cfg)
+	if err != nil {
+		return err
+	}
+	reps = append(reps, repoReps...)
+
+	return reportProblems(reps, summary, failOn)
+}
+
+func readReportFile(path string) (summary reporter.Summary, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return summary, fmt.Errorf("failed to open report file: %w", err)
+	}
+	defer f.Close()
+
+	if summary, err = reporter.ReadJSONSummary(f); err != nil {
+		return summary, fmt.Errorf("failed to parse report file %s: %w", path, err)
+	}
+	return summary, nil
+}
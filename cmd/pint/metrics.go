@@ -2,6 +2,36 @@ package main
 
 import "github.com/prometheus/client_golang/prometheus"
 
+// runGauges track the progress of a single checks run.
+type runGauges struct {
+	checks      prometheus.Gauge
+	checksDone  prometheus.Gauge
+	lastRunTime prometheus.Gauge
+	duration    prometheus.Gauge
+}
+
+// iterationGauges are exposed on the metrics endpoint and updated by lint,
+// ci and watch runs, which never run concurrently.
+func iterationGauges() runGauges {
+	return runGauges{
+		checks:      checkIterationChecks,
+		checksDone:  checkIterationChecksDone,
+		lastRunTime: lastRunTime,
+		duration:    lastRunDuration,
+	}
+}
+
+// discardGauges returns gauges that are not exposed anywhere, for runs that
+// can happen concurrently and so shouldn't update iteration gauges.
+func discardGauges() runGauges {
+	return runGauges{
+		checks:      prometheus.NewGauge(prometheus.GaugeOpts{Name: "checks"}),
+		checksDone:  prometheus.NewGauge(prometheus.GaugeOpts{Name: "checks_done"}),
+		lastRunTime: prometheus.NewGauge(prometheus.GaugeOpts{Name: "last_run_time"}),
+		duration:    prometheus.NewGauge(prometheus.GaugeOpts{Name: "duration"}),
+	}
+}
+
 var (
 	metricsRegistry = prometheus.NewRegistry()
 
@@ -18,6 +48,18 @@ var (
 			Help: "Total number of completed check iterations since pint start",
 		},
 	)
+	partialIterationsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pint_check_partial_iterations_total",
+			Help: "Total number of completed check iterations triggered by file changes since pint start",
+		},
+	)
+	alertmanagerErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pint_alertmanager_errors_total",
+			Help: "Total number of failed attempts to send alerts to Alertmanager",
+		},
+	)
 	checkIterationChecks = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "pint_last_run_checks",
@@ -56,4 +98,65 @@ var (
 		},
 		[]string{"kind"},
 	)
+	seriesPlanMetricsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pint_series_plan_metrics_total",
+			Help: "Total number of metrics checked using batched series queries",
+		},
+	)
+	seriesPlanHitsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pint_series_plan_hits_total",
+			Help: "Total number of promql/series queries avoided thanks to batched series queries",
+		},
+	)
+	discoveryErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_discovery_errors_total",
+			Help: "Total number of failed Prometheus server discovery runs",
+		},
+	)
+	lastDiscoveryTime = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_prometheus_discovery_last_success_time_seconds",
+			Help: "Last successful Prometheus server discovery time since unix epoch in seconds",
+		},
+	)
+	discoveredServers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_prometheus_servers",
+			Help: "Number of configured Prometheus servers, including discovered ones",
+		},
+	)
+	configReloadSuccess = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_config_last_reload_successful",
+			Help: "Whether the last configuration reload attempt was successful",
+		},
+	)
+	configReloadTime = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_config_last_reload_timestamp_seconds",
+			Help: "Timestamp of the last successful configuration reload",
+		},
+	)
+	serveRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_serve_requests_total",
+			Help: "Total number of lint requests handled by pint serve",
+		},
+		[]string{"code"},
+	)
+	serveRequestDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name: "pint_serve_request_duration_seconds",
+			Help: "How long did a lint request took to complete",
+		},
+	)
+	serveRequestsInFlight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_serve_requests_in_flight",
+			Help: "The number of lint requests currently being processed",
+		},
+	)
 )
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+const metricsFileFlag = "metrics-file"
+
+// writeMetricsFile writes all metrics pint watch would export after a single
+// run, so they can be collected by node_exporter textfile collector or
+// uploaded to a Pushgateway.
+func writeMetricsFile(path string, cfg config.Config, entries []discovery.Entry, summary reporter.Summary, minSeverity checks.Severity) error {
+	collector := newProblemCollector(cfg, nil, false, nil, minSeverity, 0)
+	collector.update(entries, summary)
+
+	metricsRegistry.MustRegister(collector)
+	registerRunMetrics()
+	checkIterationsTotal.Inc()
+
+	if err := prometheus.WriteToTextfile(path, metricsRegistry); err != nil {
+		return fmt.Errorf("failed to write metrics to %s: %w", path, err)
+	}
+	slog.Info("Metrics written to a file", slog.String("path", path))
+
+	return nil
+}
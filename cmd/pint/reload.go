@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/urfave/cli/v2"
+
+	"github.com/cloudflare/pint/internal/config"
+)
+
+const configReloadDelay = time.Second
+
+// configReloader loads the config file again when pint receives SIGHUP
+// or when the config file is modified.
+// Reload requests are only queued here, the new config is applied by
+// the background worker between checks runs.
+type configReloader struct {
+	load    func() (config.Config, error)
+	path    string
+	watcher *fsnotify.Watcher
+	signals chan os.Signal
+	reload  chan struct{}
+}
+
+func newConfigReloader(c *cli.Context, isOffline bool) *configReloader {
+	cr := configReloader{
+		load: func() (config.Config, error) {
+			return loadConfig(c, isOffline)
+		},
+		path:    c.Path(configFlag),
+		signals: make(chan os.Signal, 1),
+		reload:  make(chan struct{}, 1),
+	}
+	if abs, err := filepath.Abs(cr.path); err == nil {
+		cr.path = abs
+	}
+	configReloadSuccess.Set(1)
+	configReloadTime.SetToCurrentTime()
+	return &cr
+}
+
+func (cr *configReloader) start(ctx context.Context) {
+	signal.Notify(cr.signals, syscall.SIGHUP)
+
+	var err error
+	cr.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		slog.Error("Failed to create a file watcher, config will only be reloaded on SIGHUP", slog.Any("err", err))
+	} else if err = cr.watcher.Add(filepath.Dir(cr.path)); err != nil {
+		// Watch the parent directory since editors and config management tools
+		// often replace the file instead of modifying it.
+		slog.Error("Failed to watch config file for changes, config will only be reloaded on SIGHUP", slog.Any("err", err), slog.String("path", cr.path))
+		_ = cr.watcher.Close()
+		cr.watcher = nil
+	}
+
+	var events chan fsnotify.Event
+	var errs chan error
+	if cr.watcher != nil {
+		events = cr.watcher.Events
+		errs = cr.watcher.Errors
+	}
+
+	go func() {
+		// Writing a file usually generates multiple events and the file might
+		// be empty after the first one, so wait until all writes are done.
+		var settled <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-cr.signals:
+				slog.Info("Got SIGHUP, reloading config")
+				cr.request()
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) != cr.path || ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				settled = time.After(configReloadDelay)
+			case <-settled:
+				settled = nil
+				slog.Info("Config file changed, reloading config", slog.String("path", cr.path))
+				cr.request()
+			case err, ok := <-errs:
+				if !ok {
+					return
+				}
+				slog.Error("File watcher returned an error", slog.Any("err", err))
+			}
+		}
+	}()
+}
+
+// request will queue a config reload, multiple requests received before
+// the background worker handles them are merged into a single reload.
+func (cr *configReloader) request() {
+	select {
+	case cr.reload <- struct{}{}:
+	default:
+	}
+}
+
+func (cr *configReloader) stop() {
+	signal.Stop(cr.signals)
+	if cr.watcher != nil {
+		_ = cr.watcher.Close()
+	}
+}
+
+// apply loads the config file and, if it's valid, swaps it in.
+// Prometheus servers are rebuilt only if their configuration changed.
+func (cr *configReloader) apply(gen *config.PrometheusGenerator, collector *problemCollector) bool {
+	cfg, err := cr.load()
+	if err != nil {
+		configReloadSuccess.Set(0)
+		slog.Error("Failed to reload config, keeping the old one", slog.Any("err", err))
+		return false
+	}
+	if err = gen.Reload(cfg); err != nil {
+		configReloadSuccess.Set(0)
+		slog.Error("Failed to reload Prometheus servers, keeping the old config", slog.Any("err", err))
+		return false
+	}
+	collector.setConfig(cfg)
+	configReloadSuccess.Set(1)
+	configReloadTime.SetToCurrentTime()
+	slog.Info("Config reloaded", slog.String("path", cr.path), slog.Int("prometheus", gen.Count()))
+	return true
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+)
+
+const (
+	maxRequestsFlag    = "max-requests"
+	requestTimeoutFlag = "request-timeout"
+	maxBodySizeFlag    = "max-body-size"
+
+	defaultServePath = "rules.yml"
+)
+
+var serveCmd = &cli.Command{
+	Name:   "serve",
+	Usage:  "Run HTTP server exposing an API for linting rule files",
+	Action: actionServe,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    listenFlag,
+			Aliases: []string{"s"},
+			Value:   ":8080",
+			Usage:   "Listen address for HTTP web server",
+		},
+		&cli.StringFlag{
+			Name:    pidfileFlag,
+			Aliases: []string{"p"},
+			Usage:   "Write pid file to this path",
+		},
+		&cli.IntFlag{
+			Name:    maxRequestsFlag,
+			Aliases: []string{"r"},
+			Value:   8,
+			Usage:   "Maximum number of lint requests processed at the same time, extra requests will be rejected",
+		},
+		&cli.DurationFlag{
+			Name:    requestTimeoutFlag,
+			Aliases: []string{"t"},
+			Value:   time.Minute,
+			Usage:   "Maximum time a single lint request can take",
+		},
+		&cli.Int64Flag{
+			Name:    maxBodySizeFlag,
+			Aliases: []string{"b"},
+			Value:   4 << 20,
+			Usage:   "Maximum size of the request body in bytes",
+		},
+	},
+}
+
+func actionServe(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Int(maxRequestsFlag) < 1 {
+		return fmt.Errorf("--%s flag must be > 0", maxRequestsFlag)
+	}
+	if c.Duration(requestTimeoutFlag) <= 0 {
+		return fmt.Errorf("--%s flag must be > 0", requestTimeoutFlag)
+	}
+	if c.Int64(maxBodySizeFlag) < 1 {
+		return fmt.Errorf("--%s flag must be > 0", maxBodySizeFlag)
+	}
+
+	pidfile := c.String(pidfileFlag)
+	if pidfile != "" {
+		if err = writePidfile(pidfile); err != nil {
+			return err
+		}
+		defer removePidfile(pidfile)
+	}
+
+	registerMetrics()
+	metricsRegistry.MustRegister(serveRequestsTotal)
+	metricsRegistry.MustRegister(serveRequestDuration)
+	metricsRegistry.MustRegister(serveRequestsInFlight)
+
+	ctx := context.WithValue(context.Background(), config.CommandKey, config.ServeCommand)
+
+	// Prometheus servers are shared by all requests, so they share
+	// query workers and the query cache.
+	gen := config.NewPrometheusGenerator(meta.cfg, metricsRegistry)
+	defer gen.Stop()
+
+	if err = gen.GenerateStatic(); err != nil {
+		return err
+	}
+	if meta.isOffline {
+		slog.Info("Offline mode, skipping Prometheus discovery")
+	} else {
+		if err = gen.GenerateDynamic(ctx); err != nil {
+			return err
+		}
+		slog.Debug("Generated all Prometheus servers", slog.Int("count", gen.Count()))
+	}
+
+	http.Handle("/metrics", newMetricsHandler())
+	http.Handle("/api/v1/lint", &lintHandler{
+		ctx:         ctx,
+		cfg:         meta.cfg,
+		gen:         gen,
+		workers:     meta.workers,
+		timeout:     c.Duration(requestTimeoutFlag),
+		maxBodySize: c.Int64(maxBodySizeFlag),
+		slots:       make(chan struct{}, c.Int(maxRequestsFlag)),
+	})
+
+	listen := c.String(listenFlag)
+	server := http.Server{
+		Addr:         listen,
+		ReadTimeout:  time.Second * 30,
+		WriteTimeout: c.Duration(requestTimeoutFlag) + time.Second*30,
+	}
+	go func() {
+		if httpErr := server.ListenAndServe(); !errors.Is(httpErr, http.ErrServerClosed) {
+			slog.Error("HTTP server returned an error", slog.Any("err", httpErr), slog.String("listen", listen))
+		}
+	}()
+	slog.Info("Started HTTP server", slog.String("address", listen))
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	slog.Info("Shutting down")
+
+	sctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err = server.Shutdown(sctx); err != nil {
+		slog.Error("HTTP server returned an error while shutting down", slog.Any("err", err))
+	}
+
+	return nil
+}
+
+type serveLines struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+type serveProblem struct {
+	Path     string     `json:"path"`
+	Owner    string     `json:"owner,omitempty"`
+	Rule     string     `json:"rule,omitempty"`
+	Reporter string     `json:"reporter"`
+	Text     string     `json:"text"`
+	Details  string     `json:"details,omitempty"`
+	Severity string     `json:"severity"`
+	Lines    serveLines `json:"lines"`
+}
+
+type serveResponse struct {
+	Error    string         `json:"error,omitempty"`
+	Problems []serveProblem `json:"problems"`
+}
+
+type lintHandler struct {
+	ctx         context.Context
+	gen         *config.PrometheusGenerator
+	slots       chan struct{}
+	cfg         config.Config
+	workers     int
+	timeout     time.Duration
+	maxBodySize int64
+}
+
+func (h *lintHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	serveRequestsInFlight.Inc()
+	defer serveRequestsInFlight.Dec()
+
+	code, resp := h.lint(r)
+
+	serveRequestsTotal.WithLabelValues(strconv.Itoa(code)).Inc()
+	serveRequestDuration.Observe(time.Since(start).Seconds())
+
+	if resp.Error != "" {
+		slog.Debug("Lint request failed", slog.Int("code", code), slog.String("err", resp.Error))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(resp); err != nil {
+		slog.Error("Failed to write lint response", slog.Any("err", err))
+	}
+}
+
+func (h *lintHandler) lint(r *http.Request) (int, serveResponse) {
+	resp := serveResponse{Problems: []serveProblem{}}
+
+	if r.Method != http.MethodPost {
+		resp.Error = fmt.Sprintf("%s method is not allowed, use POST", r.Method)
+		return http.StatusMethodNotAllowed, resp
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+		defer func() { <-h.slots }()
+	default:
+		resp.Error = "too many requests in progress, try again later"
+		return http.StatusTooManyRequests, resp
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, h.maxBodySize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			resp.Error = fmt.Sprintf("request body is too large, maximum size is %d bytes", maxErr.Limit)
+			return http.StatusRequestEntityTooLarge, resp
+		}
+		resp.Error = fmt.Sprintf("failed to read request body: %s", err)
+		return http.StatusBadRequest, resp
+	}
+
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		path = defaultServePath
+	}
+
+	finder := discovery.NewContentFinder(
+		[]discovery.File{{Path: path, Content: body}},
+		git.NewPathFilter(nil, nil, h.cfg.Parser.CompileRelaxed()),
+	)
+	entries, err := finder.Find()
+	if err != nil {
+		resp.Error = err.Error()
+		return http.StatusBadRequest, resp
+	}
+
+	ctx, cancel := context.WithTimeout(h.ctx, h.timeout)
+	defer cancel()
+	stop := context.AfterFunc(r.Context(), cancel)
+	defer stop()
+
+	// Requests can be handled concurrently, so don't update iteration gauges.
+	summary := scanEntries(ctx, h.workers, h.gen, h.cfg, entries, discardGauges())
+	if err = ctx.Err(); err != nil {
+		resp.Error = fmt.Sprintf("lint request was cancelled: %s", err)
+		return http.StatusGatewayTimeout, resp
+	}
+
+	for _, report := range summary.Reports() {
+		resp.Problems = append(resp.Problems, serveProblem{
+			Path:     report.ReportedPath,
+			Owner:    report.Owner,
+			Rule:     report.Rule.Name(),
+			Reporter: report.Problem.Reporter,
+			Text:     report.Problem.Text,
+			Details:  report.Problem.Details,
+			Severity: strings.ToLower(report.Problem.Severity.String()),
+			Lines: serveLines{
+				First: report.Problem.Lines.First,
+				Last:  report.Problem.Lines.Last,
+			},
+		})
+	}
+
+	return http.StatusOK, resp
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+const (
+	shardFlag      = "shard"
+	reportFileFlag = "report-file"
+)
+
+// shard selects a subset of files to run checks on, so checks can be
+// split across multiple pint processes, for example parallel CI jobs.
+// index is 1-based, zero value means that sharding is disabled.
+type shard struct {
+	index int
+	total int
+}
+
+func parseShard(s string) (sh shard, err error) {
+	if s == "" {
+		return sh, nil
+	}
+
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return sh, fmt.Errorf("invalid --%s value %q, expected N/M", shardFlag, s)
+	}
+	if sh.index, err = strconv.Atoi(parts[0]); err != nil {
+		return sh, fmt.Errorf("invalid --%s value %q, expected N/M", shardFlag, s)
+	}
+	if sh.total, err = strconv.Atoi(parts[1]); err != nil {
+		return sh, fmt.Errorf("invalid --%s value %q, expected N/M", shardFlag, s)
+	}
+	if sh.total < 1 || sh.index < 1 || sh.index > sh.total {
+		return sh, fmt.Errorf("invalid --%s value %q, N must be between 1 and M", shardFlag, s)
+	}
+	return sh, nil
+}
+
+func (sh shard) isEnabled() bool {
+	return sh.total > 0
+}
+
+// selectEntries returns source paths of all entries belonging to this shard.
+// Entries are assigned to shards using a hash of the reported path, so all
+// rules from the same file are always checked by the same shard.
+// It returns nil if sharding is disabled, which means that all entries are selected.
+func (sh shard) selectEntries(entries []discovery.Entry) map[string]struct{} {
+	if !sh.isEnabled() {
+		return nil
+	}
+
+	selected := map[string]struct{}{}
+	for _, entry := range entries {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(entry.ReportedPath))
+		if int(h.Sum32()%uint32(sh.total)) == sh.index-1 {
+			selected[entry.SourcePath] = struct{}{}
+		}
+	}
+	slog.Info(
+		"Sharding enabled, checking only a subset of files",
+		slog.Int("shard", sh.index),
+		slog.Int("shards", sh.total),
+		slog.Int("files", len(selected)),
+	)
+	return selected
+}
+
+// filterEntries returns all entries from selected files.
+func filterEntries(entries []discovery.Entry, selected map[string]struct{}) []discovery.Entry {
+	if selected == nil {
+		return entries
+	}
+	filtered := make([]discovery.Entry, 0, len(selected))
+	for _, entry := range entries {
+		if _, ok := selected[entry.SourcePath]; ok {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func writeReportFile(path string, summary reporter.Summary) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer f.Close()
+
+	if err = reporter.NewJSONReporter(f).Submit(summary); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	traceFileFlag = "trace-file"
+	traceOTLPFlag = "trace-otlp"
+)
+
+// stopTracing flushes all pending spans and closes all exporters.
+var stopTracing = func() error { return nil }
+
+// initTracing configures OpenTelemetry tracing if enabled by flags.
+// Spans can be written to a local file as JSON and/or exported via OTLP,
+// which is configured using standard OTEL_EXPORTER_OTLP_* env variables.
+func initTracing(c *cli.Context) error {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "pint"),
+			attribute.String("service.version", version),
+		)),
+	}
+	closers := []func() error{}
+
+	if path := c.Path(traceFileFlag); path != "" {
+		fd, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(fd))
+		if err != nil {
+			fd.Close()
+			return fmt.Errorf("failed to create trace file exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+		closers = append(closers, fd.Close)
+		slog.Info("Writing traces to a file", slog.String("path", path))
+	}
+
+	if c.Bool(traceOTLPFlag) {
+		exporter, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+		slog.Info("Exporting traces via OTLP")
+	}
+
+	if len(opts) == 1 {
+		return nil
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Error("Tracing error", slog.Any("err", err))
+	}))
+
+	stopTracing = func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+		errs := []error{tp.Shutdown(ctx)}
+		for _, closer := range closers {
+			errs = append(errs, closer())
+		}
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -30,11 +31,15 @@ import (
 )
 
 const (
-	intervalFlag    = "interval"
-	listenFlag      = "listen"
-	pidfileFlag     = "pidfile"
-	maxProblemsFlag = "max-problems"
-	minSeverityFlag = "min-severity"
+	intervalFlag          = "interval"
+	discoveryIntervalFlag = "discovery-interval"
+	listenFlag            = "listen"
+	pidfileFlag           = "pidfile"
+	maxProblemsFlag       = "max-problems"
+	minSeverityFlag       = "min-severity"
+	fileEventsFlag        = "file-events"
+	rulesAPIFlag          = "rules-api"
+	stateFileFlag         = "state-file"
 )
 
 var watchCmd = &cli.Command{
@@ -48,6 +53,21 @@ var watchCmd = &cli.Command{
 			Value:   time.Minute * 10,
 			Usage:   "How often to run all checks",
 		},
+		&cli.BoolFlag{
+			Name:  fileEventsFlag,
+			Value: false,
+			Usage: "Watch files for changes and re-run checks only for modified rules",
+		},
+		&cli.BoolFlag{
+			Name:  rulesAPIFlag,
+			Value: false,
+			Usage: "Check rules loaded by all Prometheus servers via /api/v1/rules instead of files",
+		},
+		&cli.DurationFlag{
+			Name:  discoveryIntervalFlag,
+			Value: time.Minute * 10,
+			Usage: "How often to re-run Prometheus server discovery",
+		},
 		&cli.StringFlag{
 			Name:    listenFlag,
 			Aliases: []string{"s"},
@@ -71,6 +91,10 @@ var watchCmd = &cli.Command{
 			Value:   strings.ToLower(checks.Bug.String()),
 			Usage:   "Set minimum severity for problems reported via metrics",
 		},
+		&cli.StringFlag{
+			Name:  stateFileFlag,
+			Usage: "Save the time each problem was first reported to this file, so it's preserved between restarts",
+		},
 	},
 }
 
@@ -81,10 +105,24 @@ func actionWatch(c *cli.Context) error {
 	}
 
 	paths := c.Args().Slice()
-	if len(paths) == 0 {
+	rulesAPI := c.Bool(rulesAPIFlag)
+	switch {
+	case rulesAPI && len(paths) > 0:
+		return fmt.Errorf("--%s cannot be used with file or directory arguments", rulesAPIFlag)
+	case rulesAPI && c.Bool(fileEventsFlag):
+		return fmt.Errorf("--%s cannot be used with --%s", rulesAPIFlag, fileEventsFlag)
+	case rulesAPI && meta.isOffline:
+		return fmt.Errorf("--%s cannot be used with --%s", rulesAPIFlag, offlineFlag)
+	case !rulesAPI && len(paths) == 0:
 		return fmt.Errorf("at least one file or directory required")
 	}
 
+	// File events always use clean paths, use the same form for all rules
+	// so paths of rules don't change after checking only modified files.
+	for i, path := range paths {
+		paths[i] = filepath.Clean(path)
+	}
+
 	minSeverity, err := checks.ParseSeverity(c.String(minSeverityFlag))
 	if err != nil {
 		return fmt.Errorf("invalid --%s value: %w", minSeverityFlag, err)
@@ -92,50 +130,33 @@ func actionWatch(c *cli.Context) error {
 
 	pidfile := c.String(pidfileFlag)
 	if pidfile != "" {
-		pid := os.Getpid()
-		err = os.WriteFile(pidfile, []byte(fmt.Sprintf("%d\n", pid)), 0o644)
-		if err != nil {
+		if err = writePidfile(pidfile); err != nil {
 			return err
 		}
-		slog.Info("Pidfile created", slog.String("path", pidfile))
-		defer func() {
-			pidErr := os.RemoveAll(pidfile)
-			if pidErr != nil {
-				slog.Error("Failed to remove pidfile", slog.Any("err", pidErr), slog.String("path", pidfile))
-			}
-			slog.Info("Pidfile removed", slog.String("path", pidfile))
-		}()
+		defer removePidfile(pidfile)
 	}
 
 	// start HTTP server for metrics
-	collector := newProblemCollector(meta.cfg, paths, minSeverity, c.Int(maxProblemsFlag))
+	tracker, err := newProblemTracker(c.String(stateFileFlag))
+	if err != nil {
+		return err
+	}
+	collector := newProblemCollector(meta.cfg, paths, rulesAPI, tracker, minSeverity, c.Int(maxProblemsFlag))
 	// register all metrics
 	metricsRegistry.MustRegister(collector)
-	metricsRegistry.MustRegister(checkDuration)
-	metricsRegistry.MustRegister(checkIterationsTotal)
-	metricsRegistry.MustRegister(checkIterationChecks)
-	metricsRegistry.MustRegister(checkIterationChecksDone)
-	metricsRegistry.MustRegister(pintVersion)
-	metricsRegistry.MustRegister(lastRunTime)
-	metricsRegistry.MustRegister(lastRunDuration)
-	metricsRegistry.MustRegister(rulesParsedTotal)
-	promapi.RegisterMetrics(metricsRegistry)
-
-	metricsRegistry.MustRegister(
-		collectors.NewGoCollector(),
-		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
-	)
-
-	// init metrics if needed
-	pintVersion.WithLabelValues(version).Set(1)
-	rulesParsedTotal.WithLabelValues(config.AlertingRuleType).Add(0)
-	rulesParsedTotal.WithLabelValues(config.RecordingRuleType).Add(0)
-	rulesParsedTotal.WithLabelValues(config.InvalidRuleType).Add(0)
-
-	http.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{
-		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
-		Timeout:  time.Second * 20,
-	}))
+	registerMetrics()
+	metricsRegistry.MustRegister(discoveryErrorsTotal)
+	metricsRegistry.MustRegister(lastDiscoveryTime)
+	metricsRegistry.MustRegister(discoveredServers)
+	metricsRegistry.MustRegister(configReloadSuccess)
+	metricsRegistry.MustRegister(configReloadTime)
+	metricsRegistry.MustRegister(partialIterationsTotal)
+	metricsRegistry.MustRegister(alertmanagerErrorsTotal)
+
+	http.Handle("/metrics", newMetricsHandler())
+	http.Handle("/api/v1/problems", collector.problemsHandler())
+	http.Handle("/api/v1/rules", collector.rulesHandler())
+	http.Handle("/", collector.pageHandler())
 	listen := c.String(listenFlag)
 	server := http.Server{
 		Addr:         listen,
@@ -151,15 +172,35 @@ func actionWatch(c *cli.Context) error {
 
 	interval := c.Duration(intervalFlag)
 
+	// Alerts are re-sent after every run, keep them active for a few runs
+	// in case sending fails, resolved alerts are sent with endsAt set to now.
+	collector.alertsTTL = interval * 4
+	collector.alertmanager = collector.newAlertmanager(meta.cfg.Alertmanager, nil)
+
 	gen := config.NewPrometheusGenerator(meta.cfg, metricsRegistry)
 	if err = gen.GenerateStatic(); err != nil {
 		return err
 	}
 
+	var files *fileWatcher
+	if c.Bool(fileEventsFlag) {
+		if files, err = newFileWatcher(paths); err != nil {
+			return fmt.Errorf("failed to start file watcher: %w", err)
+		}
+	}
+
 	// start timer to run every $interval
 	ack := make(chan bool, 1)
 	mainCtx, mainCancel := context.WithCancel(context.WithValue(context.Background(), config.CommandKey, config.WatchCommand))
-	stop := startTimer(mainCtx, meta.workers, meta.isOffline, gen, interval, ack, collector)
+	reloader := newConfigReloader(c, meta.isOffline)
+	reloader.start(mainCtx)
+	defer reloader.stop()
+	if files != nil {
+		files.start(mainCtx)
+		defer files.stop()
+	}
+
+	stop := startTimer(mainCtx, meta.workers, meta.isOffline, gen, interval, c.Duration(discoveryIntervalFlag), ack, collector, reloader, files)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -183,24 +224,114 @@ func actionWatch(c *cli.Context) error {
 	return nil
 }
 
-func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, interval time.Duration, ack chan bool, collector *problemCollector) chan bool {
+func registerMetrics() {
+	registerRunMetrics()
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// registerRunMetrics registers all metrics describing check runs.
+func registerRunMetrics() {
+	metricsRegistry.MustRegister(checkDuration)
+	metricsRegistry.MustRegister(checkIterationsTotal)
+	metricsRegistry.MustRegister(checkIterationChecks)
+	metricsRegistry.MustRegister(checkIterationChecksDone)
+	metricsRegistry.MustRegister(pintVersion)
+	metricsRegistry.MustRegister(lastRunTime)
+	metricsRegistry.MustRegister(lastRunDuration)
+	metricsRegistry.MustRegister(rulesParsedTotal)
+	metricsRegistry.MustRegister(seriesPlanMetricsTotal)
+	metricsRegistry.MustRegister(seriesPlanHitsTotal)
+	promapi.RegisterMetrics(metricsRegistry)
+
+	// init metrics if needed
+	pintVersion.WithLabelValues(version).Set(1)
+	rulesParsedTotal.WithLabelValues(config.AlertingRuleType).Add(0)
+	rulesParsedTotal.WithLabelValues(config.RecordingRuleType).Add(0)
+	rulesParsedTotal.WithLabelValues(config.InvalidRuleType).Add(0)
+}
+
+func newMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{
+		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+		Timeout:  time.Second * 20,
+	})
+}
+
+func writePidfile(pidfile string) error {
+	pid := os.Getpid()
+	if err := os.WriteFile(pidfile, []byte(fmt.Sprintf("%d\n", pid)), 0o644); err != nil {
+		return err
+	}
+	slog.Info("Pidfile created", slog.String("path", pidfile))
+	return nil
+}
+
+func removePidfile(pidfile string) {
+	if err := os.RemoveAll(pidfile); err != nil {
+		slog.Error("Failed to remove pidfile", slog.Any("err", err), slog.String("path", pidfile))
+	}
+	slog.Info("Pidfile removed", slog.String("path", pidfile))
+}
+
+func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, interval, discoveryInterval time.Duration, ack chan bool, collector *problemCollector, reloader *configReloader, files *fileWatcher) chan bool {
 	ticker := time.NewTicker(time.Second)
 	stop := make(chan bool, 1)
 	wasBootstrapped := false
+	var lastDiscovery time.Time
+
+	// Discovery and config reloads run in the same goroutine as checks, so
+	// servers are never stopped while checks are still sending queries.
+	run := func() {
+		if time.Since(lastDiscovery) >= discoveryInterval {
+			runDiscovery(ctx, isOffline, gen)
+			lastDiscovery = time.Now()
+		}
+		slog.Debug("Running checks")
+		if err := collector.scan(ctx, workers, gen); err != nil {
+			slog.Error("Got an error when running checks", slog.Any("err", err))
+		}
+		checkIterationsTotal.Inc()
+		collector.sendAlerts()
+	}
+
+	var fileEvents <-chan struct{}
+	if files != nil {
+		fileEvents = files.notify
+	}
 
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
-				slog.Debug("Running checks")
 				if !wasBootstrapped {
 					ticker.Reset(interval)
 					wasBootstrapped = true
 				}
-				if err := collector.scan(ctx, workers, isOffline, gen); err != nil {
-					slog.Error("Got an error when running checks", slog.Any("err", err))
+				run()
+			case <-reloader.reload:
+				if reloader.apply(gen, collector) && wasBootstrapped {
+					// Re-run discovery and checks so metrics reflect the new config.
+					lastDiscovery = time.Time{}
+					ticker.Reset(interval)
+					run()
+				}
+			case <-fileEvents:
+				changed := files.changes()
+				if !wasBootstrapped || len(changed) == 0 {
+					continue
 				}
-				checkIterationsTotal.Inc()
+				slog.Debug("Running checks for modified files", slog.Any("paths", changed))
+				if err := collector.scanChanged(ctx, workers, gen, changed); err != nil {
+					slog.Error("Got an error when running checks for modified files, running all checks", slog.Any("err", err))
+					ticker.Reset(interval)
+					run()
+					continue
+				}
+				partialIterationsTotal.Inc()
+				collector.sendAlerts()
 			case <-stop:
 				ticker.Stop()
 				slog.Info("Background worker finished")
@@ -209,11 +340,31 @@ func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.Pr
 			}
 		}
 	}()
-	slog.Info("Will continuously run checks until terminated", slog.String("interval", interval.String()))
+	slog.Info(
+		"Will continuously run checks until terminated",
+		slog.String("interval", interval.String()),
+		slog.String("discoveryInterval", discoveryInterval.String()),
+		slog.Bool("fileEvents", files != nil),
+	)
 
 	return stop
 }
 
+func runDiscovery(ctx context.Context, isOffline bool, gen *config.PrometheusGenerator) {
+	if isOffline {
+		slog.Info("Offline mode, skipping Prometheus discovery")
+		return
+	}
+	if err := gen.GenerateDynamic(ctx); err != nil {
+		discoveryErrorsTotal.Inc()
+		slog.Error("Got an error when running Prometheus discovery", slog.Any("err", err))
+		return
+	}
+	lastDiscoveryTime.SetToCurrentTime()
+	discoveredServers.Set(float64(gen.Count()))
+	slog.Debug("Generated all Prometheus servers", slog.Int("count", gen.Count()))
+}
+
 type problemCollector struct {
 	cfg              config.Config
 	fileOwners       map[string]string
@@ -221,21 +372,48 @@ type problemCollector struct {
 	problem          *prometheus.Desc
 	problems         *prometheus.Desc
 	fileOwnersMetric *prometheus.Desc
+	firstSeen        *prometheus.Desc
+	age              *prometheus.Desc
+	rules            *prometheus.Desc
+	checkedRules     *prometheus.Desc
+	onlineChecks     *prometheus.Desc
+	offlineChecks    *prometheus.Desc
+	tracker          *problemTracker
+	alertmanager     *reporter.AlertmanagerReporter
+	alertsTTL        time.Duration
 	paths            []string
+	rulesAPI         bool
+	entries          []discovery.Entry
+	lastRun          time.Time
 	minSeverity      checks.Severity
 	maxProblems      int
 	lock             sync.Mutex
 }
 
-func newProblemCollector(cfg config.Config, paths []string, minSeverity checks.Severity, maxProblems int) *problemCollector {
+func newProblemCollector(cfg config.Config, paths []string, rulesAPI bool, tracker *problemTracker, minSeverity checks.Severity, maxProblems int) *problemCollector {
+	problemLabels := []string{"filename", "kind", "name", "severity", "reporter", "problem", "owner"}
 	return &problemCollector{
 		cfg:        cfg,
 		paths:      paths,
+		rulesAPI:   rulesAPI,
+		tracker:    tracker,
 		fileOwners: map[string]string{},
 		problem: prometheus.NewDesc(
 			"pint_problem",
 			"Prometheus rule problem reported by pint",
-			[]string{"filename", "kind", "name", "severity", "reporter", "problem", "owner"},
+			problemLabels,
+			prometheus.Labels{},
+		),
+		firstSeen: prometheus.NewDesc(
+			"pint_problem_first_seen_timestamp_seconds",
+			"Timestamp of the first time given problem was reported by pint",
+			problemLabels,
+			prometheus.Labels{},
+		),
+		age: prometheus.NewDesc(
+			"pint_problem_age_seconds",
+			"Number of seconds since given problem was first reported by pint",
+			problemLabels,
 			prometheus.Labels{},
 		),
 		problems: prometheus.NewDesc(
@@ -250,28 +428,171 @@ func newProblemCollector(cfg config.Config, paths []string, minSeverity checks.S
 			[]string{"filename", "owner"},
 			prometheus.Labels{},
 		),
+		rules: prometheus.NewDesc(
+			"pint_last_run_rules",
+			"Number of rules found in the last run",
+			[]string{},
+			prometheus.Labels{},
+		),
+		checkedRules: prometheus.NewDesc(
+			"pint_last_run_checked_rules",
+			"Number of rules checked in the last run",
+			[]string{},
+			prometheus.Labels{},
+		),
+		onlineChecks: prometheus.NewDesc(
+			"pint_last_run_online_checks",
+			"Number of online checks run in the last run",
+			[]string{},
+			prometheus.Labels{},
+		),
+		offlineChecks: prometheus.NewDesc(
+			"pint_last_run_offline_checks",
+			"Number of offline checks run in the last run",
+			[]string{},
+			prometheus.Labels{},
+		),
 		minSeverity: minSeverity,
 		maxProblems: maxProblems,
 	}
 }
 
-func (c *problemCollector) scan(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator) error {
-	slog.Info("Finding all rules to check", slog.Any("paths", c.paths))
-	// nolint: contextcheck
-	entries, err := discovery.NewGlobFinder(c.paths, git.NewPathFilter(nil, nil, c.cfg.Parser.CompileRelaxed())).Find()
-	if err != nil {
-		return err
+func (c *problemCollector) setConfig(cfg config.Config) {
+	c.lock.Lock()
+	c.cfg = cfg
+	prev := c.alertmanager
+	c.alertmanager = c.newAlertmanager(cfg.Alertmanager, prev)
+	c.lock.Unlock()
+
+	if prev != nil && cfg.Alertmanager == nil {
+		// Alertmanager was removed from the config, resolve all alerts we've sent.
+		if err := prev.Submit(reporter.Summary{}); err != nil {
+			alertmanagerErrorsTotal.Inc()
+			slog.Error("Failed to send alerts to Alertmanager", slog.Any("err", err))
+		}
 	}
+}
 
-	s, err := checkRules(ctx, workers, isOffline, gen, c.cfg, entries)
+// newAlertmanager returns a reporter for given Alertmanager config, or nil
+// if it's not configured. Alerts sent by the previous reporter are kept, so
+// they can be resolved after a config reload.
+func (c *problemCollector) newAlertmanager(cfg *config.Alertmanager, prev *reporter.AlertmanagerReporter) *reporter.AlertmanagerReporter {
+	if cfg == nil {
+		return nil
+	}
+	slog.Info("Will send problems as alerts to Alertmanager", slog.String("uri", cfg.URI))
+	am := reporter.NewAlertmanagerReporter(cfg.URI, cfg.TimeoutDuration(), cfg.Headers, cfg.Labels, c.minSeverity, c.alertsTTL)
+	if prev != nil {
+		am.ResumeFrom(prev)
+	}
+	return am
+}
+
+func (c *problemCollector) scan(ctx context.Context, workers int, gen *config.PrometheusGenerator) error {
+	var entries []discovery.Entry
+	var err error
+	filter := git.NewPathFilter(nil, nil, c.cfg.Parser.CompileRelaxed())
+	if c.rulesAPI {
+		slog.Info("Finding all rules loaded by Prometheus servers", slog.Int("servers", gen.Count()))
+		entries, err = discovery.NewRulesAPIFinder(ctx, gen.Servers(), filter).Find()
+	} else {
+		slog.Info("Finding all rules to check", slog.Any("paths", c.paths))
+		// nolint: contextcheck
+		entries, err = discovery.NewGlobFinder(c.paths, filter).Find()
+	}
 	if err != nil {
 		return err
 	}
 
+	s := scanEntries(ctx, workers, gen, c.cfg, entries, iterationGauges())
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	c.update(entries, s)
+
+	return nil
+}
+
+// scanChanged re-runs checks only for rules from changed files and rules
+// from other files that depend on them, then merges the results with
+// problems found by previous runs.
+func (c *problemCollector) scanChanged(ctx context.Context, workers int, gen *config.PrometheusGenerator, changed []string) error {
+	c.lock.Lock()
+	cfg := c.cfg
+	oldEntries := c.entries
+	oldSummary := c.summary
+	c.lock.Unlock()
+
+	if oldSummary == nil {
+		return c.scan(ctx, workers, gen)
+	}
+
+	var fresh []discovery.Entry
+	if files := changedFiles(changed); len(files) > 0 {
+		slog.Info("Finding rules in changed files", slog.Any("paths", files))
+		var err error
+		// nolint: contextcheck
+		fresh, err = discovery.NewGlobFinder(files, git.NewPathFilter(nil, nil, cfg.Parser.CompileRelaxed())).Find()
+		if err != nil {
+			return err
+		}
+	}
+
+	updated := make([]discovery.Entry, 0, len(oldEntries)+len(fresh))
+	modified := make([]discovery.Entry, 0, len(fresh))
+	for _, entry := range oldEntries {
+		if isPathChanged(entry.SourcePath, changed) {
+			modified = append(modified, entry)
+			continue
+		}
+		updated = append(updated, entry)
+	}
+	modified = append(modified, fresh...)
+
+	selected := findDependentPaths(modified, updated)
+	for _, entry := range fresh {
+		selected[entry.SourcePath] = struct{}{}
+	}
+	updated = append(updated, fresh...)
+
+	slog.Debug("Running checks for changed files", slog.Int("files", len(selected)))
+	s := scanSelectedEntries(ctx, workers, gen, cfg, updated, selected, iterationGauges())
+
+	reports := s.Reports()
+	for _, report := range oldSummary.Reports() {
+		if isPathChanged(report.SourcePath, changed) {
+			continue
+		}
+		if _, ok := selected[report.SourcePath]; ok {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	merged := reporter.NewSummary(reports)
+	merged.SortReports()
+	merged.Duration = s.Duration
+	merged.TotalEntries = len(updated)
+	merged.CheckedEntries = s.CheckedEntries
+	merged.OnlineChecks = s.OnlineChecks
+	merged.OfflineChecks = s.OfflineChecks
+	merged.TruncatedChecks = s.TruncatedChecks
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.update(updated, merged)
+
+	return nil
+}
+
+func (c *problemCollector) update(entries []discovery.Entry, s reporter.Summary) {
+	c.entries = entries
 	c.summary = &s
+	c.lastRun = time.Now()
+	if c.tracker != nil {
+		c.tracker.update(s.Reports(), c.minSeverity, c.lastRun)
+	}
 
 	fileOwners := map[string]string{}
 	for _, entry := range entries {
@@ -280,8 +601,24 @@ func (c *problemCollector) scan(ctx context.Context, workers int, isOffline bool
 		}
 	}
 	c.fileOwners = fileOwners
+}
 
-	return nil
+// sendAlerts sends all problems found by the last run to Alertmanager,
+// if it's configured.
+func (c *problemCollector) sendAlerts() {
+	c.lock.Lock()
+	summary := c.summary
+	am := c.alertmanager
+	c.lock.Unlock()
+
+	if am == nil || summary == nil {
+		return
+	}
+
+	if err := am.Submit(*summary); err != nil {
+		alertmanagerErrorsTotal.Inc()
+		slog.Error("Failed to send alerts to Alertmanager", slog.Any("err", err))
+	}
 }
 
 func (c *problemCollector) Describe(ch chan<- *prometheus.Desc) {
@@ -300,7 +637,13 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(c.fileOwnersMetric, prometheus.GaugeValue, 1, filename, owner)
 	}
 
+	ch <- prometheus.MustNewConstMetric(c.rules, prometheus.GaugeValue, float64(c.summary.TotalEntries))
+	ch <- prometheus.MustNewConstMetric(c.checkedRules, prometheus.GaugeValue, float64(c.summary.CheckedEntries))
+	ch <- prometheus.MustNewConstMetric(c.onlineChecks, prometheus.GaugeValue, float64(c.summary.OnlineChecks))
+	ch <- prometheus.MustNewConstMetric(c.offlineChecks, prometheus.GaugeValue, float64(c.summary.OfflineChecks))
+
 	done := map[string]prometheus.Metric{}
+	tracked := map[string]trackedProblem{}
 	keys := []string{}
 
 	for _, report := range c.summary.Reports() {
@@ -309,27 +652,12 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 			continue
 		}
 
-		kind := "invalid"
-		name := "unknown"
-		if report.Rule.AlertingRule != nil {
-			kind = "alerting"
-			name = report.Rule.AlertingRule.Alert.Value
-		}
-		if report.Rule.RecordingRule != nil {
-			kind = "recording"
-			name = report.Rule.RecordingRule.Record.Value
-		}
+		tp := newTrackedProblem(report)
 		metric := prometheus.MustNewConstMetric(
 			c.problem,
 			prometheus.GaugeValue,
 			1,
-			report.SourcePath,
-			kind,
-			name,
-			strings.ToLower(report.Problem.Severity.String()),
-			report.Problem.Reporter,
-			report.Problem.Text,
-			report.Owner,
+			tp.labelValues()...,
 		)
 
 		var out dto.Metric
@@ -342,6 +670,7 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 		key := out.String()
 		if _, ok := done[key]; !ok {
 			done[key] = metric
+			tracked[key] = tp
 			keys = append(keys, key)
 		}
 	}
@@ -349,9 +678,16 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(c.problems, prometheus.GaugeValue, float64(len(done)))
 
 	sort.Strings(keys)
+	now := time.Now()
 	var reported int
 	for _, key := range keys {
 		ch <- done[key]
+		if c.tracker != nil {
+			if firstSeen, ok := c.tracker.firstSeen(tracked[key]); ok {
+				ch <- prometheus.MustNewConstMetric(c.firstSeen, prometheus.GaugeValue, float64(firstSeen.Unix()), tracked[key].labelValues()...)
+				ch <- prometheus.MustNewConstMetric(c.age, prometheus.GaugeValue, now.Sub(firstSeen).Seconds(), tracked[key].labelValues()...)
+			}
+		}
 		reported++
 		if c.maxProblems > 0 && reported >= c.maxProblems {
 			break
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/russross/blackfriday/v2"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+type watchProblemsResponse struct {
+	Error    string         `json:"error,omitempty"`
+	Problems []serveProblem `json:"problems"`
+}
+
+type watchRule struct {
+	Path     string     `json:"path"`
+	Owner    string     `json:"owner,omitempty"`
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Lines    serveLines `json:"lines"`
+	Problems int        `json:"problems"`
+}
+
+type watchRulesResponse struct {
+	Rules []watchRule `json:"rules"`
+}
+
+// problemFilter selects problems using query parameters.
+// Every parameter can be passed multiple times and will match any of the values.
+type problemFilter struct {
+	owners      []string
+	paths       []string
+	reporters   []string
+	minSeverity checks.Severity
+}
+
+func newProblemFilter(args url.Values, minSeverity checks.Severity) (pf problemFilter, err error) {
+	pf.owners = args["owner"]
+	pf.paths = args["path"]
+	pf.reporters = args["reporter"]
+	pf.minSeverity = minSeverity
+	if s := args.Get("severity"); s != "" {
+		if pf.minSeverity, err = checks.ParseSeverity(s); err != nil {
+			return pf, err
+		}
+	}
+	return pf, nil
+}
+
+func (pf problemFilter) isMatch(report reporter.Report) bool {
+	if report.Problem.Severity < pf.minSeverity {
+		return false
+	}
+	if len(pf.owners) > 0 && !slices.Contains(pf.owners, report.Owner) {
+		return false
+	}
+	if len(pf.paths) > 0 && !slices.Contains(pf.paths, report.SourcePath) {
+		return false
+	}
+	if len(pf.reporters) > 0 && !slices.Contains(pf.reporters, report.Problem.Reporter) {
+		return false
+	}
+	return true
+}
+
+func newWatchProblem(report reporter.Report) serveProblem {
+	return serveProblem{
+		Path:     report.SourcePath,
+		Owner:    report.Owner,
+		Rule:     report.Rule.Name(),
+		Reporter: report.Problem.Reporter,
+		Text:     report.Problem.Text,
+		Details:  report.Problem.Details,
+		Severity: strings.ToLower(report.Problem.Severity.String()),
+		Lines: serveLines{
+			First: report.Problem.Lines.First,
+			Last:  report.Problem.Lines.Last,
+		},
+	}
+}
+
+// reports returns a copy of all reports from the last run.
+func (c *problemCollector) reports() (reports []reporter.Report, lastRun time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.summary == nil {
+		return nil, lastRun
+	}
+	return slices.Clone(c.summary.Reports()), c.lastRun
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		slog.Error("Failed to write API response", slog.Any("err", err))
+	}
+}
+
+func (c *problemCollector) problemsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := watchProblemsResponse{Problems: []serveProblem{}}
+
+		pf, err := newProblemFilter(r.URL.Query(), c.minSeverity)
+		if err != nil {
+			resp.Error = fmt.Sprintf("invalid severity filter: %s", err)
+			writeJSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		reports, _ := c.reports()
+		for _, report := range reports {
+			if pf.isMatch(report) {
+				resp.Problems = append(resp.Problems, newWatchProblem(report))
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+func (c *problemCollector) rulesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		c.lock.Lock()
+		entries := c.entries
+		var reports []reporter.Report
+		if c.summary != nil {
+			reports = c.summary.Reports()
+		}
+		c.lock.Unlock()
+
+		// Count problems per rule once, so we don't need to check every
+		// report for every rule.
+		type ruleKey struct {
+			path  string
+			lines parser.LineRange
+		}
+		problems := map[ruleKey]int{}
+		for _, report := range reports {
+			if report.Problem.Severity >= c.minSeverity {
+				problems[ruleKey{path: report.SourcePath, lines: report.Rule.Lines}]++
+			}
+		}
+
+		resp := watchRulesResponse{Rules: []watchRule{}}
+		for _, entry := range entries {
+			if entry.PathError != nil {
+				continue
+			}
+			rule := watchRule{
+				Path:  entry.SourcePath,
+				Owner: entry.Owner,
+				Name:  entry.Rule.Name(),
+				Type:  string(entry.Rule.Type()),
+				Lines: serveLines{
+					First: entry.Rule.Lines.First,
+					Last:  entry.Rule.Lines.Last,
+				},
+				Problems: problems[ruleKey{path: entry.SourcePath, lines: entry.Rule.Lines}],
+			}
+			resp.Rules = append(resp.Rules, rule)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+var markdownRenderer = blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+	// Problem details can include rule content, so never render any raw HTML from it.
+	Flags: blackfriday.SkipHTML | blackfriday.Safelink | blackfriday.NofollowLinks | blackfriday.NoreferrerLinks | blackfriday.HrefTargetBlank,
+})
+
+func renderMarkdown(s string) template.HTML {
+	// nolint: gosec
+	return template.HTML(blackfriday.Run(
+		[]byte(s),
+		blackfriday.WithRenderer(markdownRenderer),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions),
+	))
+}
+
+type problemsPageOwner struct {
+	Name     string
+	Problems []serveProblem
+}
+
+type problemsPage struct {
+	LastRun time.Time
+	Owners  []problemsPageOwner
+	Total   int
+}
+
+var problemsPageTemplate = template.Must(template.New("problems").Funcs(template.FuncMap{
+	"markdown": renderMarkdown,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>pint problems</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 0.4em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+td p { margin: 0 0 0.4em 0; }
+.fatal, .bug { color: #b00; }
+.warning { color: #b60; }
+.information { color: #666; }
+</style>
+</head>
+<body>
+<h1>pint problems</h1>
+{{- if .LastRun.IsZero }}
+<p>Checks didn't finish yet.</p>
+{{- else }}
+<p>Found {{ .Total }} problem(s), last checks run finished at {{ .LastRun.UTC.Format "2006-01-02 15:04:05 UTC" }}.</p>
+{{- end }}
+{{- range .Owners }}
+<h2>{{ if .Name }}{{ .Name }}{{ else }}No owner{{ end }}</h2>
+<table>
+<tr><th>Path</th><th>Rule</th><th>Severity</th><th>Reporter</th><th>Problem</th></tr>
+{{- range .Problems }}
+<tr>
+<td>{{ .Path }}:{{ .Lines.First }}{{ if ne .Lines.First .Lines.Last }}-{{ .Lines.Last }}{{ end }}</td>
+<td>{{ .Rule }}</td>
+<td class="{{ .Severity }}">{{ .Severity }}</td>
+<td>{{ .Reporter }}</td>
+<td>{{ markdown .Text }}{{ if .Details }}{{ markdown .Details }}{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+func (c *problemCollector) pageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		pf, err := newProblemFilter(r.URL.Query(), c.minSeverity)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid severity filter: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		reports, lastRun := c.reports()
+		page := problemsPage{LastRun: lastRun}
+		byOwner := map[string][]serveProblem{}
+		for _, report := range reports {
+			if !pf.isMatch(report) {
+				continue
+			}
+			byOwner[report.Owner] = append(byOwner[report.Owner], newWatchProblem(report))
+			page.Total++
+		}
+		for name, problems := range byOwner {
+			page.Owners = append(page.Owners, problemsPageOwner{Name: name, Problems: problems})
+		}
+		// Problems without any owner are listed last.
+		sort.Slice(page.Owners, func(i, j int) bool {
+			if page.Owners[i].Name == "" || page.Owners[j].Name == "" {
+				return page.Owners[j].Name == ""
+			}
+			return page.Owners[i].Name < page.Owners[j].Name
+		})
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err = problemsPageTemplate.Execute(w, page); err != nil {
+			slog.Error("Failed to render problems page", slog.Any("err", err))
+		}
+	})
+}
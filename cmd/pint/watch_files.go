@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser/utils"
+)
+
+const fileEventsDelay = time.Second
+
+// fileWatcher uses inotify to track changes to all files and directories
+// matching paths passed to pint watch.
+// Changed paths are collected until there are no new events for fileEventsDelay,
+// then the background worker is notified and can read them all using changes().
+type fileWatcher struct {
+	watcher  *fsnotify.Watcher
+	notify   chan struct{}
+	pending  map[string]struct{}
+	patterns []string
+	roots    []string
+	mu       sync.Mutex
+}
+
+func newFileWatcher(patterns []string) (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	fw := fileWatcher{
+		watcher:  watcher,
+		notify:   make(chan struct{}, 1),
+		pending:  map[string]struct{}{},
+		patterns: make([]string, 0, len(patterns)),
+	}
+
+	for _, pattern := range patterns {
+		// Event paths are always clean, patterns must be too or they won't match.
+		pattern = filepath.Clean(pattern)
+		fw.patterns = append(fw.patterns, pattern)
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			_ = watcher.Close()
+			return nil, err
+		}
+		for _, path := range matches {
+			if isDir(path) {
+				fw.roots = append(fw.roots, filepath.Clean(path))
+				fw.addDir(path)
+			} else {
+				// Watch the parent directory so we see files being replaced.
+				fw.addWatch(filepath.Dir(path))
+			}
+		}
+	}
+
+	return &fw, nil
+}
+
+func (fw *fileWatcher) addWatch(dir string) {
+	if err := fw.watcher.Add(dir); err != nil {
+		slog.Error("Failed to watch directory for changes", slog.Any("err", err), slog.String("path", dir))
+		return
+	}
+	slog.Debug("Watching directory for changes", slog.String("path", dir))
+}
+
+func (fw *fileWatcher) addDir(root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			fw.addWatch(path)
+		}
+		return nil
+	})
+}
+
+// isWatched returns true if given path was passed to pint watch or is inside
+// a directory that was.
+func (fw *fileWatcher) isWatched(path string) bool {
+	for _, root := range fw.roots {
+		if strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	for _, pattern := range fw.patterns {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (fw *fileWatcher) start(ctx context.Context) {
+	go func() {
+		var settled <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-fw.watcher.Events:
+				if !ok {
+					return
+				}
+				path := filepath.Clean(ev.Name)
+				if !fw.isWatched(path) {
+					continue
+				}
+				if ev.Has(fsnotify.Chmod) && ev.Op == fsnotify.Chmod {
+					continue
+				}
+				if ev.Has(fsnotify.Create) && isDir(path) {
+					fw.addDir(path)
+				}
+				slog.Debug("File changed", slog.String("path", path), slog.String("op", ev.Op.String()))
+				fw.mu.Lock()
+				fw.pending[path] = struct{}{}
+				fw.mu.Unlock()
+				settled = time.After(fileEventsDelay)
+			case <-settled:
+				settled = nil
+				select {
+				case fw.notify <- struct{}{}:
+				default:
+				}
+			case err, ok := <-fw.watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("File watcher returned an error", slog.Any("err", err))
+			}
+		}
+	}()
+}
+
+// changes returns all paths modified since the last call.
+func (fw *fileWatcher) changes() []string {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	paths := make([]string, 0, len(fw.pending))
+	for path := range fw.pending {
+		paths = append(paths, path)
+	}
+	fw.pending = map[string]struct{}{}
+	slices.Sort(paths)
+	return paths
+}
+
+func (fw *fileWatcher) stop() {
+	_ = fw.watcher.Close()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+// isPathChanged returns true if path is one of changed paths or is inside
+// a changed directory.
+func isPathChanged(path string, changed []string) bool {
+	for _, c := range changed {
+		if path == c || strings.HasPrefix(path, c+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedFiles returns all files that exist at any of changed paths.
+func changedFiles(changed []string) (files []string) {
+	for _, path := range changed {
+		_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			files = append(files, p)
+			return nil
+		})
+	}
+	return files
+}
+
+// findDependentPaths returns paths of all files with rules that might report
+// different problems after rules in changed entries were modified.
+// These are rules using metrics produced by changed recording rules
+// (rule/dependency) and other recording rules producing the same metrics
+// (rule/duplicate).
+func findDependentPaths(changed, entries []discovery.Entry) map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, entry := range changed {
+		if entry.Rule.RecordingRule != nil {
+			names[entry.Rule.RecordingRule.Record.Value] = struct{}{}
+		}
+	}
+
+	paths := map[string]struct{}{}
+	if len(names) == 0 {
+		return paths
+	}
+	for _, entry := range entries {
+		if entry.PathError != nil || entry.Rule.Error.Err != nil {
+			continue
+		}
+		if entry.Rule.RecordingRule != nil {
+			if _, ok := names[entry.Rule.RecordingRule.Record.Value]; ok {
+				paths[entry.SourcePath] = struct{}{}
+				continue
+			}
+		}
+		expr := entry.Rule.Expr()
+		if expr.SyntaxError != nil {
+			continue
+		}
+		for _, vs := range utils.HasVectorSelector(expr.Query) {
+			if _, ok := names[vs.Name]; ok {
+				paths[entry.SourcePath] = struct{}{}
+				break
+			}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+// trackedProblem holds all pint_problem labels for a problem and the time
+// it was first reported.
+type trackedProblem struct {
+	FirstSeen time.Time `json:"firstSeen"`
+	Filename  string    `json:"filename"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Severity  string    `json:"severity"`
+	Reporter  string    `json:"reporter"`
+	Problem   string    `json:"problem"`
+	Owner     string    `json:"owner"`
+}
+
+func newTrackedProblem(report reporter.Report) trackedProblem {
+	kind := "invalid"
+	name := "unknown"
+	if report.Rule.AlertingRule != nil {
+		kind = "alerting"
+		name = report.Rule.AlertingRule.Alert.Value
+	}
+	if report.Rule.RecordingRule != nil {
+		kind = "recording"
+		name = report.Rule.RecordingRule.Record.Value
+	}
+	return trackedProblem{
+		Filename: report.SourcePath,
+		Kind:     kind,
+		Name:     name,
+		Severity: strings.ToLower(report.Problem.Severity.String()),
+		Reporter: report.Problem.Reporter,
+		Problem:  report.Problem.Text,
+		Owner:    report.Owner,
+	}
+}
+
+// labelValues returns values for all pint_problem labels.
+func (tp trackedProblem) labelValues() []string {
+	return []string{tp.Filename, tp.Kind, tp.Name, tp.Severity, tp.Reporter, tp.Problem, tp.Owner}
+}
+
+func (tp trackedProblem) fingerprint() string {
+	return strings.Join(tp.labelValues(), "\xff")
+}
+
+type problemState struct {
+	Problems []trackedProblem `json:"problems"`
+}
+
+// problemTracker remembers when each problem was first reported and
+// optionally saves it to a file, so it's preserved between restarts.
+type problemTracker struct {
+	problems map[string]trackedProblem
+	path     string
+}
+
+func newProblemTracker(path string) (*problemTracker, error) {
+	pt := problemTracker{
+		path:     path,
+		problems: map[string]trackedProblem{},
+	}
+	if path == "" {
+		return &pt, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Info("Problem state file doesn't exist yet", slog.String("path", path))
+		return &pt, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read problem state file: %w", err)
+	}
+
+	var state problemState
+	if err = json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse problem state file %s: %w", path, err)
+	}
+	for _, tp := range state.Problems {
+		pt.problems[tp.fingerprint()] = tp
+	}
+	slog.Info("Loaded problem state file", slog.String("path", path), slog.Int("problems", len(pt.problems)))
+
+	return &pt, nil
+}
+
+// incompleteCheck identifies a check that didn't complete for a file.
+type incompleteCheck struct {
+	path     string
+	reporter string
+}
+
+// update compares reported problems with tracked problems, logs all problems
+// that appeared or were resolved, and saves the state file.
+// Problems from checks that didn't complete, because they were cancelled or
+// a Prometheus query failed, are kept, since they might not be resolved.
+func (pt *problemTracker) update(reports []reporter.Report, minSeverity checks.Severity, now time.Time) {
+	incomplete := map[incompleteCheck]struct{}{}
+	current := map[string]trackedProblem{}
+	for _, report := range reports {
+		if report.Problem.Incomplete {
+			incomplete[incompleteCheck{path: report.SourcePath, reporter: report.Problem.Reporter}] = struct{}{}
+		}
+		if report.Problem.Severity < minSeverity {
+			continue
+		}
+		tp := newTrackedProblem(report)
+		fp := tp.fingerprint()
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		if old, ok := pt.problems[fp]; ok {
+			tp.FirstSeen = old.FirstSeen
+		} else {
+			tp.FirstSeen = now
+			slog.Info("Problem detected",
+				slog.String("path", tp.Filename),
+				slog.String("name", tp.Name),
+				slog.String("reporter", tp.Reporter),
+				slog.String("severity", tp.Severity),
+				slog.String("owner", tp.Owner),
+				slog.String("problem", tp.Problem),
+			)
+		}
+		current[fp] = tp
+	}
+
+	for fp, tp := range pt.problems {
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		if _, ok := incomplete[incompleteCheck{path: tp.Filename, reporter: tp.Reporter}]; ok {
+			current[fp] = tp
+			continue
+		}
+		slog.Info("Problem resolved",
+			slog.String("path", tp.Filename),
+			slog.String("name", tp.Name),
+			slog.String("reporter", tp.Reporter),
+			slog.String("severity", tp.Severity),
+			slog.String("owner", tp.Owner),
+			slog.String("problem", tp.Problem),
+			slog.String("age", now.Sub(tp.FirstSeen).Round(time.Second).String()),
+		)
+	}
+
+	pt.problems = current
+
+	if err := pt.save(); err != nil {
+		slog.Error("Failed to save problem state file", slog.String("path", pt.path), slog.Any("err", err))
+	}
+}
+
+func (pt *problemTracker) firstSeen(tp trackedProblem) (time.Time, bool) {
+	old, ok := pt.problems[tp.fingerprint()]
+	return old.FirstSeen, ok
+}
+
+// save writes the state file to a temporary file first and then renames it,
+// so it's never left half written.
+func (pt *problemTracker) save() error {
+	if pt.path == "" {
+		return nil
+	}
+
+	state := problemState{Problems: make([]trackedProblem, 0, len(pt.problems))}
+	for _, tp := range pt.problems {
+		state.Problems = append(state.Problems, tp)
+	}
+	sortTrackedProblems(state.Problems)
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(pt.path), filepath.Base(pt.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), pt.path)
+}
+
+func sortTrackedProblems(problems []trackedProblem) {
+	slices.SortFunc(problems, func(a, b trackedProblem) int {
+		return strings.Compare(a.fingerprint(), b.fingerprint())
+	})
+}
@@ -74,10 +74,11 @@ func (c AlertsCheck) Check(ctx context.Context, _ string, rule parser.Rule, _ []
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 		problems = append(problems, Problem{
-			Lines:    rule.AlertingRule.Expr.Value.Lines,
-			Reporter: c.Reporter(),
-			Text:     text,
-			Severity: severity,
+			Lines:      rule.AlertingRule.Expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
 		})
 		return problems
 	}
@@ -56,10 +56,11 @@ func (c AlertsExternalLabelsCheck) Check(ctx context.Context, _ string, rule par
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 		problems = append(problems, Problem{
-			Lines:    rule.Lines,
-			Reporter: c.Reporter(),
-			Text:     text,
-			Severity: severity,
+			Lines:      rule.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
 		})
 		return problems
 	}
@@ -62,9 +62,10 @@ func TestAlertsExternalLabelsCountCheck(t *testing.T) {
 							First: 2,
 							Last:  10,
 						},
-						Reporter: checks.AlertsExternalLabelsCheckName,
-						Text:     checkErrorBadData("prom", uri, "bad_data: bad input data"),
-						Severity: checks.Bug,
+						Reporter:   checks.AlertsExternalLabelsCheckName,
+						Text:       checkErrorBadData("prom", uri, "bad_data: bad input data"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -89,9 +90,10 @@ func TestAlertsExternalLabelsCountCheck(t *testing.T) {
 							First: 2,
 							Last:  10,
 						},
-						Reporter: checks.AlertsExternalLabelsCheckName,
-						Text:     checkErrorUnableToRun(checks.AlertsExternalLabelsCheckName, "prom", "http://127.0.0.1:1111", `connection refused`),
-						Severity: checks.Warning,
+						Reporter:   checks.AlertsExternalLabelsCheckName,
+						Text:       checkErrorUnableToRun(checks.AlertsExternalLabelsCheckName, "prom", "http://127.0.0.1:1111", `connection refused`),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -30,10 +30,12 @@ var (
 		SeriesCheckName,
 		RuleDependencyCheckName,
 		RuleDuplicateCheckName,
+		RuleHealthCheckName,
 		RuleForCheckName,
 		LabelCheckName,
 		RuleLinkCheckName,
 		RejectCheckName,
+		CustomCheckName,
 	}
 	OnlineChecks = []string{
 		AlertsCheckName,
@@ -45,6 +47,7 @@ var (
 		CostCheckName,
 		SeriesCheckName,
 		RuleLinkCheckName,
+		RuleHealthCheckName,
 	}
 )
 
@@ -110,6 +113,10 @@ type Problem struct {
 	Lines    parser.LineRange
 	Severity Severity
 	Anchor   Anchor
+	// Incomplete is set when the check couldn't run to completion,
+	// for example because a Prometheus query failed, so other
+	// problems this check would report might be missing.
+	Incomplete bool
 }
 
 type CheckMeta struct {
@@ -125,10 +132,11 @@ type RuleChecker interface {
 }
 
 type exprProblem struct {
-	expr     string
-	text     string
-	details  string
-	severity Severity
+	expr       string
+	text       string
+	details    string
+	severity   Severity
+	incomplete bool
 }
 
 func textAndSeverityFromError(err error, reporter, prom string, s Severity) (text string, severity Severity) {
@@ -159,6 +167,16 @@ func textAndSeverityFromError(err error, reporter, prom string, s Severity) (tex
 	return text, severity
 }
 
+// Find the index of discovery entry for given rule.
+func findRuleEntry(path string, rule parser.Rule, entries []discovery.Entry) (int, bool) {
+	for i, entry := range entries {
+		if entry.ReportedPath == path && entry.Rule.Lines == rule.Lines && entry.Rule.Name() == rule.Name() {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
 func promText(name, uri string) string {
 	return fmt.Sprintf("`%s` Prometheus server at %s", name, uri)
 }
@@ -69,7 +69,7 @@ func simpleProm(name, uri string, timeout time.Duration, required bool) *promapi
 		name,
 		uri,
 		[]*promapi.Prometheus{
-			promapi.NewPrometheus(name, uri, "", map[string]string{"X-Debug": "1"}, timeout, 16, 1000, nil),
+			promapi.NewPrometheus(name, uri, "", map[string]string{"X-Debug": "1"}, timeout, 16, 1000, nil, nil, nil, promapi.RetryPolicy{}),
 		},
 		required,
 		"up",
@@ -274,8 +274,15 @@ var (
 	requireQueryPath      = requestPathCond{path: "/api/v1/query"}
 	requireRangeQueryPath = requestPathCond{path: "/api/v1/query_range"}
 	requireMetadataPath   = requestPathCond{path: "/api/v1/metadata"}
+	requireSeriesPath     = requestPathCond{path: "/api/v1/series"}
+	requireLabelsPath     = requestPathCond{path: "/api/v1/labels"}
+	requireTSDBStatusPath = requestPathCond{path: "/api/v1/status/tsdb"}
 )
 
+func requireLabelValuesPath(name string) requestPathCond {
+	return requestPathCond{path: "/api/v1/label/" + name + "/values"}
+}
+
 type promError struct {
 	code      int
 	errorType v1.ErrorType
@@ -430,6 +437,27 @@ func (fg flagsResponse) respond(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write(d)
 }
 
+type tsdbStatusResponse struct {
+	status v1.TSDBResult
+}
+
+func (tr tsdbStatusResponse) respond(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(200)
+	w.Header().Set("Content-Type", "application/json")
+	result := struct {
+		Status string        `json:"status"`
+		Data   v1.TSDBResult `json:"data"`
+	}{
+		Status: "success",
+		Data:   tr.status,
+	}
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = w.Write(d)
+}
+
 type metadataResponse struct {
 	metadata map[string][]v1.Metadata
 }
@@ -452,6 +480,54 @@ func (mr metadataResponse) respond(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write(d)
 }
 
+type seriesResponse struct {
+	series []map[string]string
+}
+
+func (sr seriesResponse) respond(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(200)
+	w.Header().Set("Content-Type", "application/json")
+	result := struct {
+		Status string              `json:"status"`
+		Data   []map[string]string `json:"data"`
+	}{
+		Status: "success",
+		Data:   sr.series,
+	}
+	if result.Data == nil {
+		result.Data = []map[string]string{}
+	}
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = w.Write(d)
+}
+
+type stringsResponse struct {
+	values []string
+}
+
+func (sr stringsResponse) respond(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(200)
+	w.Header().Set("Content-Type", "application/json")
+	result := struct {
+		Status string   `json:"status"`
+		Data   []string `json:"data"`
+	}{
+		Status: "success",
+		Data:   sr.values,
+	}
+	if result.Data == nil {
+		result.Data = []string{}
+	}
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = w.Write(d)
+}
+
 type sleepResponse struct {
 	sleep time.Duration
 }
@@ -479,6 +555,15 @@ var (
 	respondWithEmptyMatrix = func() responseWriter {
 		return matrixResponse{samples: []*model.SampleStream{}}
 	}
+	respondWithEmptySeries = func() responseWriter {
+		return seriesResponse{}
+	}
+	respondWithSingleSeries = func() responseWriter {
+		return seriesResponse{series: []map[string]string{{}}}
+	}
+	respondWithStrings = func(values ...string) responseWriter {
+		return stringsResponse{values: values}
+	}
 	respondWithSingleInstantVector = func() responseWriter {
 		return vectorResponse{
 			samples: []*model.Sample{generateSample(map[string]string{})},
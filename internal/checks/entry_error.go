@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	"github.com/cloudflare/pint/internal/comments"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	YamlParseReporter   = "yaml/parse"
+	IgnoreFileReporter  = "ignore/file"
+	PintCommentReporter = "pint/comment"
+)
+
+var (
+	yamlErrRe          = regexp.MustCompile("^yaml: line (.+): (.+)")
+	yamlUnmarshalErrRe = regexp.MustCompile("^yaml: unmarshal errors:\n  line (.+): (.+)")
+	rulefmtGroupRe     = regexp.MustCompile("^([0-9]+):[0-9]+: group \".+\", rule [0-9]+, (.+)")
+	rulefmtGroupnameRe = regexp.MustCompile("^([0-9]+):[0-9]+: (groupname: .+)")
+)
+
+func tryDecodingYamlError(err error) (l int, s string) {
+	s = err.Error()
+
+	werr := &rulefmt.WrappedError{}
+	if errors.As(err, &werr) {
+		if uerr := werr.Unwrap(); uerr != nil {
+			s = uerr.Error()
+		}
+	}
+
+	for _, re := range []*regexp.Regexp{yamlErrRe, yamlUnmarshalErrRe, rulefmtGroupRe, rulefmtGroupnameRe} {
+		parts := re.FindStringSubmatch(err.Error())
+		if len(parts) > 2 {
+			line, err2 := strconv.Atoi(parts[1])
+			if err2 != nil || line <= 0 {
+				return 1, s
+			}
+			return line, parts[2]
+		}
+	}
+	return 1, s
+}
+
+// EntryErrorProblem returns a problem for discovery entries that cannot
+// be checked because either the file or the rule failed to parse.
+func EntryErrorProblem(entry discovery.Entry) (Problem, bool) {
+	var commentErr comments.CommentError
+	var ignoreErr discovery.FileIgnoreError
+	switch {
+	case errors.As(entry.PathError, &ignoreErr):
+		return Problem{
+			Lines: parser.LineRange{
+				First: ignoreErr.Line,
+				Last:  ignoreErr.Line,
+			},
+			Reporter: IgnoreFileReporter,
+			Text:     ignoreErr.Error(),
+			Severity: Information,
+		}, true
+	case errors.As(entry.PathError, &commentErr):
+		return Problem{
+			Lines: parser.LineRange{
+				First: commentErr.Line,
+				Last:  commentErr.Line,
+			},
+			Reporter: PintCommentReporter,
+			Text:     fmt.Sprintf("This comment is not a valid pint control comment: %s", commentErr.Error()),
+			Severity: Warning,
+		}, true
+	case entry.PathError != nil:
+		line, e := tryDecodingYamlError(entry.PathError)
+		return Problem{
+			Lines: parser.LineRange{
+				First: line,
+				Last:  line,
+			},
+			Reporter: YamlParseReporter,
+			Text:     fmt.Sprintf("YAML parser returned an error when reading this file: `%s`.", e),
+			Details: `pint cannot read this file because YAML parser returned an error.
+This usually means that you have an indention error or the file doesn't have the YAML structure required by Prometheus for [recording](https://prometheus.io/docs/prometheus/latest/configuration/recording_rules/) and [alerting](https://prometheus.io/docs/prometheus/latest/configuration/alerting_rules/) rules.
+If this file is a template that will be rendered into valid YAML then you can instruct pint to ignore some lines using comments, see [pint docs](https://cloudflare.github.io/pint/ignoring.html).
+`,
+			Severity: Fatal,
+		}, true
+	case entry.Rule.Error.Err != nil:
+		return Problem{
+			Lines: parser.LineRange{
+				First: entry.Rule.Error.Line,
+				Last:  entry.Rule.Error.Line,
+			},
+			Reporter: YamlParseReporter,
+			Text:     fmt.Sprintf("This rule is not a valid Prometheus rule: `%s`.", entry.Rule.Error.Err.Error()),
+			Details: `This Prometheus rule is not valid.
+This usually means that it's missing some required fields.`,
+			Severity: Fatal,
+		}, true
+	}
+	return Problem{}, false
+}
@@ -0,0 +1,345 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	ExternalCheckPrefix = "external/"
+
+	externalStderrLimit = 4096
+)
+
+type ExternalCheckSettings struct {
+	Command         []string `hcl:"command" json:"command"`
+	Timeout         string   `hcl:"timeout,optional" json:"timeout,omitempty"`
+	Severity        string   `hcl:"severity,optional" json:"severity,omitempty"`
+	timeoutDuration time.Duration
+	severity        Severity
+	once            sync.Once
+	results         map[int][]Problem
+	err             error
+	stderr          string
+	// prepared is set when the command was run by Run, which means that any
+	// command failure was reported by the caller and not for every rule.
+	prepared bool
+}
+
+func (s *ExternalCheckSettings) Validate() error {
+	if len(s.Command) == 0 || s.Command[0] == "" {
+		return errors.New("external check command cannot be empty")
+	}
+
+	s.timeoutDuration = time.Minute
+	if s.Timeout != "" {
+		dur, err := model.ParseDuration(s.Timeout)
+		if err != nil {
+			return err
+		}
+		s.timeoutDuration = time.Duration(dur)
+	}
+
+	s.severity = Bug
+	if s.Severity != "" {
+		sev, err := ParseSeverity(s.Severity)
+		if err != nil {
+			return err
+		}
+		s.severity = sev
+	}
+
+	return nil
+}
+
+// Run the external command for all entries before any rule is checked.
+// It should be called once per scan, so the command is not bound to the
+// context (and time budget) of a single rule check. Failures are not
+// reported by the check after calling Run, use FailureProblem to report
+// them once instead.
+func (s *ExternalCheckSettings) Run(ctx context.Context, name string, entries []discovery.Entry) error {
+	s.prepared = true
+	_, err := s.run(ctx, name, entries)
+	return err
+}
+
+// FailureProblem returns the problem describing external command failure.
+func (s *ExternalCheckSettings) FailureProblem(name string, lines parser.LineRange) Problem {
+	problem := Problem{
+		Lines:    lines,
+		Reporter: name,
+		Text:     fmt.Sprintf("Failed to run external check `%s`: `%s`.", name, s.err),
+		Severity: Bug,
+	}
+	if s.stderr != "" {
+		problem.Details = fmt.Sprintf("Command stderr:\n\n```\n%s\n```", s.stderr)
+	}
+	return problem
+}
+
+// Run the external command once for all entries and return problems
+// for each entry index. Every other call will return cached results.
+func (s *ExternalCheckSettings) run(ctx context.Context, name string, entries []discovery.Entry) (map[int][]Problem, error) {
+	s.once.Do(func() {
+		s.results, s.stderr, s.err = s.exec(ctx, name, entries)
+	})
+	return s.results, s.err
+}
+
+func (s *ExternalCheckSettings) exec(ctx context.Context, name string, entries []discovery.Entry) (map[int][]Problem, string, error) {
+	req := externalRequest{Entries: []externalEntry{}}
+	for i, entry := range entries {
+		if entry.State == discovery.Excluded || entry.PathError != nil || entry.Rule.Error.Err != nil {
+			continue
+		}
+		req.Entries = append(req.Entries, newExternalEntry(i, entry))
+	}
+
+	stdin, err := json.Marshal(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode entries: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeoutDuration)
+	defer cancel()
+
+	slog.Debug(
+		"Running external check",
+		slog.String("check", name),
+		slog.Any("command", s.Command),
+		slog.Int("entries", len(req.Entries)),
+	)
+
+	var stdout, stderr bytes.Buffer
+	// nolint: gosec
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	errOut := tailString(stderr.String(), externalStderrLimit)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, errOut, fmt.Errorf("command timed out after %s", s.timeoutDuration)
+	}
+	if err != nil {
+		return nil, errOut, fmt.Errorf("command failed: %w", err)
+	}
+
+	var resp externalResponse
+	dec := json.NewDecoder(&stdout)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&resp); err != nil {
+		return nil, errOut, fmt.Errorf("failed to decode command output: %w", err)
+	}
+
+	results := map[int][]Problem{}
+	for _, ep := range resp.Problems {
+		if ep.Entry < 0 || ep.Entry >= len(entries) {
+			return nil, errOut, fmt.Errorf("command returned a problem for unknown entry %d", ep.Entry)
+		}
+		problem, err := ep.toProblem(name, entries[ep.Entry].Rule, s.severity)
+		if err != nil {
+			return nil, errOut, err
+		}
+		results[ep.Entry] = append(results[ep.Entry], problem)
+	}
+
+	slog.Debug(
+		"External check completed",
+		slog.String("check", name),
+		slog.Int("problems", len(resp.Problems)),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return results, errOut, nil
+}
+
+type externalRequest struct {
+	Entries []externalEntry `json:"entries"`
+}
+
+type externalEntry struct {
+	ReportedPath  string       `json:"reportedPath"`
+	SourcePath    string       `json:"sourcePath"`
+	State         string       `json:"state"`
+	Owner         string       `json:"owner"`
+	ModifiedLines []int        `json:"modifiedLines"`
+	Rule          externalRule `json:"rule"`
+	ID            int          `json:"id"`
+}
+
+type externalRule struct {
+	Labels        map[string]string `json:"labels"`
+	Annotations   map[string]string `json:"annotations"`
+	Type          string            `json:"type"`
+	Name          string            `json:"name"`
+	Expr          string            `json:"expr"`
+	For           string            `json:"for,omitempty"`
+	KeepFiringFor string            `json:"keep_firing_for,omitempty"`
+	Comments      []string          `json:"comments"`
+	Lines         externalLines     `json:"lines"`
+}
+
+type externalLines struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+func newExternalEntry(id int, entry discovery.Entry) externalEntry {
+	ee := externalEntry{
+		ID:            id,
+		ReportedPath:  entry.ReportedPath,
+		SourcePath:    entry.SourcePath,
+		State:         entry.State.String(),
+		Owner:         entry.Owner,
+		ModifiedLines: entry.ModifiedLines,
+		Rule: externalRule{
+			Type:        string(entry.Rule.Type()),
+			Name:        entry.Rule.Name(),
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+			Comments:    customRuleComments(entry.Rule),
+			Lines: externalLines{
+				First: entry.Rule.Lines.First,
+				Last:  entry.Rule.Lines.Last,
+			},
+		},
+	}
+	if entry.Rule.AlertingRule != nil {
+		ee.Rule.Expr = entry.Rule.AlertingRule.Expr.Value.Value
+		ee.Rule.Labels = customYamlMap(entry.Rule.AlertingRule.Labels)
+		ee.Rule.Annotations = customYamlMap(entry.Rule.AlertingRule.Annotations)
+		if entry.Rule.AlertingRule.For != nil {
+			ee.Rule.For = entry.Rule.AlertingRule.For.Value
+		}
+		if entry.Rule.AlertingRule.KeepFiringFor != nil {
+			ee.Rule.KeepFiringFor = entry.Rule.AlertingRule.KeepFiringFor.Value
+		}
+	}
+	if entry.Rule.RecordingRule != nil {
+		ee.Rule.Expr = entry.Rule.RecordingRule.Expr.Value.Value
+		ee.Rule.Labels = customYamlMap(entry.Rule.RecordingRule.Labels)
+	}
+	return ee
+}
+
+type externalResponse struct {
+	Problems []externalProblem `json:"problems"`
+}
+
+type externalProblem struct {
+	Lines    *externalLines `json:"lines"`
+	Reporter string         `json:"reporter"`
+	Text     string         `json:"text"`
+	Details  string         `json:"details"`
+	Severity string         `json:"severity"`
+	Entry    int            `json:"entry"`
+}
+
+func (ep externalProblem) toProblem(name string, rule parser.Rule, fallback Severity) (problem Problem, err error) {
+	if ep.Text == "" {
+		return problem, fmt.Errorf("command returned a problem for entry %d with empty text", ep.Entry)
+	}
+
+	problem = Problem{
+		Lines:    rule.Lines,
+		Reporter: name,
+		Text:     ep.Text,
+		Details:  ep.Details,
+		Severity: fallback,
+	}
+	if ep.Reporter != "" {
+		problem.Reporter = ep.Reporter
+	}
+	if ep.Lines != nil {
+		problem.Lines = parser.LineRange{First: ep.Lines.First, Last: ep.Lines.Last}
+	}
+	if ep.Severity != "" {
+		if problem.Severity, err = ParseSeverity(ep.Severity); err != nil {
+			return problem, fmt.Errorf("command returned a problem for entry %d with invalid severity: %w", ep.Entry, err)
+		}
+	}
+	return problem, nil
+}
+
+func tailString(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= limit {
+		return s
+	}
+	return s[len(s)-limit:]
+}
+
+func NewExternalCheck(name string) ExternalCheck {
+	return ExternalCheck{name: name}
+}
+
+type ExternalCheck struct {
+	name string
+}
+
+func (c ExternalCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		IsOnline: false,
+	}
+}
+
+func (c ExternalCheck) String() string {
+	return c.name
+}
+
+func (c ExternalCheck) Reporter() string {
+	return c.name
+}
+
+func (c ExternalCheck) Check(ctx context.Context, path string, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	var settings *ExternalCheckSettings
+	if s := ctx.Value(SettingsKey(c.name)); s != nil {
+		settings = s.(*ExternalCheckSettings)
+	}
+	if settings == nil {
+		problems = append(problems, Problem{
+			Lines:    rule.Lines,
+			Reporter: c.Reporter(),
+			Text:     fmt.Sprintf("External check `%s` is not configured.", c.name),
+			Severity: Bug,
+		})
+		return problems
+	}
+
+	results, err := settings.run(ctx, c.name, entries)
+	if err != nil {
+		if settings.prepared {
+			return nil
+		}
+		problems = append(problems, settings.FailureProblem(c.name, rule.Lines))
+		return problems
+	}
+
+	if i, ok := findRuleEntry(path, rule, entries); ok {
+		problems = append(problems, results[i]...)
+	}
+
+	return problems
+}
@@ -0,0 +1,277 @@
+package checks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newExternalCtx(t *testing.T, command []string, timeout, severity string) newCtxFn {
+	return func() context.Context {
+		settings := checks.ExternalCheckSettings{
+			Command:  command,
+			Timeout:  timeout,
+			Severity: severity,
+		}
+		if err := settings.Validate(); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		return context.WithValue(context.Background(), checks.SettingsKey("external/test"), &settings)
+	}
+}
+
+func TestExternalCheck(t *testing.T) {
+	content := "- record: foo\n  expr: sum(foo)\n"
+
+	testCases := []checkTest{
+		{
+			description: "not configured",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "External check `external/test` is not configured.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "no problems",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `cat > /dev/null; echo '{"problems":[]}'`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "problem with defaults",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `cat > /dev/null; echo '{"problems":[{"entry":0,"text":"bad rule"}]}'`}, "", "warning"),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "bad rule",
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "problem with all fields",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `cat > /dev/null; echo '{"problems":[{"entry":0,"reporter":"team/naming","text":"bad name","details":"more info","lines":{"first":1,"last":1},"severity":"info"}]}'`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  1,
+						},
+						Reporter: "team/naming",
+						Text:     "bad name",
+						Details:  "more info",
+						Severity: checks.Information,
+					},
+				}
+			},
+		},
+		{
+			description: "command receives rule details",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `grep -q '"rule":{.*"type":"recording","name":"foo","expr":"sum(foo)"' && echo '{"problems":[{"entry":0,"text":"matched"}]}'`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "matched",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "command fails",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `echo "something went wrong" >&2; exit 3`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "Failed to run external check `external/test`: `command failed: exit status 3`.",
+						Details:  "Command stderr:\n\n```\nsomething went wrong\n```",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "command times out",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sleep", "5"}, "100ms", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "Failed to run external check `external/test`: `command timed out after 100ms`.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "invalid output",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `cat > /dev/null; echo 'not json'`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "Failed to run external check `external/test`: `failed to decode command output: invalid character 'o' in literal null (expecting 'u')`.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "unknown entry",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `cat > /dev/null; echo '{"problems":[{"entry":5,"text":"bad rule"}]}'`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "Failed to run external check `external/test`: `command returned a problem for unknown entry 5`.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "invalid severity",
+			content:     content,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewExternalCheck("external/test")
+			},
+			ctx:        newExternalCtx(t, []string{"sh", "-c", `cat > /dev/null; echo '{"problems":[{"entry":0,"text":"bad rule","severity":"foo"}]}'`}, "", ""),
+			entries:    mustParseContent(content),
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: "external/test",
+						Text:     "Failed to run external check `external/test`: `command returned a problem for entry 0 with invalid severity: unknown severity: foo`.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+	}
+	runTests(t, testCases)
+}
+
+func TestExternalCheckSettingsRun(t *testing.T) {
+	entries := mustParseContent("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n")
+
+	settings := checks.ExternalCheckSettings{Command: []string{"sh", "-c", `cat > /dev/null; echo boom >&2; exit 1`}}
+	require.NoError(t, settings.Validate())
+	ctx := context.WithValue(context.Background(), checks.SettingsKey("external/test"), &settings)
+
+	err := settings.Run(ctx, "external/test", entries)
+	require.EqualError(t, err, "command failed: exit status 1")
+	require.Equal(t, checks.Problem{
+		Lines:    entries[1].Rule.Lines,
+		Reporter: "external/test",
+		Text:     "Failed to run external check `external/test`: `command failed: exit status 1`.",
+		Details:  "Command stderr:\n\n```\nboom\n```",
+		Severity: checks.Bug,
+	}, settings.FailureProblem("external/test", entries[1].Rule.Lines))
+
+	// Failure was already returned by Run, so it's not reported for every rule.
+	check := checks.NewExternalCheck("external/test")
+	for _, entry := range entries {
+		require.Empty(t, check.Check(ctx, entry.SourcePath, entry.Rule, entries))
+	}
+}
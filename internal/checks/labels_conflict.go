@@ -54,10 +54,11 @@ func (c LabelsConflictCheck) Check(ctx context.Context, _ string, rule parser.Ru
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Warning)
 		problems = append(problems, Problem{
-			Lines:    rule.RecordingRule.Labels.Lines,
-			Reporter: c.Reporter(),
-			Text:     text,
-			Severity: severity,
+			Lines:      rule.RecordingRule.Labels.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
 		})
 		return problems
 	}
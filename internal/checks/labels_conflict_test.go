@@ -55,9 +55,10 @@ func TestLabelsConflictCheck(t *testing.T) {
 							First: 3,
 							Last:  4,
 						},
-						Reporter: checks.LabelsConflictCheckName,
-						Text:     checkErrorUnableToRun(checks.LabelsConflictCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
-						Severity: checks.Warning,
+						Reporter:   checks.LabelsConflictCheckName,
+						Text:       checkErrorUnableToRun(checks.LabelsConflictCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -56,10 +56,11 @@ func (c RangeQueryCheck) Check(ctx context.Context, _ string, rule parser.Rule,
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Warning)
 		problems = append(problems, Problem{
-			Lines:    expr.Value.Lines,
-			Reporter: c.Reporter(),
-			Text:     text,
-			Severity: severity,
+			Lines:      expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
 		})
 		return problems
 	}
@@ -71,10 +71,11 @@ func (c RateCheck) Check(ctx context.Context, _ string, rule parser.Rule, entrie
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 		problems = append(problems, Problem{
-			Lines:    expr.Value.Lines,
-			Reporter: c.Reporter(),
-			Text:     text,
-			Severity: severity,
+			Lines:      expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
 		})
 		return problems
 	}
@@ -82,11 +83,12 @@ func (c RateCheck) Check(ctx context.Context, _ string, rule parser.Rule, entrie
 	done := &completedList{}
 	for _, problem := range c.checkNode(ctx, expr.Query, entries, cfg, done) {
 		problems = append(problems, Problem{
-			Lines:    expr.Value.Lines,
-			Reporter: c.Reporter(),
-			Text:     problem.text,
-			Details:  problem.details,
-			Severity: problem.severity,
+			Lines:      expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       problem.text,
+			Details:    problem.details,
+			Severity:   problem.severity,
+			Incomplete: problem.incomplete,
 		})
 	}
 
@@ -122,9 +124,10 @@ func (c RateCheck) checkNode(ctx context.Context, node *parser.PromQLNode, entri
 				if err != nil {
 					text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 					problems = append(problems, exprProblem{
-						expr:     s.Name,
-						text:     text,
-						severity: severity,
+						expr:       s.Name,
+						text:       text,
+						severity:   severity,
+						incomplete: true,
 					})
 					continue
 				}
@@ -154,9 +157,10 @@ func (c RateCheck) checkNode(ctx context.Context, node *parser.PromQLNode, entri
 								if err != nil {
 									text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 									problems = append(problems, exprProblem{
-										expr:     sv.Name,
-										text:     text,
-										severity: severity,
+										expr:       sv.Name,
+										text:       text,
+										severity:   severity,
+										incomplete: true,
 									})
 									continue
 								}
@@ -361,9 +361,10 @@ func TestRateCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "promql/rate",
-						Text:     checkErrorUnableToRun(checks.RateCheckName, "prom", uri, "server_error: internal error"),
-						Severity: checks.Bug,
+						Reporter:   "promql/rate",
+						Text:       checkErrorUnableToRun(checks.RateCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -386,9 +387,10 @@ func TestRateCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "promql/rate",
-						Text:     checkErrorBadData("prom", uri, "bad_data: bad input data"),
-						Severity: checks.Bug,
+						Reporter:   "promql/rate",
+						Text:       checkErrorBadData("prom", uri, "bad_data: bad input data"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -414,7 +416,8 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text: checkErrorUnableToRun(checks.RateCheckName, "prom", uri,
 							fmt.Sprintf("failed to decode config data in %s response: yaml: line 2: could not find expected ':'", uri)),
-						Severity: checks.Bug,
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -439,9 +442,10 @@ func TestRateCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "promql/rate",
-						Text:     checkErrorUnableToRun(checks.RateCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
-						Severity: checks.Bug,
+						Reporter:   "promql/rate",
+						Text:       checkErrorUnableToRun(checks.RateCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -487,9 +491,10 @@ func TestRateCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "promql/rate",
-						Text:     checkErrorUnableToRun(checks.RateCheckName, "prom", uri, "server_error: internal error"),
-						Severity: checks.Bug,
+						Reporter:   "promql/rate",
+						Text:       checkErrorUnableToRun(checks.RateCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -716,9 +721,10 @@ func TestRateCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "promql/rate",
-						Text:     checkErrorUnableToRun(checks.RateCheckName, "prom", uri, "server_error: internal error"),
-						Severity: checks.Bug,
+						Reporter:   "promql/rate",
+						Text:       checkErrorUnableToRun(checks.RateCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -100,6 +100,12 @@ func (c SeriesCheck) Reporter() string {
 	return SeriesCheckName
 }
 
+// Plan records all metrics used by given rule, so their existence
+// can be checked with batched queries before this check runs.
+func (c SeriesCheck) Plan(plan *SeriesPlan, rule parser.Rule) {
+	plan.Add(c.prom, rule)
+}
+
 func (c SeriesCheck) Check(ctx context.Context, _ string, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
 	var settings *PromqlSeriesSettings
 	if s := ctx.Value(SettingsKey(c.Reporter())); s != nil {
@@ -118,6 +124,14 @@ func (c SeriesCheck) Check(ctx context.Context, _ string, rule parser.Rule, entr
 
 	params := promapi.NewRelativeRange(settings.lookbackRangeDuration, settings.lookbackStepDuration)
 
+	var plan *SeriesPlan
+	if p := ctx.Value(SeriesPlanKey); p != nil {
+		plan = p.(*SeriesPlan)
+	}
+
+	// Uptime is only needed for range queries, so it's fetched lazily.
+	var promUptime *promapi.RangeQueryResult
+
 	done := map[string]bool{}
 	for _, selector := range getSelectors(expr.Query) {
 		if _, ok := done[selector.String()]; ok {
@@ -131,15 +145,7 @@ func (c SeriesCheck) Check(ctx context.Context, _ string, rule parser.Rule, entr
 			continue
 		}
 
-		metricName := selector.Name
-		if metricName == "" {
-			for _, lm := range selector.LabelMatchers {
-				if lm.Name == labels.MetricName && lm.Type == labels.MatchEqual {
-					metricName = lm.Value
-					break
-				}
-			}
-		}
+		metricName := selectorMetricName(selector)
 
 		// 0. Special case for alert metrics
 		if metricName == "ALERTS" || metricName == "ALERTS_FOR_STATE" {
@@ -194,150 +200,91 @@ func (c SeriesCheck) Check(ctx context.Context, _ string, rule parser.Rule, entr
 			labelNames = append(labelNames, lm.Name)
 		}
 
+		bareSelector := stripLabels(selector)
+
 		// 1. If foo{bar, baz} is there -> GOOD
-		slog.Debug("Checking if selector returns anything", slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()))
-		count, _, err := c.instantSeriesCount(ctx, fmt.Sprintf("count(%s)", selector.String()))
-		if err != nil {
-			problems = append(problems, c.queryProblem(err, expr))
-			continue
-		}
-		if count > 0 {
-			slog.Debug("Found series, skipping further checks", slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()))
+		// Batched results can tell us if foo is there, but if we have any other
+		// label matchers and foo is present we still need to run a query for it.
+		isPresent, isPlanned := plan.isPresent(c.prom.Name(), metricName)
+		switch {
+		case isPlanned && isPresent && len(selector.LabelMatchers) == 1:
+			plan.hit()
+			slog.Debug("Found series in batched results, skipping further checks", slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()))
 			continue
-		}
-
-		promUptime, err := c.prom.RangeQuery(ctx, fmt.Sprintf("count(%s)", c.prom.UptimeMetric()), params)
-		if err != nil {
-			slog.Warn("Cannot detect Prometheus uptime gaps", slog.Any("err", err), slog.String("name", c.prom.Name()))
-		}
-		if promUptime != nil && promUptime.Series.Ranges.Len() == 0 {
-			slog.Warn(
-				"No results for Prometheus uptime metric, you might have set uptime config option to a missing metric, please check your config",
-				slog.String("name", c.prom.Name()),
-				slog.String("metric", c.prom.UptimeMetric()),
-			)
-		}
-		if promUptime == nil || promUptime.Series.Ranges.Len() == 0 {
-			slog.Warn(
-				"Using dummy Prometheus uptime metric results with no gaps",
-				slog.String("name", c.prom.Name()),
-				slog.String("metric", c.prom.UptimeMetric()),
-			)
-			promUptime = &promapi.RangeQueryResult{
-				Series: promapi.SeriesTimeRanges{
-					From:  params.Start(),
-					Until: params.End(),
-					Step:  params.Step(),
-					Ranges: promapi.MetricTimeRanges{
-						{
-							Fingerprint: 0,
-							Labels:      labels.Labels{},
-							Start:       params.Start(),
-							End:         params.End(),
-						},
-					},
-				},
+		case isPlanned && !isPresent:
+			plan.hit()
+			slog.Debug("No series in batched results", slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()))
+		default:
+			slog.Debug("Checking if selector returns anything", slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()))
+			count, _, err := c.instantSeriesCount(ctx, fmt.Sprintf("count(%s)", selector.String()))
+			if err != nil {
+				problems = append(problems, c.queryProblem(err, expr))
+				continue
+			}
+			if count > 0 {
+				slog.Debug("Found series, skipping further checks", slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()))
+				continue
 			}
 		}
 
-		bareSelector := stripLabels(selector)
-
 		// 2. If foo was NEVER there -> BUG
+		// Ask the series API first, it's much cheaper than running a range query.
 		slog.Debug("Checking if base metric has historical series", slog.String("check", c.Reporter()), slog.String("selector", (&bareSelector).String()))
-		trs, err := c.prom.RangeQuery(ctx, fmt.Sprintf("count(%s)", bareSelector.String()), params)
+		sr, err := c.prom.Series(ctx, []string{bareSelector.String()}, params.Start(), params.End(), 1)
 		if err != nil {
 			problems = append(problems, c.queryProblem(err, expr))
 			continue
 		}
-		trs.Series.FindGaps(promUptime.Series, trs.Series.From, trs.Series.Until)
-		if len(trs.Series.Ranges) == 0 {
-			// Check if we have recording rule that provides this metric before we give up
-			var rrEntry *discovery.Entry
-			for _, entry := range entries {
-				entry := entry
-				if entry.Rule.RecordingRule != nil &&
-					entry.Rule.Error.Err == nil &&
-					entry.Rule.RecordingRule.Record.Value == bareSelector.String() {
-					rrEntry = &entry
-					break
-				}
-			}
-			if rrEntry != nil {
-				// Validate recording rule instead
-				slog.Debug("Metric is provided by recording rule", slog.String("selector", (&bareSelector).String()), slog.String("path", rrEntry.SourcePath))
-				problems = append(problems, Problem{
-					Lines:    expr.Value.Lines,
-					Reporter: c.Reporter(),
-					Text: fmt.Sprintf("%s didn't have any series for `%s` metric in the last %s but found recording rule that generates it, skipping further checks.",
-						promText(c.prom.Name(), trs.URI), bareSelector.String(), sinceDesc(trs.Series.From)),
-					Details:  SeriesCheckRuleDetails,
-					Severity: Information,
-				})
-				continue
-			}
-
-			text, severity := c.textAndSeverity(
-				settings,
-				bareSelector.String(),
-				fmt.Sprintf("%s didn't have any series for `%s` metric in the last %s.",
-					promText(c.prom.Name(), trs.URI),
-					bareSelector.String(),
-					sinceDesc(trs.Series.From),
-				),
-				Bug,
-			)
-			problems = append(problems, Problem{
-				Lines:    expr.Value.Lines,
-				Reporter: c.Reporter(),
-				Text:     text,
-				Details:  c.checkOtherServer(ctx, selector.String()),
-				Severity: severity,
-			})
-			slog.Debug("No historical series for base metric", slog.String("check", c.Reporter()), slog.String("selector", (&bareSelector).String()))
+		if len(sr.Series) == 0 {
+			problems = append(problems, c.noSeriesProblem(ctx, settings, expr, entries, selector, sr.URI, params.Start()))
 			continue
 		}
 
 		// 3. If foo is ALWAYS/SOMETIMES there BUT {bar OR baz} is NEVER there -> BUG
-		for _, name := range labelNames {
-			l := stripLabels(selector)
-			l.LabelMatchers = append(l.LabelMatchers, labels.MustNewMatcher(labels.MatchRegexp, name, ".+"))
-			slog.Debug("Checking if base metric has historical series with required label", slog.String("check", c.Reporter()), slog.String("selector", (&l).String()), slog.String("label", name))
-			trsLabelCount, err := c.prom.RangeQuery(ctx, fmt.Sprintf("absent(%s)", l.String()), params)
+		if len(labelNames) > 0 {
+			slog.Debug("Checking if base metric has historical series with required labels", slog.String("check", c.Reporter()), slog.String("selector", (&bareSelector).String()), slog.Any("labels", labelNames))
+			lnr, err := c.prom.LabelNames(ctx, []string{bareSelector.String()}, params.Start(), params.End(), 0)
 			if err != nil {
 				problems = append(problems, c.queryProblem(err, expr))
 				continue
 			}
-			trsLabelCount.Series.FindGaps(promUptime.Series, trsLabelCount.Series.From, trsLabelCount.Series.Until)
-
-			var isAbsentInsideSeriesRange bool
-			for _, lr := range trsLabelCount.Series.Ranges {
-				for _, str := range trs.Series.Ranges {
-					if _, ok := promapi.Overlaps(lr, str, trsLabelCount.Series.Step); ok {
-						isAbsentInsideSeriesRange = true
-					}
+			for _, name := range labelNames {
+				if slices.Contains(lnr.Names, name) {
+					continue
 				}
-			}
-			if !isAbsentInsideSeriesRange {
-				continue
-			}
-
-			if trsLabelCount.Series.Ranges.Len() == 1 && len(trsLabelCount.Series.Gaps) == 0 {
 				problems = append(problems, Problem{
 					Lines:    expr.Value.Lines,
 					Reporter: c.Reporter(),
 					Text: fmt.Sprintf(
 						"%s has `%s` metric but there are no series with `%s` label in the last %s.",
-						promText(c.prom.Name(), trsLabelCount.URI), bareSelector.String(), name, sinceDesc(trsLabelCount.Series.From)),
+						promText(c.prom.Name(), lnr.URI), bareSelector.String(), name, sinceDesc(params.Start())),
 					Details:  SeriesCheckCommonProblemDetails,
 					Severity: Bug,
 				})
-				slog.Debug("No historical series with label used for the query", slog.String("check", c.Reporter()), slog.String("selector", (&l).String()), slog.String("label", name))
+				slog.Debug("No historical series with label used for the query", slog.String("check", c.Reporter()), slog.String("selector", (&bareSelector).String()), slog.String("label", name))
 			}
 		}
 		if len(problems) > 0 {
 			continue
 		}
 
+		// We know that foo exists, now we need range queries to find any gaps.
+		if promUptime == nil {
+			promUptime = c.uptime(ctx, params)
+		}
+
+		slog.Debug("Checking base metric gaps", slog.String("check", c.Reporter()), slog.String("selector", (&bareSelector).String()))
+		trs, err := c.prom.RangeQuery(ctx, fmt.Sprintf("count(%s)", bareSelector.String()), params)
+		if err != nil {
+			problems = append(problems, c.queryProblem(err, expr))
+			continue
+		}
+		trs.Series.FindGaps(promUptime.Series, trs.Series.From, trs.Series.Until)
+		if len(trs.Series.Ranges) == 0 {
+			problems = append(problems, c.noSeriesProblem(ctx, settings, expr, entries, selector, trs.URI, trs.Series.From))
+			continue
+		}
+
 		// 4. If foo was ALWAYS there but it's NO LONGER there (for more than min-age) -> BUG
 		if len(trs.Series.Ranges) == 1 &&
 			!oldest(trs.Series.Ranges).After(trs.Series.From.Add(settings.lookbackStepDuration)) &&
@@ -396,32 +343,26 @@ func (c SeriesCheck) Check(ctx context.Context, _ string, rule parser.Rule, entr
 			addNameSelectorIfNeeded(&labelSelector, selector.LabelMatchers)
 			slog.Debug("Checking if there are historical series matching filter", slog.String("check", c.Reporter()), slog.String("selector", (&labelSelector).String()), slog.String("matcher", lm.String()))
 
-			trsLabel, err := c.prom.RangeQuery(ctx, fmt.Sprintf("count(%s)", labelSelector.String()), params)
+			lvr, err := c.prom.LabelValues(ctx, lm.Name, []string{labelSelector.String()}, params.Start(), params.End(), 1)
 			if err != nil {
 				problems = append(problems, c.queryProblem(err, expr))
 				continue
 			}
-			trsLabel.Series.FindGaps(promUptime.Series, trsLabel.Series.From, trsLabel.Series.Until)
 
 			// 5. If foo is ALWAYS/SOMETIMES there BUT {bar OR baz} value is NEVER there -> BUG
+			if len(lvr.Values) == 0 {
+				problems = append(problems, c.noFilterMatchProblem(settings, expr, selector, lm, lvr.URI, trs.Series.From))
+				continue
+			}
+
+			trsLabel, err := c.prom.RangeQuery(ctx, fmt.Sprintf("count(%s)", labelSelector.String()), params)
+			if err != nil {
+				problems = append(problems, c.queryProblem(err, expr))
+				continue
+			}
+			trsLabel.Series.FindGaps(promUptime.Series, trsLabel.Series.From, trsLabel.Series.Until)
 			if len(trsLabel.Series.Ranges) == 0 {
-				text, severity := c.textAndSeverity(
-					settings,
-					bareSelector.String(),
-					fmt.Sprintf(
-						"%s has `%s` metric with `%s` label but there are no series matching `{%s}` in the last %s.",
-						promText(c.prom.Name(), trsLabel.URI), bareSelector.String(), lm.Name, lm.String(), sinceDesc(trs.Series.From)),
-					Bug,
-				)
-				problems = append(problems, Problem{
-					Lines:    expr.Value.Lines,
-					Reporter: c.Reporter(),
-					Text:     text,
-					Details:  SeriesCheckCommonProblemDetails,
-					Severity: severity,
-				})
-				slog.Debug("No historical series matching filter used in the query",
-					slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()), slog.String("matcher", lm.String()))
+				problems = append(problems, c.noFilterMatchProblem(settings, expr, selector, lm, trsLabel.URI, trs.Series.From))
 				continue
 			}
 
@@ -588,16 +529,137 @@ func (c SeriesCheck) checkOtherServer(ctx context.Context, query string) string
 	return SeriesCheckCommonProblemDetails
 }
 
-func (c SeriesCheck) queryProblem(err error, expr parser.PromQLExpr) Problem {
-	text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+func (c SeriesCheck) uptime(ctx context.Context, params promapi.RangeQueryTimes) *promapi.RangeQueryResult {
+	promUptime, err := c.prom.RangeQuery(ctx, fmt.Sprintf("count(%s)", c.prom.UptimeMetric()), params)
+	if err != nil {
+		slog.Warn("Cannot detect Prometheus uptime gaps", slog.Any("err", err), slog.String("name", c.prom.Name()))
+	}
+	if promUptime != nil && promUptime.Series.Ranges.Len() == 0 {
+		slog.Warn(
+			"No results for Prometheus uptime metric, you might have set uptime config option to a missing metric, please check your config",
+			slog.String("name", c.prom.Name()),
+			slog.String("metric", c.prom.UptimeMetric()),
+		)
+	}
+	if promUptime == nil || promUptime.Series.Ranges.Len() == 0 {
+		slog.Warn(
+			"Using dummy Prometheus uptime metric results with no gaps",
+			slog.String("name", c.prom.Name()),
+			slog.String("metric", c.prom.UptimeMetric()),
+		)
+		promUptime = &promapi.RangeQueryResult{
+			Series: promapi.SeriesTimeRanges{
+				From:  params.Start(),
+				Until: params.End(),
+				Step:  params.Step(),
+				Ranges: promapi.MetricTimeRanges{
+					{
+						Fingerprint: 0,
+						Labels:      labels.Labels{},
+						Start:       params.Start(),
+						End:         params.End(),
+					},
+				},
+			},
+		}
+	}
+	return promUptime
+}
+
+func (c SeriesCheck) noSeriesProblem(
+	ctx context.Context,
+	settings *PromqlSeriesSettings,
+	expr parser.PromQLExpr,
+	entries []discovery.Entry,
+	selector promParser.VectorSelector,
+	uri string,
+	from time.Time,
+) Problem {
+	bareSelector := stripLabels(selector)
+
+	// Check if we have recording rule that provides this metric before we give up
+	var rrEntry *discovery.Entry
+	for _, entry := range entries {
+		entry := entry
+		if entry.Rule.RecordingRule != nil &&
+			entry.Rule.Error.Err == nil &&
+			entry.Rule.RecordingRule.Record.Value == bareSelector.String() {
+			rrEntry = &entry
+			break
+		}
+	}
+	if rrEntry != nil {
+		// Validate recording rule instead
+		slog.Debug("Metric is provided by recording rule", slog.String("selector", (&bareSelector).String()), slog.String("path", rrEntry.SourcePath))
+		return Problem{
+			Lines:    expr.Value.Lines,
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("%s didn't have any series for `%s` metric in the last %s but found recording rule that generates it, skipping further checks.",
+				promText(c.prom.Name(), uri), bareSelector.String(), sinceDesc(from)),
+			Details:  SeriesCheckRuleDetails,
+			Severity: Information,
+		}
+	}
+
+	text, severity := c.textAndSeverity(
+		settings,
+		bareSelector.String(),
+		fmt.Sprintf("%s didn't have any series for `%s` metric in the last %s.",
+			promText(c.prom.Name(), uri),
+			bareSelector.String(),
+			sinceDesc(from),
+		),
+		Bug,
+	)
+	slog.Debug("No historical series for base metric", slog.String("check", c.Reporter()), slog.String("selector", (&bareSelector).String()))
 	return Problem{
 		Lines:    expr.Value.Lines,
 		Reporter: c.Reporter(),
 		Text:     text,
+		Details:  c.checkOtherServer(ctx, selector.String()),
 		Severity: severity,
 	}
 }
 
+func (c SeriesCheck) noFilterMatchProblem(
+	settings *PromqlSeriesSettings,
+	expr parser.PromQLExpr,
+	selector promParser.VectorSelector,
+	lm *labels.Matcher,
+	uri string,
+	from time.Time,
+) Problem {
+	bareSelector := stripLabels(selector)
+	text, severity := c.textAndSeverity(
+		settings,
+		bareSelector.String(),
+		fmt.Sprintf(
+			"%s has `%s` metric with `%s` label but there are no series matching `{%s}` in the last %s.",
+			promText(c.prom.Name(), uri), bareSelector.String(), lm.Name, lm.String(), sinceDesc(from)),
+		Bug,
+	)
+	slog.Debug("No historical series matching filter used in the query",
+		slog.String("check", c.Reporter()), slog.String("selector", (&selector).String()), slog.String("matcher", lm.String()))
+	return Problem{
+		Lines:    expr.Value.Lines,
+		Reporter: c.Reporter(),
+		Text:     text,
+		Details:  SeriesCheckCommonProblemDetails,
+		Severity: severity,
+	}
+}
+
+func (c SeriesCheck) queryProblem(err error, expr parser.PromQLExpr) Problem {
+	text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
+	return Problem{
+		Lines:      expr.Value.Lines,
+		Reporter:   c.Reporter(),
+		Text:       text,
+		Severity:   severity,
+		Incomplete: true,
+	}
+}
+
 func (c SeriesCheck) instantSeriesCount(ctx context.Context, query string) (int, string, error) {
 	qr, err := c.prom.Query(ctx, query)
 	if err != nil {
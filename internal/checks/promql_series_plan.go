@@ -0,0 +1,180 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+// SeriesPlanBatchSize is the maximum number of metric names
+// resolved with a single batched query.
+const SeriesPlanBatchSize = 100
+
+type SeriesPlanContextKey string
+
+const SeriesPlanKey = SeriesPlanContextKey("seriesPlan")
+
+// SeriesPlan collects metric names used by all rules before any check runs
+// and resolves which of them currently have any series using batched
+// count by (__name__) queries, one set of queries per Prometheus server.
+// promql/series check will read these results instead of sending
+// an instant query for every selector.
+type SeriesPlan struct {
+	proms   map[string]*promapi.FailoverGroup
+	names   map[string]map[string]struct{}
+	present map[string]map[string]bool
+	hits    atomic.Int64
+	mtx     sync.Mutex
+}
+
+func NewSeriesPlan() *SeriesPlan {
+	return &SeriesPlan{
+		proms:   map[string]*promapi.FailoverGroup{},
+		names:   map[string]map[string]struct{}{},
+		present: map[string]map[string]bool{},
+	}
+}
+
+// Add records all metric names used by given rule that
+// will need to be checked on given Prometheus server.
+func (sp *SeriesPlan) Add(prom *promapi.FailoverGroup, rule parser.Rule) {
+	expr := rule.Expr()
+	if expr.SyntaxError != nil {
+		return
+	}
+
+	sp.mtx.Lock()
+	defer sp.mtx.Unlock()
+
+	for _, selector := range getSelectors(expr.Query) {
+		if isDisabled(rule, selector) {
+			continue
+		}
+		name := selectorMetricName(selector)
+		if name == "" || name == "ALERTS" || name == "ALERTS_FOR_STATE" {
+			continue
+		}
+		if _, ok := sp.names[prom.Name()]; !ok {
+			sp.proms[prom.Name()] = prom
+			sp.names[prom.Name()] = map[string]struct{}{}
+		}
+		sp.names[prom.Name()][name] = struct{}{}
+	}
+}
+
+// Resolve runs batched queries for all recorded metric names.
+// Any query error is logged and affected names are left unresolved,
+// so checks will fall back to querying them individually.
+func (sp *SeriesPlan) Resolve(ctx context.Context) {
+	sp.mtx.Lock()
+	defer sp.mtx.Unlock()
+
+	for promName, names := range sp.names {
+		prom := sp.proms[promName]
+		batch := make([]string, 0, len(names))
+		for name := range names {
+			batch = append(batch, name)
+		}
+		sort.Strings(batch)
+
+		for len(batch) > 0 {
+			size := min(len(batch), SeriesPlanBatchSize)
+			sp.resolveBatch(ctx, prom, batch[:size])
+			batch = batch[size:]
+		}
+	}
+}
+
+func (sp *SeriesPlan) resolveBatch(ctx context.Context, prom *promapi.FailoverGroup, names []string) {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	query := fmt.Sprintf(`count by (__name__) ({__name__=~"%s"})`, strings.Join(quoted, "|"))
+
+	slog.Debug("Running batched series query", slog.String("name", prom.Name()), slog.Int("metrics", len(names)))
+	qr, err := prom.Query(ctx, query)
+	if err != nil {
+		slog.Warn(
+			"Batched series query failed, metrics will be checked individually",
+			slog.String("name", prom.Name()),
+			slog.Int("metrics", len(names)),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	found := map[string]bool{}
+	for _, s := range qr.Series {
+		name := s.Labels.Get(labels.MetricName)
+		if name == "" {
+			// Results without metric names can't be trusted, leave this batch unresolved.
+			slog.Debug("Batched series query returned results without metric names", slog.String("name", prom.Name()))
+			return
+		}
+		found[name] = s.Value > 0
+	}
+
+	present, ok := sp.present[prom.Name()]
+	if !ok {
+		present = map[string]bool{}
+		sp.present[prom.Name()] = present
+	}
+	for _, name := range names {
+		present[name] = found[name]
+	}
+}
+
+// Metrics returns the number of metric names resolved by batched queries.
+func (sp *SeriesPlan) Metrics() (n int) {
+	sp.mtx.Lock()
+	defer sp.mtx.Unlock()
+	for _, present := range sp.present {
+		n += len(present)
+	}
+	return n
+}
+
+// Hits returns the number of times checks used batched results
+// instead of sending their own queries.
+func (sp *SeriesPlan) Hits() int64 {
+	return sp.hits.Load()
+}
+
+func (sp *SeriesPlan) isPresent(prom, name string) (present, ok bool) {
+	if sp == nil || name == "" {
+		return false, false
+	}
+
+	sp.mtx.Lock()
+	defer sp.mtx.Unlock()
+	present, ok = sp.present[prom][name]
+	return present, ok
+}
+
+func (sp *SeriesPlan) hit() {
+	sp.hits.Add(1)
+}
+
+func selectorMetricName(selector promParser.VectorSelector) string {
+	if selector.Name != "" {
+		return selector.Name
+	}
+	for _, lm := range selector.LabelMatchers {
+		if lm.Name == labels.MetricName && lm.Type == labels.MatchEqual {
+			return lm.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package checks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestSeriesPlan(t *testing.T) {
+	var queries, other atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch {
+		case r.URL.Path == "/api/v1/query" && r.Form.Get("query") == `count by (__name__) ({__name__=~"bar|foo|foo:sum"})`:
+			queries.Add(1)
+			vectorResponse{
+				samples: model.Vector{
+					generateSampleWithValue(map[string]string{"__name__": "foo"}, 5),
+					generateSampleWithValue(map[string]string{"__name__": "foo:sum"}, 1),
+				},
+			}.respond(w, r)
+		case r.URL.Path == "/api/v1/query" && r.Form.Get("query") == `count(foo{job="xxx"})`:
+			other.Add(1)
+			respondWithSingleInstantVector().respond(w, r)
+		case r.URL.Path == "/api/v1/series" && r.Form.Get("match[]") == "bar":
+			other.Add(1)
+			respondWithEmptySeries().respond(w, r)
+		default:
+			t.Errorf("unexpected request: %s %s", r.URL.Path, r.Form)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	prom := newSimpleProm(srv.URL)
+	reg := prometheus.NewRegistry()
+	prom.StartWorkers(reg)
+	defer prom.Close(reg)
+
+	entries := mustParseContent(`
+- record: foo:sum
+  expr: sum(foo)
+- record: foo:job
+  expr: sum(foo{job="xxx"})
+- record: bar:sum
+  expr: sum(bar) + sum(foo:sum)
+- alert: foo
+  expr: ALERTS{alertname="foo"}
+- record: disabled
+  # pint disable promql/series(baz)
+  expr: sum(baz)
+`)
+
+	check := checks.NewSeriesCheck(prom)
+	plan := checks.NewSeriesPlan()
+	for _, entry := range entries {
+		check.Plan(plan, entry.Rule)
+	}
+	plan.Resolve(context.Background())
+	require.Equal(t, int64(1), queries.Load())
+	require.Equal(t, 3, plan.Metrics())
+
+	ctx := context.WithValue(context.Background(), checks.SeriesPlanKey, plan)
+	var problems []checks.Problem
+	for _, entry := range entries {
+		problems = append(problems, check.Check(ctx, entry.SourcePath, entry.Rule, entries)...)
+	}
+	require.Len(t, problems, 1)
+	require.Equal(t, noMetricText("prom", srv.URL, "bar", "1w"), problems[0].Text)
+
+	// foo and foo:sum are answered from the plan,
+	// foo{job="xxx"} still needs a query because it has extra matchers,
+	// bar is known to be missing so we only ask for its history.
+	require.Equal(t, int64(3), plan.Hits())
+	require.Equal(t, int64(2), other.Load())
+	require.Equal(t, int64(1), queries.Load())
+}
+
+func TestSeriesPlanQueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondWithInternalError().respond(w, r)
+	}))
+	defer srv.Close()
+
+	prom := newSimpleProm(srv.URL)
+	reg := prometheus.NewRegistry()
+	prom.StartWorkers(reg)
+	defer prom.Close(reg)
+
+	plan := checks.NewSeriesPlan()
+	for _, entry := range mustParseContent("- record: foo\n  expr: sum(bar)\n") {
+		checks.NewSeriesCheck(prom).Plan(plan, entry.Rule)
+	}
+	plan.Resolve(context.Background())
+	require.Equal(t, 0, plan.Metrics())
+	require.Equal(t, int64(0), plan.Hits())
+}
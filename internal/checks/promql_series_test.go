@@ -82,9 +82,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorBadData("prom", uri, "bad_data: bad input data"),
-						Severity: checks.Bug,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorBadData("prom", uri, "bad_data: bad input data"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -109,9 +110,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", "http://127.127.127.127", `connection refused`),
-						Severity: checks.Warning,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorUnableToRun(checks.SeriesCheckName, "prom", "http://127.127.127.127", `connection refused`),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -128,9 +130,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorTooExpensiveToRun(checks.SeriesCheckName, "prom", uri, "execution: query processing would load too many samples into memory in query execution"),
-						Severity: checks.Warning,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorTooExpensiveToRun(checks.SeriesCheckName, "prom", uri, "execution: query processing would load too many samples into memory in query execution"),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -139,6 +142,10 @@ func TestSeriesCheck(t *testing.T) {
 					conds: []requestCondition{requireQueryPath},
 					resp:  respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{requireRangeQueryPath},
 					resp:  respondWithTooManySamples(),
@@ -157,9 +164,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorTooExpensiveToRun(checks.SeriesCheckName, "prom", uri, "execution: expanding series: context deadline exceeded"),
-						Severity: checks.Warning,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorTooExpensiveToRun(checks.SeriesCheckName, "prom", uri, "execution: expanding series: context deadline exceeded"),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -168,6 +176,10 @@ func TestSeriesCheck(t *testing.T) {
 					conds: []requestCondition{requireQueryPath},
 					resp:  respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{requireRangeQueryPath},
 					resp:  respondWithTimeoutExpandingSeriesSamples(),
@@ -199,8 +211,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -229,8 +241,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -263,22 +275,15 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(notfound)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithEmptySeries(),
 				},
 				{
 					conds: []requestCondition{requireQueryPath, formCond{key: "query", value: "count(found_7)"}},
 					resp:  respondWithSingleInstantVector(),
 				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
-					},
-					resp: respondWithSingleRangeVector1W(),
-				},
 			},
 		},
 		{
@@ -382,9 +387,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
-						Severity: checks.Bug,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -420,8 +426,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -461,8 +467,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -496,17 +502,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:bar)`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "foo:bar"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -538,10 +537,50 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "foo:bar"},
+					},
+					resp: respondWithEmptySeries(),
+				},
+			},
+		},
+		{
+			description: "#2 series present but range query is empty",
+			content:     "- record: foo\n  expr: sum(notfound)\n",
+			checker:     newSeriesCheck,
+			prometheus:  newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: checks.SeriesCheckName,
+						Text:     noMetricText("prom", uri, "notfound", "1w"),
+						Details:  checks.SeriesCheckCommonProblemDetails,
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "notfound"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:bar)`},
+						formCond{key: "query", value: "count(notfound)"},
 					},
 					resp: respondWithEmptyMatrix(),
 				},
@@ -554,6 +593,36 @@ func TestSeriesCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "#2 series query error",
+			content:     "- record: foo\n  expr: sum(notfound)\n",
+			checker:     newSeriesCheck,
+			prometheus:  newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
 		{
 			description: "#2 {ALERTS=...} present",
 			content:     "- record: foo\n  expr: count(ALERTS{alertname=\"myalert\"})\n",
@@ -613,17 +682,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:bar)`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "foo:bar"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -639,9 +701,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
-						Severity: checks.Bug,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -650,6 +713,10 @@ func TestSeriesCheck(t *testing.T) {
 					conds: []requestCondition{requireQueryPath},
 					resp:  respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{requireRangeQueryPath},
 					resp:  respondWithInternalError(),
@@ -691,8 +758,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -725,31 +792,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: respondWithSingleRangeVector1W(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{notfound=~".+"})`},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithStrings("job"),
 				},
 			},
 		},
@@ -765,9 +818,10 @@ func TestSeriesCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
-						Severity: checks.Bug,
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -781,25 +835,18 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{notfound=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
 					resp: respondWithInternalError(),
 				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
-					},
-					resp: respondWithSingleRangeVector1W(),
-				},
 			},
 		},
 		{
@@ -831,33 +878,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-7).Add(time.Minute*5),
-								time.Minute*5,
-							),
-						},
-					},
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithStrings(),
 				},
 			},
 		},
@@ -888,6 +919,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -904,6 +942,13 @@ func TestSeriesCheck(t *testing.T) {
 						},
 					},
 				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: `found{job="abc"}`},
+					},
+					resp: respondWithStrings("xxx"),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -922,37 +967,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{cluster="dev"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("cluster"),
+						formCond{key: "match[]", value: `found{cluster="dev"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found{cluster="dev"})`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*5),
-								time.Now(),
+								time.Now().Add(time.Hour*24*-5),
+								time.Now().Add(time.Hour*24*-5),
 								time.Minute*5,
 							),
 						},
@@ -960,25 +990,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{cluster=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "cluster"),
 				},
 				{
 					conds: []requestCondition{
@@ -1016,6 +1031,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1032,6 +1054,13 @@ func TestSeriesCheck(t *testing.T) {
 						},
 					},
 				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: `found{job="abc"}`},
+					},
+					resp: respondWithStrings("xxx"),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1050,37 +1079,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{cluster="dev"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("cluster"),
+						formCond{key: "match[]", value: `found{cluster="dev"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found{cluster="dev"})`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*-10),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*5),
-								time.Now(),
+								time.Now().Add(time.Hour*24*-5),
+								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
 								time.Minute*5,
 							),
 						},
@@ -1088,25 +1102,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{cluster=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*-10),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "cluster"),
 				},
 				{
 					conds: []requestCondition{
@@ -1144,6 +1143,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1162,31 +1168,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job="abc"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-5),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: `found{job="abc"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{cluster="dev"})`},
+						formCond{key: "query", value: `count(found{job="abc"})`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
 								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
+								time.Now().Add(time.Hour*24*-5),
 								time.Minute*5,
 							),
 						},
@@ -1194,48 +1191,34 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*-10),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelValuesPath("cluster"),
+						formCond{key: "match[]", value: `found{cluster="dev"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{cluster=~".+"})`},
+						formCond{key: "query", value: `count(found{cluster="dev"})`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*-10),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
+								time.Now().Add(time.Hour*24*-5),
 								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
-								time.Now(),
 								time.Minute*5,
 							),
 						},
 					},
 				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithStrings("job", "cluster"),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1261,29 +1244,20 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Minute*-50),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
-								map[string]string{"job": "foo"},
+								map[string]string{},
 								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Minute*-50),
 								time.Minute*5,
@@ -1293,19 +1267,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{"instance": "bar"},
-								time.Now().Add(time.Minute*-50),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1345,31 +1310,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
-								time.Now().Add(time.Hour*24*-4),
-								time.Now(),
+								time.Now().Add(time.Hour*24*-7),
+								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
 								time.Minute*5,
 							),
 						},
@@ -1377,19 +1333,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1439,31 +1386,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
+								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
 								time.Minute*5,
 							),
 						},
@@ -1471,10 +1409,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1503,6 +1441,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1521,17 +1466,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1562,31 +1500,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
+								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
 								time.Minute*5,
 							),
 						},
@@ -1594,19 +1523,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1650,31 +1570,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
+								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
 								time.Minute*5,
 							),
 						},
@@ -1682,19 +1593,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1738,31 +1640,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
+								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
 								time.Minute*5,
 							),
 						},
@@ -1770,19 +1663,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1836,31 +1720,22 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Minute*5,
-							),
-						},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
 					resp: matrixResponse{
 						samples: []*model.SampleStream{
 							generateSampleStream(
 								map[string]string{},
+								time.Now().Add(time.Hour*24*-7),
 								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
 								time.Minute*5,
 							),
 						},
@@ -1868,19 +1743,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-4).Add(time.Minute*-5),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
+					resp: respondWithStrings("job", "instance"),
 				},
 				{
 					conds: []requestCondition{
@@ -1928,6 +1794,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -1937,17 +1810,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("instance", "notfound"),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{notfound=~".+"})`},
+						requireLabelValuesPath("instance"),
+						formCond{key: "match[]", value: `found{instance=~".+"}`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
@@ -1958,10 +1831,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{notfound="notfound"})`},
+						requireLabelValuesPath("notfound"),
+						formCond{key: "match[]", value: `found{notfound="notfound"}`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings(),
 				},
 				{
 					conds: []requestCondition{
@@ -2001,38 +1874,116 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `count(found)`},
+					},
+					resp: respondWithSingleRangeVector1W(),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithStrings("instance", "notfound"),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("instance"),
+						formCond{key: "match[]", value: `found{instance=~".+"}`},
+					},
+					resp: respondWithStrings("xxx"),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `count(found{instance=~".+"})`},
+					},
+					resp: respondWithSingleRangeVector1W(),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("notfound"),
+						formCond{key: "match[]", value: `found{notfound="notfound"}`},
+					},
+					resp: respondWithStrings(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(up)"},
+					},
+					resp: respondWithSingleRangeVector1W(),
+				},
+			},
+		},
+		{
+			description: "#5 label query error",
+			content:     "- record: foo\n  expr: sum(found{error=\"xxx\"})\n",
+			checker:     newSeriesCheck,
+			prometheus:  newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter:   checks.SeriesCheckName,
+						Text:       checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
+						Severity:   checks.Bug,
+						Incomplete: true,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(found{error="xxx"})`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithSingleSeries(),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{instance=~".+"})`},
+						formCond{key: "query", value: `count(found)`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithSingleRangeVector1W(),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{notfound=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("error"),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{instance=~".+"})`},
+						requireLabelValuesPath("error"),
+						formCond{key: "match[]", value: `found{error="xxx"}`},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{notfound="notfound"})`},
+						formCond{key: "query", value: `count(found{error="xxx"})`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithInternalError(),
 				},
 				{
 					conds: []requestCondition{
@@ -2044,8 +1995,8 @@ func TestSeriesCheck(t *testing.T) {
 			},
 		},
 		{
-			description: "#5 label query error",
-			content:     "- record: foo\n  expr: sum(found{error=\"xxx\"})\n",
+			description: "#5 label value present but range query is empty",
+			content:     "- record: foo\n  expr: sum(found{job=\"foo\"})\n",
 			checker:     newSeriesCheck,
 			prometheus:  newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -2056,39 +2007,51 @@ func TestSeriesCheck(t *testing.T) {
 							Last:  2,
 						},
 						Reporter: checks.SeriesCheckName,
-						Text:     checkErrorUnableToRun(checks.SeriesCheckName, "prom", uri, "server_error: internal error"),
+						Text:     noFilterMatchText("prom", uri, "found", "job", `{job="foo"}`, "1w"),
+						Details:  checks.SeriesCheckCommonProblemDetails,
 						Severity: checks.Bug,
 					},
 				}
 			},
 			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
 				{
 					conds: []requestCondition{
-						requireQueryPath,
-						formCond{key: "query", value: `count(found{error="xxx"})`},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyVector(),
+					resp: respondWithSingleSeries(),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithStrings("__name__", "job"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found)`},
+						formCond{key: "query", value: "count(found)"},
 					},
 					resp: respondWithSingleRangeVector1W(),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{error=~".+"})`},
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: `found{job="foo"}`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("foo"),
 				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{error="xxx"})`},
+						formCond{key: "query", value: `count(found{job="foo"})`},
 					},
-					resp: respondWithInternalError(),
+					resp: respondWithEmptyMatrix(),
 				},
 				{
 					conds: []requestCondition{
@@ -2126,6 +2089,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "sometimes"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2156,38 +2126,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(sometimes{churn=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-7).Add(time.Hour),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-5).Add(time.Minute*10),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-2),
-								time.Now().Add(time.Hour*24*-2).Add(time.Minute*20),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "sometimes"},
 					},
+					resp: respondWithStrings("churn"),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(sometimes{churn="notfound"})`},
+						requireLabelValuesPath("churn"),
+						formCond{key: "match[]", value: `sometimes{churn="notfound"}`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings(),
 				},
 				{
 					conds: []requestCondition{
@@ -2216,6 +2165,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "foo"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2225,10 +2181,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(foo{error=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "foo"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("error"),
 				},
 				{
 					conds: []requestCondition{
@@ -2266,6 +2222,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2275,19 +2238,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{removed=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-6).Add(time.Hour*8),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+					resp: respondWithStrings("removed"),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("removed"),
+						formCond{key: "match[]", value: `found{removed="xxx"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
@@ -2355,6 +2316,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2364,19 +2332,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{removed=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-6).Add(time.Hour*8),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+					resp: respondWithStrings("removed"),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("removed"),
+						formCond{key: "match[]", value: `found{removed="xxx"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
@@ -2421,6 +2387,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2430,19 +2403,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{removed=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Minute*-150),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+					resp: respondWithStrings("removed"),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("removed"),
+						formCond{key: "match[]", value: `found{removed="xxx"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
@@ -2496,6 +2467,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2505,37 +2483,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{sometimes=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-7),
-								time.Now().Add(time.Hour*24*-6),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-5),
-								time.Now().Add(time.Hour*24*-5).Add(time.Hour*8),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-4),
-								time.Now().Add(time.Hour*24*-3),
-								time.Minute*5,
-							),
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Hour*24*-2),
-								time.Now().Add(time.Hour*24*-1),
-								time.Minute*5,
-							),
-						},
+					resp: respondWithStrings("sometimes"),
+				},
+				{
+					conds: []requestCondition{
+						requireLabelValuesPath("sometimes"),
+						formCond{key: "match[]", value: `found{sometimes="xxx"}`},
 					},
+					resp: respondWithStrings("xxx"),
 				},
 				{
 					conds: []requestCondition{
@@ -2601,6 +2559,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "sometimes"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2652,6 +2617,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "sometimes"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2725,6 +2697,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: "found"},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -2734,17 +2713,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent(found{job=~".+"})`},
+						requireLabelsPath,
+						formCond{key: "match[]", value: "found"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings("job"),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(found{job="notfound"})`},
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: `found{job="notfound"}`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings(),
 				},
 				{
 					conds: []requestCondition{
@@ -2784,17 +2763,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(notfound)"},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -2830,17 +2802,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(notfound)`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -2983,8 +2948,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -3017,8 +2982,8 @@ func TestSeriesCheck(t *testing.T) {
 					resp:  respondWithEmptyVector(),
 				},
 				{
-					conds: []requestCondition{requireRangeQueryPath},
-					resp:  respondWithEmptyMatrix(),
+					conds: []requestCondition{requireSeriesPath},
+					resp:  respondWithEmptySeries(),
 				},
 			},
 		},
@@ -3077,17 +3042,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(notfound)`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -3131,12 +3089,11 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:count)`},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "foo:count"},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithEmptySeries(),
 				},
-
 				{
 					conds: []requestCondition{
 						requireQueryPath,
@@ -3146,17 +3103,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(foo:sum)`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "foo:sum"},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -3189,17 +3139,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count({__name__=~"(foo|bar)_panics_total"})`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: `{__name__=~"(foo|bar)_panics_total"}`},
 					},
-					resp: respondWithSingleRangeVector1W(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
@@ -3251,6 +3194,13 @@ func TestSeriesCheck(t *testing.T) {
 					},
 					resp: respondWithEmptyVector(),
 				},
+				{
+					conds: []requestCondition{
+						requireSeriesPath,
+						formCond{key: "match[]", value: `{__name__=~"(foo|bar)_panics_total"}`},
+					},
+					resp: respondWithSingleSeries(),
+				},
 				{
 					conds: []requestCondition{
 						requireRangeQueryPath,
@@ -3260,26 +3210,17 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `absent({__name__=~"(foo|bar)_panics_total",job=~".+"})`},
-					},
-					resp: matrixResponse{
-						samples: []*model.SampleStream{
-							generateSampleStream(
-								map[string]string{},
-								time.Now().Add(time.Minute*-50),
-								time.Now(),
-								time.Minute*5,
-							),
-						},
+						requireLabelsPath,
+						formCond{key: "match[]", value: `{__name__=~"(foo|bar)_panics_total"}`},
 					},
+					resp: respondWithStrings("job"),
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count({__name__=~"(foo|bar)_panics_total",job="myjob"})`},
+						requireLabelValuesPath("job"),
+						formCond{key: "match[]", value: `{__name__=~"(foo|bar)_panics_total",job="myjob"}`},
 					},
-					resp: respondWithEmptyMatrix(),
+					resp: respondWithStrings(),
 				},
 			},
 		},
@@ -3312,17 +3253,10 @@ func TestSeriesCheck(t *testing.T) {
 				},
 				{
 					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: `count(notfound)`},
-					},
-					resp: respondWithEmptyMatrix(),
-				},
-				{
-					conds: []requestCondition{
-						requireRangeQueryPath,
-						formCond{key: "query", value: "count(up)"},
+						requireSeriesPath,
+						formCond{key: "match[]", value: "notfound"},
 					},
-					resp: respondWithInternalError(),
+					resp: respondWithEmptySeries(),
 				},
 			},
 		},
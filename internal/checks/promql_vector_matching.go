@@ -60,11 +60,12 @@ func (c VectorMatchingCheck) Check(ctx context.Context, _ string, rule parser.Ru
 
 	for _, problem := range c.checkNode(ctx, expr.Query) {
 		problems = append(problems, Problem{
-			Lines:    expr.Value.Lines,
-			Reporter: c.Reporter(),
-			Text:     problem.text,
-			Details:  problem.details,
-			Severity: problem.severity,
+			Lines:      expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       problem.text,
+			Details:    problem.details,
+			Severity:   problem.severity,
+			Incomplete: problem.incomplete,
 		})
 	}
 
@@ -82,9 +83,10 @@ func (c VectorMatchingCheck) checkNode(ctx context.Context, node *parser.PromQLN
 		if err != nil {
 			text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 			problems = append(problems, exprProblem{
-				expr:     node.Expr,
-				text:     text,
-				severity: severity,
+				expr:       node.Expr,
+				text:       text,
+				severity:   severity,
+				incomplete: true,
 			})
 			return problems
 		}
@@ -139,9 +141,10 @@ func (c VectorMatchingCheck) checkNode(ctx context.Context, node *parser.PromQLN
 		if err != nil {
 			text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 			problems = append(problems, exprProblem{
-				expr:     node.Expr,
-				text:     text,
-				severity: severity,
+				expr:       node.Expr,
+				text:       text,
+				severity:   severity,
+				incomplete: true,
 			})
 			return problems
 		}
@@ -153,9 +156,10 @@ func (c VectorMatchingCheck) checkNode(ctx context.Context, node *parser.PromQLN
 		if err != nil {
 			text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 			problems = append(problems, exprProblem{
-				expr:     node.Expr,
-				text:     text,
-				severity: severity,
+				expr:       node.Expr,
+				text:       text,
+				severity:   severity,
+				incomplete: true,
 			})
 			return problems
 		}
@@ -1078,9 +1078,10 @@ func TestVectorMatchingCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.VectorMatchingCheckName,
-						Text:     checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
-						Severity: checks.Bug,
+						Reporter:   checks.VectorMatchingCheckName,
+						Text:       checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -1099,9 +1100,10 @@ func TestVectorMatchingCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.VectorMatchingCheckName,
-						Text:     checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
-						Severity: checks.Warning,
+						Reporter:   checks.VectorMatchingCheckName,
+						Text:       checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -1120,9 +1122,10 @@ func TestVectorMatchingCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.VectorMatchingCheckName,
-						Text:     checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", uri, `server_error: internal error`),
-						Severity: checks.Bug,
+						Reporter:   checks.VectorMatchingCheckName,
+						Text:       checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", uri, `server_error: internal error`),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -1157,9 +1160,10 @@ func TestVectorMatchingCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: checks.VectorMatchingCheckName,
-						Text:     checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", uri, `server_error: internal error`),
-						Severity: checks.Bug,
+						Reporter:   checks.VectorMatchingCheckName,
+						Text:       checkErrorUnableToRun(checks.VectorMatchingCheckName, "prom", uri, `server_error: internal error`),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -3,8 +3,15 @@ package checks
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
 	"time"
 
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/output"
 	"github.com/cloudflare/pint/internal/parser"
@@ -14,14 +21,19 @@ import (
 const (
 	CostCheckName       = "query/cost"
 	BytesPerSampleQuery = "avg(avg_over_time(go_memstats_alloc_bytes[2h]) / avg_over_time(prometheus_tsdb_head_series[2h]))"
+
+	// TSDBStatusLimit is the number of top metrics and labels
+	// requested from /api/v1/status/tsdb.
+	TSDBStatusLimit = 100
 )
 
-func NewCostCheck(prom *promapi.FailoverGroup, maxSeries, maxTotalSamples, maxPeakSamples int, maxEvaluationDuration time.Duration, severity Severity) CostCheck {
+func NewCostCheck(prom *promapi.FailoverGroup, maxSeries, maxTotalSamples, maxPeakSamples, maxSelectorSeries int, maxEvaluationDuration time.Duration, severity Severity) CostCheck {
 	return CostCheck{
 		prom:                  prom,
 		maxSeries:             maxSeries,
 		maxTotalSamples:       maxTotalSamples,
 		maxPeakSamples:        maxPeakSamples,
+		maxSelectorSeries:     maxSelectorSeries,
 		maxEvaluationDuration: maxEvaluationDuration,
 		severity:              severity,
 	}
@@ -32,6 +44,7 @@ type CostCheck struct {
 	maxSeries             int
 	maxTotalSamples       int
 	maxPeakSamples        int
+	maxSelectorSeries     int
 	maxEvaluationDuration time.Duration
 	severity              Severity
 }
@@ -71,10 +84,11 @@ func (c CostCheck) Check(ctx context.Context, _ string, rule parser.Rule, _ []di
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 		problems = append(problems, Problem{
-			Lines:    expr.Value.Lines,
-			Reporter: c.Reporter(),
-			Text:     text,
-			Severity: severity,
+			Lines:      expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
 		})
 		return problems
 	}
@@ -139,5 +153,145 @@ func (c CostCheck) Check(ctx context.Context, _ string, rule parser.Rule, _ []di
 		})
 	}
 
+	if c.maxSelectorSeries > 0 {
+		problems = append(problems, c.checkSelectors(ctx, expr)...)
+	}
+
+	return problems
+}
+
+// checkSelectors estimates how many series each selector will read before
+// any aggregation is applied, using cardinality stats from the TSDB head.
+// Only metrics and labels included in the TSDB status response are known,
+// so selectors for metrics outside of the top list are never reported.
+// Servers without the TSDB status API are skipped.
+func (c CostCheck) checkSelectors(ctx context.Context, expr parser.PromQLExpr) (problems []Problem) {
+	ts, err := c.prom.TSDBStatus(ctx, TSDBStatusLimit)
+	if err != nil {
+		if promapi.IsUnsupportedError(err) {
+			slog.Debug(
+				"Prometheus server doesn't support TSDB status API, skipping selector series estimate",
+				slog.String("name", c.prom.Name()),
+				slog.String("err", err.Error()),
+			)
+			return nil
+		}
+		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Warning)
+		problems = append(problems, Problem{
+			Lines:      expr.Value.Lines,
+			Reporter:   c.Reporter(),
+			Text:       text,
+			Severity:   severity,
+			Incomplete: true,
+		})
+		return problems
+	}
+
+	done := map[string]struct{}{}
+	for _, selector := range getSelectors(expr.Query) {
+		if _, ok := done[selector.String()]; ok {
+			continue
+		}
+		done[selector.String()] = struct{}{}
+
+		name := selectorMetricName(selector)
+		total, estimate, ok := estimateSelectorSeries(selector, name, ts.Status)
+		if !ok || estimate <= c.maxSelectorSeries {
+			continue
+		}
+
+		var head string
+		if ts.Status.HeadStats.NumSeries > 0 {
+			head = fmt.Sprintf(" (%.1f%% of all series in the TSDB head)", float64(estimate)/float64(ts.Status.HeadStats.NumSeries)*100)
+		}
+		problems = append(problems, Problem{
+			Lines:    expr.Value.Lines,
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf(
+				"%s has %d series for `%s` metric, `%s` selector will read an estimated %d series%s before any aggregation, which is more than the configured limit of %d.",
+				promText(c.prom.Name(), ts.URI), total, name, selector.String(), estimate, head, c.maxSelectorSeries,
+			),
+			Severity: c.severity,
+		})
+	}
+
 	return problems
 }
+
+// estimateSelectorSeries returns the number of series for the selector metric and an estimate of
+// how many of them will match all label matchers, assuming that series are evenly distributed
+// across all label values.
+// Label value counts in TSDB stats are for the whole head, not for given metric, so the estimate
+// is only as good as the guess that given metric uses all values of each label.
+// It returns false if there are no stats for given metric or if there are matchers that
+// can't be estimated, like regexp matchers, labels not present in stats or labels with more
+// values than there are series for given metric, which means that most of these values
+// are used by other metrics.
+func estimateSelectorSeries(selector promParser.VectorSelector, name string, status v1.TSDBResult) (total, estimate int, ok bool) {
+	if name == "" {
+		return 0, 0, false
+	}
+
+	for _, stat := range status.SeriesCountByMetricName {
+		if stat.Name == name {
+			total = int(stat.Value)
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return 0, 0, false
+	}
+
+	values := make(map[string]uint64, len(status.LabelValueCountByLabelName))
+	for _, stat := range status.LabelValueCountByLabelName {
+		values[stat.Name] = stat.Value
+	}
+
+	est := float64(total)
+	for _, lm := range selector.LabelMatchers {
+		if lm.Name == labels.MetricName {
+			continue
+		}
+
+		var matched uint64
+		switch lm.Type {
+		case labels.MatchNotEqual, labels.MatchNotRegexp:
+			// Negative matchers usually only exclude a small number of series.
+			continue
+		case labels.MatchEqual:
+			if lm.Value == "" {
+				// Only matches series without this label.
+				return 0, 0, false
+			}
+			matched = 1
+		case labels.MatchRegexp:
+			if lm.Value == ".*" || lm.Value == ".+" {
+				continue
+			}
+			if matched = uint64(literalAlternatives(lm.Value)); matched == 0 {
+				return 0, 0, false
+			}
+		}
+
+		count, found := values[lm.Name]
+		if !found || count == 0 || count > uint64(total) {
+			return 0, 0, false
+		}
+		est = est * float64(min(matched, count)) / float64(count)
+	}
+
+	return total, int(est), true
+}
+
+// literalAlternatives returns the number of values matched by a regexp
+// that is a list of literal strings, like "a|b|c", or 0 for any other regexp.
+func literalAlternatives(re string) (n int) {
+	for _, v := range strings.Split(re, "|") {
+		if v == "" || regexp.QuoteMeta(v) != v {
+			return 0
+		}
+		n++
+	}
+	return n
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
 	"github.com/cloudflare/pint/internal/checks"
@@ -36,6 +37,25 @@ func peakSamplesText(name, uri string, total, limit int) string {
 	return fmt.Sprintf("`%s` Prometheus server at %s queried %d peak samples when executing this query, which is more than the configured limit of %d.", name, uri, total, limit)
 }
 
+func selectorSeriesText(name, uri string, total int, metric, selector string, estimate int, head string, limit int) string {
+	return fmt.Sprintf("`%s` Prometheus server at %s has %d series for `%s` metric, `%s` selector will read an estimated %d series%s before any aggregation, which is more than the configured limit of %d.", name, uri, total, metric, selector, estimate, head, limit)
+}
+
+func tsdbStatus() v1.TSDBResult {
+	return v1.TSDBResult{
+		HeadStats: v1.TSDBHeadStats{NumSeries: 4000000},
+		SeriesCountByMetricName: []v1.Stat{
+			{Name: "foo", Value: 2000000},
+			{Name: "bar", Value: 50000},
+		},
+		LabelValueCountByLabelName: []v1.Stat{
+			{Name: "instance", Value: 1000},
+			{Name: "job", Value: 4},
+			{Name: "pod", Value: 3000000},
+		},
+	}
+}
+
 func TestCostCheck(t *testing.T) {
 	content := "- record: foo\n  expr: sum(foo)\n"
 
@@ -44,7 +64,7 @@ func TestCostCheck(t *testing.T) {
 			description: "ignores rules with syntax errors",
 			content:     "- record: foo\n  expr: sum(foo) without(\n",
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems:   noProblems,
@@ -53,7 +73,7 @@ func TestCostCheck(t *testing.T) {
 			description: "empty response",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -83,7 +103,7 @@ func TestCostCheck(t *testing.T) {
 			description: "response timeout",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: func(uri string) *promapi.FailoverGroup {
 				return simpleProm("prom", uri, time.Millisecond*50, true)
@@ -95,9 +115,10 @@ func TestCostCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "query/cost",
-						Text:     checkErrorUnableToRun(checks.CostCheckName, "prom", uri, "connection timeout"),
-						Severity: checks.Bug,
+						Reporter:   "query/cost",
+						Text:       checkErrorUnableToRun(checks.CostCheckName, "prom", uri, "connection timeout"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -115,7 +136,7 @@ func TestCostCheck(t *testing.T) {
 			description: "bad request",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -125,9 +146,10 @@ func TestCostCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "query/cost",
-						Text:     checkErrorBadData("prom", uri, "bad_data: bad input data"),
-						Severity: checks.Bug,
+						Reporter:   "query/cost",
+						Text:       checkErrorBadData("prom", uri, "bad_data: bad input data"),
+						Severity:   checks.Bug,
+						Incomplete: true,
 					},
 				}
 			},
@@ -145,7 +167,7 @@ func TestCostCheck(t *testing.T) {
 			description: "connection refused",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: func(s string) *promapi.FailoverGroup {
 				return simpleProm("prom", "http://127.0.0.1:1111", time.Second*5, false)
@@ -157,9 +179,10 @@ func TestCostCheck(t *testing.T) {
 							First: 2,
 							Last:  2,
 						},
-						Reporter: "query/cost",
-						Text:     checkErrorUnableToRun(checks.CostCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
-						Severity: checks.Warning,
+						Reporter:   "query/cost",
+						Text:       checkErrorUnableToRun(checks.CostCheckName, "prom", "http://127.0.0.1:1111", "connection refused"),
+						Severity:   checks.Warning,
+						Incomplete: true,
 					},
 				}
 			},
@@ -168,7 +191,7 @@ func TestCostCheck(t *testing.T) {
 			description: "1 result",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -209,7 +232,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 results",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -260,7 +283,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 result with MB",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -311,7 +334,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 results with 1 series max (1KB bps)",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 1, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 1, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -362,7 +385,7 @@ func TestCostCheck(t *testing.T) {
 			description: "6 results with 5 series max",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 5, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 5, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -408,7 +431,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 results with 5 series max / infi",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 5, 0, 0, 0, checks.Information)
+				return checks.NewCostCheck(prom, 5, 0, 0, 0, 0, checks.Information)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -458,7 +481,7 @@ func TestCostCheck(t *testing.T) {
   expr: 'sum({__name__="foo"})'
 `,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -509,7 +532,7 @@ func TestCostCheck(t *testing.T) {
 			description: "1s eval, 5s limit",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 0, 0, time.Second*5, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, time.Second*5, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -557,7 +580,7 @@ func TestCostCheck(t *testing.T) {
 			description: "stats",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 100, 10, time.Second*5, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 100, 10, 0, time.Second*5, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -636,7 +659,7 @@ func TestCostCheck(t *testing.T) {
 			description: "stats - info",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 100, 10, time.Second*5, checks.Information)
+				return checks.NewCostCheck(prom, 0, 100, 10, 0, time.Second*5, checks.Information)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -711,6 +734,224 @@ func TestCostCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "unfiltered selector on high cardinality metric",
+			content:     "- record: foo\n  expr: sum(foo) + sum(bar)\n",
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 0, 100000, 0, checks.Bug)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 0) + ".",
+						Severity: checks.Information,
+					},
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     selectorSeriesText("prom", uri, 2000000, "foo", "foo", 2000000, " (50.0% of all series in the TSDB head)", 100000),
+						Severity: checks.Bug,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo) + sum(bar))`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireTSDBStatusPath,
+						formCond{key: "limit", value: "100"},
+					},
+					resp: tsdbStatusResponse{status: tsdbStatus()},
+				},
+			},
+		},
+		{
+			description: "filtered selectors on high cardinality metric",
+			content:     "- record: foo\n  expr: sum(foo{job=\"a\", instance!=\"b\"}) + sum(foo{instance=~\"a|b\"}) + sum(foo{job=~\"a.*\"}) + sum(foo{env=\"prod\"})\n",
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 0, 100000, 0, checks.Warning)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 0) + ".",
+						Severity: checks.Information,
+					},
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     selectorSeriesText("prom", uri, 2000000, "foo", `foo{instance!="b",job="a"}`, 500000, " (12.5% of all series in the TSDB head)", 100000),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo{job="a", instance!="b"}) + sum(foo{instance=~"a|b"}) + sum(foo{job=~"a.*"}) + sum(foo{env="prod"}))`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireTSDBStatusPath,
+					},
+					resp: tsdbStatusResponse{status: tsdbStatus()},
+				},
+			},
+		},
+		{
+			description: "filtered selector on label with more values than metric series",
+			content:     "- record: foo\n  expr: sum(foo{pod=~\".+\"}) + sum(foo{job=\"a\", pod=\"b\"})\n",
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 0, 100000, 0, checks.Warning)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 0) + ".",
+						Severity: checks.Information,
+					},
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     selectorSeriesText("prom", uri, 2000000, "foo", `foo{pod=~".+"}`, 2000000, " (50.0% of all series in the TSDB head)", 100000),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo{pod=~".+"}) + sum(foo{job="a", pod="b"}))`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireTSDBStatusPath,
+					},
+					resp: tsdbStatusResponse{status: tsdbStatus()},
+				},
+			},
+		},
+		{
+			description: "TSDB status error",
+			content:     content,
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 0, 100000, 0, checks.Bug)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 0) + ".",
+						Severity: checks.Information,
+					},
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter:   "query/cost",
+						Text:       checkErrorBadData("prom", uri, "bad_data: bad input data"),
+						Severity:   checks.Warning,
+						Incomplete: true,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo))`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireTSDBStatusPath,
+					},
+					resp: respondWithBadData(),
+				},
+			},
+		},
+		{
+			description: "TSDB status not supported",
+			content:     content,
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 0, 100000, 0, checks.Bug)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  2,
+						},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 0) + ".",
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo))`},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireTSDBStatusPath,
+					},
+					resp: promError{code: 404, errorType: v1.ErrClient, err: "404 page not found"},
+				},
+			},
+		},
 	}
 
 	runTests(t, testCases)
@@ -0,0 +1,333 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/comments"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/utils"
+)
+
+const (
+	CustomCheckName = "rule/custom"
+)
+
+var (
+	customEnv     *cel.Env
+	customEnvErr  error
+	customEnvOnce sync.Once
+)
+
+// Variables available to all custom check expressions.
+func customCheckEnv() (*cel.Env, error) {
+	customEnvOnce.Do(func() {
+		customEnv, customEnvErr = cel.NewEnv(
+			cel.Variable("rule.name", cel.StringType),
+			cel.Variable("rule.type", cel.StringType),
+			cel.Variable("rule.expr", cel.StringType),
+			cel.Variable("rule.metrics", cel.ListType(cel.StringType)),
+			cel.Variable("rule.functions", cel.ListType(cel.StringType)),
+			cel.Variable("rule.aggregations", cel.ListType(cel.StringType)),
+			cel.Variable("rule.labels", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("rule.annotations", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("rule.for", cel.DurationType),
+			cel.Variable("rule.keep_firing_for", cel.DurationType),
+			cel.Variable("rule.path", cel.StringType),
+			cel.Variable("rule.owner", cel.StringType),
+			cel.Variable("rule.comments", cel.ListType(cel.StringType)),
+			cel.OptionalTypes(),
+			ext.Strings(),
+		)
+	})
+	return customEnv, customEnvErr
+}
+
+func CompileCustomCheck(expr string) (cel.Program, error) {
+	env, err := customCheckEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must return a bool value, got %s", ast.OutputType())
+	}
+
+	return env.Program(ast)
+}
+
+func ParseCustomCheckMessage(message string) error {
+	_, err := newTemplateFromContext(newTemplateContext(parser.Rule{}), message)
+	return err
+}
+
+func NewCustomCheck(name, expr, message string, severity Severity) CustomCheck {
+	prg, err := CompileCustomCheck(expr)
+	return CustomCheck{
+		name:     name,
+		expr:     expr,
+		message:  message,
+		program:  prg,
+		err:      err,
+		severity: severity,
+	}
+}
+
+// NewCompiledCustomCheck creates a custom check from a program already
+// returned by CompileCustomCheck.
+func NewCompiledCustomCheck(name, expr, message string, prg cel.Program, severity Severity) CustomCheck {
+	return CustomCheck{
+		name:     name,
+		expr:     expr,
+		message:  message,
+		program:  prg,
+		severity: severity,
+	}
+}
+
+type CustomCheck struct {
+	program  cel.Program
+	err      error
+	name     string
+	expr     string
+	message  string
+	severity Severity
+}
+
+func (c CustomCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		IsOnline: false,
+	}
+}
+
+func (c CustomCheck) String() string {
+	return fmt.Sprintf("%s(%s)", CustomCheckName, c.name)
+}
+
+func (c CustomCheck) Reporter() string {
+	return CustomCheckName
+}
+
+func (c CustomCheck) Check(_ context.Context, path string, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.Type() == parser.InvalidRuleType {
+		return nil
+	}
+
+	if c.err != nil {
+		problems = append(problems, Problem{
+			Lines:    rule.Lines,
+			Reporter: c.Reporter(),
+			Text:     fmt.Sprintf("Failed to compile `%s` custom check: `%s`.", c.name, c.err),
+			Severity: Bug,
+		})
+		return problems
+	}
+
+	out, _, err := c.program.Eval(newCustomCheckVars(path, rule, entries))
+	if err != nil {
+		problems = append(problems, Problem{
+			Lines:    rule.Lines,
+			Reporter: c.Reporter(),
+			Text:     fmt.Sprintf("Failed to evaluate `%s` custom check: `%s`.", c.name, err),
+			Severity: Warning,
+		})
+		return problems
+	}
+
+	if ok, isBool := out.Value().(bool); isBool && ok {
+		return nil
+	}
+
+	problems = append(problems, Problem{
+		Lines:    rule.Lines,
+		Reporter: c.Reporter(),
+		Text:     c.renderMessage(rule),
+		Severity: c.severity,
+	})
+	return problems
+}
+
+func (c CustomCheck) renderMessage(rule parser.Rule) string {
+	if c.message == "" {
+		return fmt.Sprintf("This rule doesn't pass `%s` custom check: `%s`.", c.name, c.expr)
+	}
+
+	tctx := newTemplateContext(rule)
+	tmpl, err := newTemplateFromContext(tctx, c.message)
+	if err != nil {
+		return c.message
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, tctx); err != nil {
+		return c.message
+	}
+	return buf.String()
+}
+
+func newCustomCheckVars(path string, rule parser.Rule, entries []discovery.Entry) map[string]any {
+	vars := map[string]any{
+		"rule.name":            rule.Name(),
+		"rule.type":            string(rule.Type()),
+		"rule.expr":            "",
+		"rule.metrics":         []string{},
+		"rule.functions":       []string{},
+		"rule.aggregations":    []string{},
+		"rule.labels":          map[string]string{},
+		"rule.annotations":     map[string]string{},
+		"rule.for":             time.Duration(0),
+		"rule.keep_firing_for": time.Duration(0),
+		"rule.path":            path,
+		"rule.owner":           customRuleOwner(path, rule, entries),
+		"rule.comments":        customRuleComments(rule),
+	}
+
+	expr := rule.Expr()
+	vars["rule.expr"] = expr.Value.Value
+	if expr.Query != nil {
+		vars["rule.metrics"] = customExprMetrics(expr.Query)
+		vars["rule.functions"] = customExprFunctions(expr.Query)
+		vars["rule.aggregations"] = customExprAggregations(expr.Query)
+	}
+
+	if rule.AlertingRule != nil {
+		vars["rule.labels"] = customYamlMap(rule.AlertingRule.Labels)
+		vars["rule.annotations"] = customYamlMap(rule.AlertingRule.Annotations)
+		if rule.AlertingRule.For != nil {
+			d, _ := model.ParseDuration(rule.AlertingRule.For.Value)
+			vars["rule.for"] = time.Duration(d)
+		}
+		if rule.AlertingRule.KeepFiringFor != nil {
+			d, _ := model.ParseDuration(rule.AlertingRule.KeepFiringFor.Value)
+			vars["rule.keep_firing_for"] = time.Duration(d)
+		}
+	}
+	if rule.RecordingRule != nil {
+		vars["rule.labels"] = customYamlMap(rule.RecordingRule.Labels)
+	}
+
+	return vars
+}
+
+func customYamlMap(ym *parser.YamlMap) map[string]string {
+	m := map[string]string{}
+	if ym == nil {
+		return m
+	}
+	for _, kv := range ym.Items {
+		m[kv.Key.Value] = kv.Value.Value
+	}
+	return m
+}
+
+// Owner is stored on the discovery entry, so we need to find it first.
+// If that fails then fallback to using rule/owner comments.
+func customRuleOwner(path string, rule parser.Rule, entries []discovery.Entry) string {
+	if i, ok := findRuleEntry(path, rule, entries); ok {
+		return entries[i].Owner
+	}
+	var owner string
+	for _, o := range comments.Only[comments.Owner](rule.Comments, comments.RuleOwnerType) {
+		owner = o.Name
+	}
+	return owner
+}
+
+func customRuleComments(rule parser.Rule) []string {
+	names := map[comments.Type]string{
+		comments.RuleOwnerType: comments.RuleOwnerComment,
+		comments.DisableType:   comments.DisableComment,
+		comments.SnoozeType:    comments.SnoozeComment,
+		comments.RuleSetType:   comments.RuleSetComment,
+	}
+	cs := make([]string, 0, len(rule.Comments))
+	for _, c := range rule.Comments {
+		name, ok := names[c.Type]
+		if !ok {
+			continue
+		}
+		cs = append(cs, strings.TrimSpace(name+" "+c.Value.String()))
+	}
+	return cs
+}
+
+func customExprMetrics(node *parser.PromQLNode) []string {
+	names := []string{}
+	for _, vs := range utils.HasVectorSelector(node) {
+		name := vs.Name
+		if name == "" {
+			for _, lm := range vs.LabelMatchers {
+				if lm.Name == model.MetricNameLabel && lm.Type == labels.MatchEqual {
+					name = lm.Value
+				}
+			}
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return uniqueSorted(names)
+}
+
+func customExprFunctions(node *parser.PromQLNode) []string {
+	names := []string{}
+	walkPromQLNode(node, func(n *parser.PromQLNode) {
+		if call, ok := n.Node.(*promParser.Call); ok {
+			names = append(names, call.Func.Name)
+		}
+	})
+	return uniqueSorted(names)
+}
+
+func customExprAggregations(node *parser.PromQLNode) []string {
+	names := []string{}
+	walkPromQLNode(node, func(n *parser.PromQLNode) {
+		if agg, ok := n.Node.(*promParser.AggregateExpr); ok {
+			names = append(names, agg.Op.String())
+		}
+	})
+	return uniqueSorted(names)
+}
+
+func walkPromQLNode(node *parser.PromQLNode, fn func(*parser.PromQLNode)) {
+	fn(node)
+	for _, child := range node.Children {
+		walkPromQLNode(child, fn)
+	}
+}
+
+func uniqueSorted(src []string) []string {
+	seen := map[string]struct{}{}
+	dst := make([]string, 0, len(src))
+	for _, s := range src {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		dst = append(dst, s)
+	}
+	sort.Strings(dst)
+	return dst
+}
@@ -0,0 +1,204 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestCustomCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "passing expression",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck("name", `rule.name == "foo"`, "", checks.Bug)
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "failing expression / default message",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck("prefix", `rule.name.startsWith("team_x:")`, "", checks.Bug)
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: checks.CustomCheckName,
+						Text:     "This rule doesn't pass `prefix` custom check: `rule.name.startsWith(\"team_x:\")`.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "failing expression / templated message",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck("prefix", `rule.name.startsWith("team_x:")`, "{{ $record }} must start with team_x:", checks.Warning)
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: checks.CustomCheckName,
+						Text:     "foo must start with team_x:",
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "critical alert with for and runbook",
+			content: `
+- alert: foo
+  expr: up == 0
+  for: 5m
+  labels:
+    severity: critical
+  annotations:
+    runbook: http://runbook
+`,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck(
+					"critical",
+					`rule.labels.?severity.orValue("") != "critical" || (rule.for >= duration("5m") && "runbook" in rule.annotations)`,
+					"", checks.Bug)
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "critical alert without for",
+			content: `
+- alert: foo
+  expr: up == 0
+  labels:
+    severity: critical
+  annotations:
+    runbook: http://runbook
+`,
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck(
+					"critical",
+					`!("severity" in rule.labels) || rule.labels.severity != "critical" || (rule.for >= duration("5m") && "runbook" in rule.annotations)`,
+					"Critical alerts must have for >= 5m and a runbook annotation.", checks.Bug)
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 2,
+							Last:  7,
+						},
+						Reporter: checks.CustomCheckName,
+						Text:     "Critical alerts must have for >= 5m and a runbook annotation.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "expression facts",
+			content:     "- record: foo\n  expr: sum(rate(bar_total[5m])) / count(max_over_time(bar[5m]))\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck(
+					"facts",
+					`rule.metrics == ["bar", "bar_total"] && rule.functions == ["max_over_time", "rate"] && rule.aggregations == ["count", "sum"] && rule.type == "recording"`,
+					"", checks.Bug)
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "path, owner and comments",
+			content:     "# pint rule/owner bob\n- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck(
+					"meta",
+					`rule.path == "fake.yml" && rule.owner == "alice" && rule.comments == ["rule/owner bob"]`,
+					"", checks.Bug)
+			},
+			prometheus: noProm,
+			entries: []discovery.Entry{
+				{
+					ReportedPath: "fake.yml",
+					SourcePath:   "fake.yml",
+					Owner:        "alice",
+					Rule: parser.Rule{
+						RecordingRule: &parser.RecordingRule{Record: parser.YamlNode{Value: "foo"}},
+						Lines:         parser.LineRange{First: 2, Last: 3},
+					},
+				},
+			},
+			problems: noProblems,
+		},
+		{
+			description: "owner from comments",
+			content:     "# pint rule/owner bob\n- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck("meta", `rule.owner == "bob"`, "", checks.Bug)
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "evaluation error",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck("missing", `rule.labels.severity == "critical"`, "", checks.Bug)
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: checks.CustomCheckName,
+						Text:     "Failed to evaluate `missing` custom check: `no such key: severity`.",
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "invalid expression",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck("invalid", `rule.name`, "", checks.Bug)
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  2,
+						},
+						Reporter: checks.CustomCheckName,
+						Text:     "Failed to compile `invalid` custom check: `expression must return a bool value, got string`.",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -92,7 +92,7 @@ func TestRuleDependencyCheck(t *testing.T) {
 					"prom",
 					uri,
 					[]*promapi.Prometheus{
-						promapi.NewPrometheus("prom", uri, "", map[string]string{"X-Debug": "1"}, time.Second, 16, 1000, nil),
+						promapi.NewPrometheus("prom", uri, "", map[string]string{"X-Debug": "1"}, time.Second, 16, 1000, nil, nil, nil, promapi.RetryPolicy{}),
 					},
 					true,
 					"up",
@@ -161,7 +161,7 @@ func TestRuleDuplicateCheck(t *testing.T) {
 					"prom",
 					uri,
 					[]*promapi.Prometheus{
-						promapi.NewPrometheus("prom", uri, "", map[string]string{}, time.Second, 4, 100, nil),
+						promapi.NewPrometheus("prom", uri, "", map[string]string{}, time.Second, 4, 100, nil, nil, nil, promapi.RetryPolicy{}),
 					},
 					true,
 					"up",
@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	RuleHealthCheckName = "rule/health"
+
+	// Report rules that take at least this much of the group interval to evaluate.
+	ruleHealthSlowRatio = 0.8
+)
+
+func NewRuleHealthCheck(runtime discovery.RuleRuntime) RuleHealthCheck {
+	return RuleHealthCheck{runtime: runtime}
+}
+
+// RuleHealthCheck reports problems with rules that Prometheus had while
+// evaluating them, it only works for rules loaded from the rules API.
+type RuleHealthCheck struct {
+	runtime discovery.RuleRuntime
+}
+
+func (c RuleHealthCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		IsOnline: true,
+	}
+}
+
+func (c RuleHealthCheck) String() string {
+	return fmt.Sprintf("%s(%s)", RuleHealthCheckName, c.runtime.Prometheus)
+}
+
+func (c RuleHealthCheck) Reporter() string {
+	return RuleHealthCheckName
+}
+
+func (c RuleHealthCheck) Check(_ context.Context, _ string, rule parser.Rule, _ []discovery.Entry) (problems []Problem) {
+	lines := rule.Lines
+	if rule.AlertingRule != nil {
+		lines = rule.AlertingRule.Alert.Lines
+	}
+	if rule.RecordingRule != nil {
+		lines = rule.RecordingRule.Record.Lines
+	}
+
+	if c.runtime.Health == promapi.RuleHealthErr || c.runtime.LastError != "" {
+		problems = append(problems, Problem{
+			Lines:    lines,
+			Reporter: c.Reporter(),
+			Text:     fmt.Sprintf("Prometheus server %q failed to evaluate this rule.", c.runtime.Prometheus),
+			Details:  c.runtime.LastError,
+			Severity: Bug,
+		})
+	}
+
+	if c.runtime.GroupInterval > 0 && c.runtime.EvaluationTime >= time.Duration(float64(c.runtime.GroupInterval)*ruleHealthSlowRatio) {
+		problems = append(problems, Problem{
+			Lines:    lines,
+			Reporter: c.Reporter(),
+			Text: fmt.Sprintf("Prometheus server %q took %s to evaluate this rule, which is close to the group evaluation interval of %s.",
+				c.runtime.Prometheus, output.HumanizeDuration(c.runtime.EvaluationTime), output.HumanizeDuration(c.runtime.GroupInterval)),
+			Details:  "Rules that take longer to evaluate than the group interval will cause missed evaluations.",
+			Severity: Warning,
+		})
+	}
+
+	return problems
+}
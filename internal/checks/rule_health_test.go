@@ -0,0 +1,112 @@
+package checks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestRuleHealthCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "healthy rule",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRuleHealthCheck(discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         promapi.RuleHealthOK,
+					EvaluationTime: time.Second,
+					GroupInterval:  time.Minute,
+				})
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "unknown health",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRuleHealthCheck(discovery.RuleRuntime{
+					Prometheus: "prom",
+					Health:     promapi.RuleHealthUnknown,
+				})
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+		{
+			description: "failed evaluation",
+			content:     "- alert: foo\n  expr: sum(foo) > 0\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRuleHealthCheck(discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         promapi.RuleHealthErr,
+					LastError:      "query timed out in expression evaluation",
+					EvaluationTime: time.Second,
+					GroupInterval:  time.Minute,
+				})
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  1,
+						},
+						Reporter: "rule/health",
+						Text:     `Prometheus server "prom" failed to evaluate this rule.`,
+						Details:  "query timed out in expression evaluation",
+						Severity: checks.Bug,
+					},
+				}
+			},
+		},
+		{
+			description: "slow evaluation",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRuleHealthCheck(discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         promapi.RuleHealthOK,
+					EvaluationTime: time.Second * 50,
+					GroupInterval:  time.Minute,
+				})
+			},
+			prometheus: noProm,
+			problems: func(_ string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Lines: parser.LineRange{
+							First: 1,
+							Last:  1,
+						},
+						Reporter: "rule/health",
+						Text:     `Prometheus server "prom" took 50s to evaluate this rule, which is close to the group evaluation interval of 1m.`,
+						Details:  "Rules that take longer to evaluate than the group interval will cause missed evaluations.",
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "no group interval",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRuleHealthCheck(discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         promapi.RuleHealthOK,
+					EvaluationTime: time.Second * 50,
+				})
+			},
+			prometheus: noProm,
+			problems:   noProblems,
+		},
+	}
+
+	runTests(t, testCases)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Alertmanager struct {
+	Headers map[string]string `hcl:"headers,optional" json:"headers,omitempty"`
+	Labels  map[string]string `hcl:"labels,optional" json:"labels,omitempty"`
+	URI     string            `hcl:"uri" json:"uri"`
+	Timeout string            `hcl:"timeout,optional" json:"timeout"`
+}
+
+func (am Alertmanager) validate() error {
+	if am.URI == "" {
+		return errors.New("alertmanager uri cannot be empty")
+	}
+	if _, err := url.Parse(am.URI); err != nil {
+		return fmt.Errorf("invalid alertmanager uri: %w", err)
+	}
+	if am.Timeout != "" {
+		if _, err := parseDuration(am.Timeout); err != nil {
+			return err
+		}
+	}
+	for k := range am.Labels {
+		if k == "" {
+			return errors.New("alertmanager label name cannot be empty")
+		}
+	}
+	return nil
+}
+
+func (am *Alertmanager) applyDefaults() {
+	if am.Timeout == "" {
+		am.Timeout = time.Minute.String()
+	}
+}
+
+// TimeoutDuration returns the timeout for requests sent to Alertmanager.
+func (am Alertmanager) TimeoutDuration() time.Duration {
+	timeout, _ := parseDuration(am.Timeout)
+	return timeout
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerSettings(t *testing.T) {
+	type testCaseT struct {
+		conf    Alertmanager
+		err     error
+		timeout time.Duration
+	}
+
+	testCases := []testCaseT{
+		{
+			conf:    Alertmanager{URI: "http://localhost:9093"},
+			timeout: time.Minute,
+		},
+		{
+			conf:    Alertmanager{URI: "http://localhost:9093", Timeout: "15s"},
+			timeout: time.Second * 15,
+		},
+		{
+			conf:    Alertmanager{URI: "http://localhost:9093", Labels: map[string]string{"source": "pint"}},
+			timeout: time.Minute,
+		},
+		{
+			conf: Alertmanager{},
+			err:  errors.New("alertmanager uri cannot be empty"),
+		},
+		{
+			conf: Alertmanager{URI: "http://%41:8080/"},
+			err:  errors.New(`invalid alertmanager uri: parse "http://%41:8080/": invalid URL escape "%41"`),
+		},
+		{
+			conf: Alertmanager{URI: "http://localhost:9093", Timeout: "foo"},
+			err:  errors.New(`not a valid duration string: "foo"`),
+		},
+		{
+			conf: Alertmanager{URI: "http://localhost:9093", Labels: map[string]string{"": "pint"}},
+			err:  errors.New("alertmanager label name cannot be empty"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if tc.err == nil {
+				require.NoError(t, err)
+				tc.conf.applyDefaults()
+				require.Equal(t, tc.timeout, tc.conf.TimeoutDuration())
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const secretMask = "xxx"
+
+// Secret is a string that will never be included in JSON output.
+type Secret string
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return json.Marshal("")
+	}
+	return json.Marshal(secretMask)
+}
+
+type BasicAuthConfig struct {
+	Username     string `hcl:"username" json:"username"`
+	Password     Secret `hcl:"password,optional" json:"password,omitempty"`
+	PasswordFile string `hcl:"passwordFile,optional" json:"passwordFile,omitempty"`
+}
+
+func (ba BasicAuthConfig) validate() error {
+	if ba.Username == "" {
+		return errors.New("basic auth username cannot be empty")
+	}
+	if (ba.Password != "") == (ba.PasswordFile != "") {
+		return errors.New("basic auth requires exactly one of password or passwordFile to be set")
+	}
+	return nil
+}
+
+type BearerTokenConfig struct {
+	Token     Secret `hcl:"token,optional" json:"token,omitempty"`
+	TokenFile string `hcl:"tokenFile,optional" json:"tokenFile,omitempty"`
+}
+
+func (bt BearerTokenConfig) validate() error {
+	if (bt.Token != "") == (bt.TokenFile != "") {
+		return errors.New("bearer token auth requires exactly one of token or tokenFile to be set")
+	}
+	return nil
+}
+
+type OAuth2Config struct {
+	EndpointParams   map[string]string `hcl:"endpointParams,optional" json:"endpointParams,omitempty"`
+	ClientID         string            `hcl:"clientID" json:"clientID"`
+	ClientSecret     Secret            `hcl:"clientSecret,optional" json:"clientSecret,omitempty"`
+	ClientSecretFile string            `hcl:"clientSecretFile,optional" json:"clientSecretFile,omitempty"`
+	TokenURL         string            `hcl:"tokenURL" json:"tokenURL"`
+	Scopes           []string          `hcl:"scopes,optional" json:"scopes,omitempty"`
+}
+
+func (o OAuth2Config) validate() error {
+	if o.ClientID == "" {
+		return errors.New("oauth2 clientID cannot be empty")
+	}
+	if (o.ClientSecret != "") == (o.ClientSecretFile != "") {
+		return errors.New("oauth2 auth requires exactly one of clientSecret or clientSecretFile to be set")
+	}
+	if o.TokenURL == "" {
+		return errors.New("oauth2 tokenURL cannot be empty")
+	}
+	if _, err := url.Parse(o.TokenURL); err != nil {
+		return fmt.Errorf("oauth2 tokenURL is invalid: %w", err)
+	}
+	return nil
+}
+
+type SigV4Config struct {
+	Region    string `hcl:"region,optional" json:"region,omitempty"`
+	AccessKey string `hcl:"accessKey,optional" json:"accessKey,omitempty"`
+	SecretKey Secret `hcl:"secretKey,optional" json:"secretKey,omitempty"`
+	Profile   string `hcl:"profile,optional" json:"profile,omitempty"`
+	RoleARN   string `hcl:"roleARN,optional" json:"roleARN,omitempty"`
+}
+
+func (s SigV4Config) validate() error {
+	if (s.AccessKey == "") != (s.SecretKey == "") {
+		return errors.New("sigv4 accessKey and secretKey must be set together")
+	}
+	return nil
+}
+
+type AuthConfig struct {
+	Basic  *BasicAuthConfig   `hcl:"basic,block" json:"basic,omitempty"`
+	Bearer *BearerTokenConfig `hcl:"bearer,block" json:"bearer,omitempty"`
+	OAuth2 *OAuth2Config      `hcl:"oauth2,block" json:"oauth2,omitempty"`
+	SigV4  *SigV4Config       `hcl:"sigv4,block" json:"sigv4,omitempty"`
+}
+
+func (ac AuthConfig) validate() error {
+	var count int
+	if ac.Basic != nil {
+		count++
+		if err := ac.Basic.validate(); err != nil {
+			return err
+		}
+	}
+	if ac.Bearer != nil {
+		count++
+		if err := ac.Bearer.validate(); err != nil {
+			return err
+		}
+	}
+	if ac.OAuth2 != nil {
+		count++
+		if err := ac.OAuth2.validate(); err != nil {
+			return err
+		}
+	}
+	if ac.SigV4 != nil {
+		count++
+		if err := ac.SigV4.validate(); err != nil {
+			return err
+		}
+	}
+	if count != 1 {
+		return errors.New("auth block must have exactly one of basic, bearer, oauth2 or sigv4 blocks")
+	}
+	return nil
+}
+
+func (ac *AuthConfig) toAuthenticator() promapi.Authenticator {
+	switch {
+	case ac == nil:
+		return nil
+	case ac.Basic != nil:
+		return promapi.BasicAuth{
+			Username:     ac.Basic.Username,
+			Password:     string(ac.Basic.Password),
+			PasswordFile: ac.Basic.PasswordFile,
+		}
+	case ac.Bearer != nil:
+		return promapi.BearerToken{
+			Token:     string(ac.Bearer.Token),
+			TokenFile: ac.Bearer.TokenFile,
+		}
+	case ac.OAuth2 != nil:
+		return promapi.OAuth2{
+			ClientID:         ac.OAuth2.ClientID,
+			ClientSecret:     string(ac.OAuth2.ClientSecret),
+			ClientSecretFile: ac.OAuth2.ClientSecretFile,
+			TokenURL:         ac.OAuth2.TokenURL,
+			Scopes:           ac.OAuth2.Scopes,
+			EndpointParams:   ac.OAuth2.EndpointParams,
+		}
+	case ac.SigV4 != nil:
+		return promapi.SigV4{
+			Region:    ac.SigV4.Region,
+			AccessKey: ac.SigV4.AccessKey,
+			SecretKey: string(ac.SigV4.SecretKey),
+			Profile:   ac.SigV4.Profile,
+			RoleARN:   ac.SigV4.RoleARN,
+		}
+	default:
+		return nil
+	}
+}
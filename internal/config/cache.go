@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/units"
+)
+
+type Cache struct {
+	Dir     string `hcl:"dir" json:"dir"`
+	MaxSize string `hcl:"maxSize,optional" json:"maxSize,omitempty"`
+}
+
+func (c Cache) validate() error {
+	if c.Dir == "" {
+		return errors.New("cache dir cannot be empty")
+	}
+
+	if c.MaxSize != "" {
+		size, err := units.ParseBase2Bytes(c.MaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid cache maxSize %q: %w", c.MaxSize, err)
+		}
+		if size <= 0 {
+			return errors.New("cache maxSize must be > 0")
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) applyDefaults() {
+	if c.MaxSize == "" {
+		c.MaxSize = "1GiB"
+	}
+}
+
+// MaxSizeBytes returns the size limit of the cache directory in bytes.
+func (c Cache) MaxSizeBytes() int64 {
+	size, _ := units.ParseBase2Bytes(c.MaxSize)
+	return int64(size)
+}
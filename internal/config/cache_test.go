@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSettings(t *testing.T) {
+	type testCaseT struct {
+		conf Cache
+		err  error
+		size int64
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: Cache{Dir: ".cache"},
+			size: 1024 * 1024 * 1024,
+		},
+		{
+			conf: Cache{Dir: ".cache", MaxSize: "100MB"},
+			size: 100 * 1024 * 1024,
+		},
+		{
+			conf: Cache{Dir: ".cache", MaxSize: "512KiB"},
+			size: 512 * 1024,
+		},
+		{
+			conf: Cache{},
+			err:  errors.New("cache dir cannot be empty"),
+		},
+		{
+			conf: Cache{Dir: ".cache", MaxSize: "foo"},
+			err:  errors.New(`invalid cache maxSize "foo": units: invalid foo`),
+		},
+		{
+			conf: Cache{Dir: ".cache", MaxSize: "0B"},
+			err:  errors.New("cache maxSize must be > 0"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if tc.err == nil {
+				require.NoError(t, err)
+				tc.conf.applyDefaults()
+				require.Equal(t, tc.size, tc.conf.MaxSizeBytes())
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
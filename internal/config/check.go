@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/cloudflare/pint/internal/checks"
 
@@ -28,6 +29,10 @@ func (c Check) Decode() (s CheckSettings, err error) {
 	case checks.SeriesCheckName:
 		s = &checks.PromqlSeriesSettings{}
 	default:
+		if isExternalCheckName(c.Name) {
+			s = &checks.ExternalCheckSettings{}
+			break
+		}
 		return nil, fmt.Errorf("unknown check %q", c.Name)
 	}
 
@@ -51,3 +56,7 @@ func (c Check) validate() error {
 type CheckSettings interface {
 	Validate() error
 }
+
+func isExternalCheckName(name string) bool {
+	return strings.HasPrefix(name, checks.ExternalCheckPrefix) && len(name) > len(checks.ExternalCheckPrefix)
+}
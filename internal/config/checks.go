@@ -2,15 +2,43 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cloudflare/pint/internal/checks"
 )
 
 type Checks struct {
+	Budget   *Budget  `hcl:"budget,block" json:"budget,omitempty"`
 	Enabled  []string `hcl:"enabled,optional" json:"enabled,omitempty"`
 	Disabled []string `hcl:"disabled,optional" json:"disabled,omitempty"`
 }
 
+// RunBudget returns the maximum time all checks can run for, 0 means no limit.
+func (c Checks) RunBudget() time.Duration {
+	if c.Budget == nil || c.Budget.Run == "" {
+		return 0
+	}
+	d, _ := parseDuration(c.Budget.Run)
+	return d
+}
+
+// CheckBudget returns the maximum time a single check with given name can run
+// for, 0 means no limit.
+func (c Checks) CheckBudget(name string) time.Duration {
+	if c.Budget == nil {
+		return 0
+	}
+	if v, ok := c.Budget.Checks[name]; ok {
+		d, _ := parseDuration(v)
+		return d
+	}
+	if c.Budget.Check == "" {
+		return 0
+	}
+	d, _ := parseDuration(c.Budget.Check)
+	return d
+}
+
 func (c Checks) validate() error {
 	for _, name := range c.Enabled {
 		if err := validateCheckName(name); err != nil {
@@ -22,15 +50,61 @@ func (c Checks) validate() error {
 			return err
 		}
 	}
+	if c.Budget != nil {
+		if err := c.Budget.validate(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+type Budget struct {
+	Checks map[string]string `hcl:"checks,optional" json:"checks,omitempty"`
+	Run    string            `hcl:"run,optional" json:"run,omitempty"`
+	Check  string            `hcl:"check,optional" json:"check,omitempty"`
+}
+
+func (b Budget) validate() error {
+	if err := validateBudget("run", b.Run); err != nil {
+		return err
+	}
+	if err := validateBudget("check", b.Check); err != nil {
+		return err
+	}
+	for name, v := range b.Checks {
+		if err := validateCheckName(name); err != nil {
+			return err
+		}
+		if err := validateBudget(name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateBudget(name, v string) error {
+	if v == "" {
+		return nil
+	}
+	d, err := parseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s budget: %w", name, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%s budget must be > 0", name)
+	}
+	return nil
+}
+
 func validateCheckName(name string) error {
 	for _, c := range checks.CheckNames {
 		if name == c {
 			return nil
 		}
 	}
+	if isExternalCheckName(name) {
+		return nil
+	}
 	return fmt.Errorf("unknown check name %s", name)
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +37,39 @@ func TestChecksSettings(t *testing.T) {
 				Disabled: []string{"promql/syntax"},
 			},
 		},
+		{
+			conf: Checks{
+				Budget: &Budget{
+					Run:    "10m",
+					Check:  "30s",
+					Checks: map[string]string{"promql/series": "1m"},
+				},
+			},
+		},
+		{
+			conf: Checks{
+				Budget: &Budget{Run: "foo"},
+			},
+			err: errors.New(`invalid run budget: not a valid duration string: "foo"`),
+		},
+		{
+			conf: Checks{
+				Budget: &Budget{Check: "0s"},
+			},
+			err: errors.New("check budget must be > 0"),
+		},
+		{
+			conf: Checks{
+				Budget: &Budget{Checks: map[string]string{"foo": "1m"}},
+			},
+			err: errors.New("unknown check name foo"),
+		},
+		{
+			conf: Checks{
+				Budget: &Budget{Checks: map[string]string{"promql/series": "1x"}},
+			},
+			err: errors.New(`invalid promql/series budget: unknown unit "x" in duration "1x"`),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -49,3 +83,47 @@ func TestChecksSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestChecksBudget(t *testing.T) {
+	type testCaseT struct {
+		conf  Checks
+		run   time.Duration
+		check map[string]time.Duration
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: Checks{},
+			check: map[string]time.Duration{
+				"promql/series": 0,
+			},
+		},
+		{
+			conf: Checks{Budget: &Budget{Run: "10m"}},
+			run:  time.Minute * 10,
+			check: map[string]time.Duration{
+				"promql/series": 0,
+			},
+		},
+		{
+			conf: Checks{Budget: &Budget{
+				Check:  "30s",
+				Checks: map[string]string{"promql/series": "1m"},
+			}},
+			check: map[string]time.Duration{
+				"promql/series": time.Minute,
+				"query/cost":    time.Second * 30,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			require.NoError(t, tc.conf.validate())
+			require.Equal(t, tc.run, tc.conf.RunBudget())
+			for name, d := range tc.check {
+				require.Equal(t, d, tc.conf.CheckBudget(name), name)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsimple"
@@ -22,15 +23,17 @@ import (
 )
 
 type Config struct {
-	CI         *CI                `hcl:"ci,block" json:"ci,omitempty"`
-	Parser     *Parser            `hcl:"parser,block" json:"parser,omitempty"`
-	Repository *Repository        `hcl:"repository,block" json:"repository,omitempty"`
-	Discovery  *Discovery         `hcl:"discovery,block" json:"discovery,omitempty"`
-	Checks     *Checks            `hcl:"checks,block" json:"checks,omitempty"`
-	Owners     *Owners            `hcl:"owners,block" json:"owners,omitempty"`
-	Prometheus []PrometheusConfig `hcl:"prometheus,block" json:"prometheus,omitempty"`
-	Check      []Check            `hcl:"check,block" json:"check,omitempty"`
-	Rules      []Rule             `hcl:"rule,block" json:"rules,omitempty"`
+	CI           *CI                `hcl:"ci,block" json:"ci,omitempty"`
+	Parser       *Parser            `hcl:"parser,block" json:"parser,omitempty"`
+	Repository   *Repository        `hcl:"repository,block" json:"repository,omitempty"`
+	Discovery    *Discovery         `hcl:"discovery,block" json:"discovery,omitempty"`
+	Cache        *Cache             `hcl:"cache,block" json:"cache,omitempty"`
+	Alertmanager *Alertmanager      `hcl:"alertmanager,block" json:"alertmanager,omitempty"`
+	Checks       *Checks            `hcl:"checks,block" json:"checks,omitempty"`
+	Owners       *Owners            `hcl:"owners,block" json:"owners,omitempty"`
+	Prometheus   []PrometheusConfig `hcl:"prometheus,block" json:"prometheus,omitempty"`
+	Check        []Check            `hcl:"check,block" json:"check,omitempty"`
+	Rules        []Rule             `hcl:"rule,block" json:"rules,omitempty"`
 }
 
 func (cfg *Config) DisableOnlineChecks() {
@@ -60,6 +63,11 @@ func (cfg *Config) SetDisabledChecks(l []string) {
 				disabled[name] = struct{}{}
 			}
 		}
+		for _, name := range cfg.externalCheckNames() {
+			if re.MatchString(name) {
+				disabled[name] = struct{}{}
+			}
+		}
 	}
 	for name := range disabled {
 		var found bool
@@ -75,6 +83,15 @@ func (cfg *Config) SetDisabledChecks(l []string) {
 	}
 }
 
+func (cfg Config) externalCheckNames() (names []string) {
+	for _, chk := range cfg.Check {
+		if isExternalCheckName(chk.Name) {
+			names = append(names, chk.Name)
+		}
+	}
+	return names
+}
+
 func (cfg Config) String() string {
 	content, _ := json.MarshalIndent(cfg, "", "  ")
 	return string(content)
@@ -115,6 +132,13 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, gen *PrometheusGenerato
 	}
 
 	proms := gen.ServersForPath(entry.SourcePath)
+	if entry.Runtime != nil {
+		// Rules fetched from the rules API are only checked against the
+		// server they were loaded from.
+		proms = slices.DeleteFunc(proms, func(p *promapi.FailoverGroup) bool {
+			return p.Name() != entry.Runtime.Prometheus
+		})
+	}
 
 	for _, p := range proms {
 		allChecks = append(allChecks, checkMeta{
@@ -154,6 +178,20 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, gen *PrometheusGenerato
 		})
 	}
 
+	if entry.Runtime != nil {
+		allChecks = append(allChecks, checkMeta{
+			name:  checks.RuleHealthCheckName,
+			check: checks.NewRuleHealthCheck(*entry.Runtime),
+		})
+	}
+
+	for _, name := range cfg.externalCheckNames() {
+		allChecks = append(allChecks, checkMeta{
+			name:  name,
+			check: checks.NewExternalCheck(name),
+		})
+	}
+
 	for _, rule := range cfg.Rules {
 		allChecks = append(allChecks, rule.resolveChecks(ctx, entry.SourcePath, entry.Rule, proms)...)
 	}
@@ -209,8 +247,10 @@ func getContext() *hcl.EvalContext {
 	return &hcl.EvalContext{Variables: vars}
 }
 
-func Load(path string, failOnMissing bool) (cfg Config, err error) {
-	cfg = Config{
+// Default returns a config with all default values, as used when there
+// is no config file.
+func Default() Config {
+	return Config{
 		CI: &CI{
 			MaxCommits: 20,
 			BaseBranch: "master",
@@ -225,6 +265,10 @@ func Load(path string, failOnMissing bool) (cfg Config, err error) {
 			Allowed: []string{},
 		},
 	}
+}
+
+func Load(path string, failOnMissing bool) (cfg Config, err error) {
+	cfg = Default()
 
 	if _, err = os.Stat(path); err == nil || failOnMissing {
 		slog.Info("Loading configuration file", slog.String("path", path))
@@ -235,21 +279,39 @@ func Load(path string, failOnMissing bool) (cfg Config, err error) {
 		}
 	}
 
+	err = cfg.Validate()
+	return cfg, err
+}
+
+// Parse config from HCL source, filename is only used for error messages
+// and to detect the syntax, so it must have either .hcl or .json suffix.
+func Parse(filename string, src []byte) (cfg Config, err error) {
+	cfg = Default()
+
+	if err = hclsimple.Decode(filename, src, getContext(), &cfg); err != nil {
+		return cfg, err
+	}
+
+	err = cfg.Validate()
+	return cfg, err
+}
+
+func (cfg *Config) Validate() (err error) {
 	if cfg.CI != nil {
 		if err = cfg.CI.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
 	if cfg.Owners != nil {
 		if err = cfg.Owners.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
 	if cfg.Parser != nil {
 		if err = cfg.Parser.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
@@ -258,7 +320,7 @@ func Load(path string, failOnMissing bool) (cfg Config, err error) {
 			cfg.Repository.BitBucket.Timeout = time.Minute.String()
 		}
 		if err = cfg.Repository.BitBucket.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
@@ -267,53 +329,73 @@ func Load(path string, failOnMissing bool) (cfg Config, err error) {
 			cfg.Repository.GitHub.Timeout = time.Minute.String()
 		}
 		if err = cfg.Repository.GitHub.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
 	if cfg.Checks != nil {
 		if err = cfg.Checks.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
 	for _, chk := range cfg.Check {
 		if err = chk.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
+	// External checks are enabled by default, unless there's an explicit
+	// list of enabled checks.
+	if cfg.Checks != nil && slices.Equal(cfg.Checks.Enabled, checks.CheckNames) {
+		cfg.Checks.Enabled = append(slices.Clone(checks.CheckNames), cfg.externalCheckNames()...)
+	}
+
 	promNames := make([]string, 0, len(cfg.Prometheus))
 	for i, prom := range cfg.Prometheus {
 		if err = prom.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 
 		if slices.Contains(promNames, prom.Name) {
-			return cfg, fmt.Errorf("prometheus server name must be unique, found two or more config blocks using %q name", prom.Name)
+			return fmt.Errorf("prometheus server name must be unique, found two or more config blocks using %q name", prom.Name)
 		}
 		promNames = append(promNames, prom.Name)
 
 		cfg.Prometheus[i].applyDefaults()
 
 		if _, err = prom.TLS.toHTTPConfig(); err != nil {
-			return cfg, fmt.Errorf("invalid prometheus TLS configuration: %w", err)
+			return fmt.Errorf("invalid prometheus TLS configuration: %w", err)
 		}
 	}
 
 	if cfg.Discovery != nil {
 		if err = cfg.Discovery.validate(); err != nil {
-			return cfg, err
+			return err
+		}
+	}
+
+	if cfg.Cache != nil {
+		if err = cfg.Cache.validate(); err != nil {
+			return err
+		}
+		cfg.Cache.applyDefaults()
+	}
+
+	if cfg.Alertmanager != nil {
+		if err = cfg.Alertmanager.validate(); err != nil {
+			return err
 		}
+		cfg.Alertmanager.applyDefaults()
 	}
 
 	for _, rule := range cfg.Rules {
 		if err = rule.validate(); err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
-	return cfg, nil
+	return nil
 }
 
 func parseDuration(d string) (time.Duration, error) {
@@ -33,6 +33,23 @@ func TestConfigLoadMissingFileOk(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestConfigParse(t *testing.T) {
+	cfg, err := config.Parse("pint.hcl", []byte(`parser {
+  relaxed = ["foo"]
+}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, cfg.Parser.Relaxed)
+	require.Equal(t, checks.CheckNames, cfg.Checks.Enabled)
+
+	_, err = config.Parse("pint.hcl", []byte(`prometheus "prom" {}`))
+	require.EqualError(t, err, "prometheus URI cannot be empty")
+
+	_, err = config.Parse("pint.hcl", []byte(`prometheus "prom" {
+  uri = ""
+}`))
+	require.EqualError(t, err, "prometheus URI cannot be empty")
+}
+
 func TestDisableOnlineChecksWithPrometheus(t *testing.T) {
 	dir := t.TempDir()
 	path := path.Join(dir, "config.hcl")
@@ -703,6 +720,129 @@ rule {
 				checks.RejectCheckName + "(val=~'^$')",
 			},
 		},
+		{
+			title: "custom checks",
+			config: `
+rule {
+  match {
+    kind = "recording"
+  }
+  check "custom" "prefix" {
+    expression = "rule.name.startsWith('team_x:')"
+    message    = "Recording rule {{ $record }} must use team_x: prefix."
+  }
+}
+rule {
+  match {
+    kind = "alerting"
+  }
+  check "custom" "runbook" {
+    expression = "'runbook' in rule.annotations"
+    severity   = "warning"
+  }
+}
+`,
+			entry: discovery.Entry{
+				State:      discovery.Modified,
+				SourcePath: "rules.yml",
+				Rule:       newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			},
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.CustomCheckName + "(prefix)",
+			},
+		},
+		{
+			title: "external check",
+			config: `
+check "external/naming" {
+  command = ["./naming.sh"]
+}
+`,
+			entry: discovery.Entry{
+				State:      discovery.Modified,
+				SourcePath: "rules.yml",
+				Rule:       newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			},
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				"external/naming",
+			},
+		},
+		{
+			title: "external check / disabled",
+			config: `
+check "external/naming" {
+  command = ["./naming.sh"]
+}
+checks {
+  disabled = ["external/naming"]
+}
+`,
+			entry: discovery.Entry{
+				State:      discovery.Modified,
+				SourcePath: "rules.yml",
+				Rule:       newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			},
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+			},
+		},
+		{
+			title: "external check / not in enabled list",
+			config: `
+check "external/naming" {
+  command = ["./naming.sh"]
+}
+checks {
+  enabled = ["promql/syntax"]
+}
+`,
+			entry: discovery.Entry{
+				State:      discovery.Modified,
+				SourcePath: "rules.yml",
+				Rule:       newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			},
+			checks: []string{
+				checks.SyntaxCheckName,
+			},
+		},
+		{
+			title: "external check / disabled by comment",
+			config: `
+check "external/naming" {
+  command = ["./naming.sh"]
+}
+`,
+			entry: discovery.Entry{
+				State:      discovery.Modified,
+				SourcePath: "rules.yml",
+				Rule:       newRule(t, "# pint disable external/naming\n- record: foo\n  expr: sum(foo)\n"),
+			},
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+			},
+		},
 		{
 			title: "rule with label match / type mismatch",
 			config: `
@@ -1386,6 +1526,25 @@ rule {
 				checks.RuleLinkCheckName + "(^https?://(.+)$)",
 			},
 		},
+		{
+			title:  "rule loaded from Prometheus",
+			config: "",
+			entry: discovery.Entry{
+				State:      discovery.Noop,
+				SourcePath: "prom:/etc/prometheus/rules.yml",
+				Rule:       newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+				Runtime:    &discovery.RuleRuntime{Prometheus: "prom", Health: "ok"},
+			},
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.RuleHealthCheckName + "(prom)",
+			},
+		},
 		{
 			title: "two prometheus servers / disable checks via file/disable comment",
 			config: `
@@ -1760,6 +1919,13 @@ func TestConfigErrors(t *testing.T) {
 			config: `checks { enabled = ["foo"] }`,
 			err:    "unknown check name foo",
 		},
+		{
+			config: `alertmanager {
+  uri     = "http://localhost:9093"
+  timeout = "abc"
+}`,
+			err: `not a valid duration string: "abc"`,
+		},
 		{
 			config: `prometheus "prom" {
   uri     = "http://localhost"
@@ -1915,6 +2081,34 @@ func TestConfigErrors(t *testing.T) {
 			config: `check "promql/series " {}`,
 			err:    `unknown check "promql/series "`,
 		},
+		{
+			config: `check "external/" { command = ["foo"] }`,
+			err:    `unknown check "external/"`,
+		},
+		{
+			config: `check "external/foo" { command = [] }`,
+			err:    "external check command cannot be empty",
+		},
+		{
+			config: `check "external/foo" {
+  command = ["foo"]
+  timeout = "abc"
+}`,
+			err: `not a valid duration string: "abc"`,
+		},
+		{
+			config: `check "external/foo" {
+  command  = ["foo"]
+  severity = "abc"
+}`,
+			err: "unknown severity: abc",
+		},
+		{
+			config: `checks {
+  enabled = ["external/"]
+}`,
+			err: "unknown check name external/",
+		},
 		{
 			config: `check "promql/series" { ignoreMetrics = [".+++"] }`,
 			err:    "error parsing regexp: invalid nested repetition operator: `++`",
@@ -2003,6 +2197,48 @@ func TestConfigErrors(t *testing.T) {
 }`,
 			err: "must set either min or max option, or both",
 		},
+		{
+			config: `rule {
+  check "external" "foo" {
+    expression = "true"
+  }
+}`,
+			err: `unknown check kind "external", only "custom" checks can be defined inside rule blocks`,
+		},
+		{
+			config: `rule {
+  check "custom" "foo" {
+    expression = "rule.name"
+  }
+}`,
+			err: `invalid "foo" custom check expression: expression must return a bool value, got string`,
+		},
+		{
+			config: `rule {
+  check "custom" "foo" {
+    expression = "rule.name == 1"
+  }
+}`,
+			err: "invalid \"foo\" custom check expression: ERROR: <input>:1:11: found no matching overload for '_==_' applied to '(string, int)'\n | rule.name == 1\n | ..........^",
+		},
+		{
+			config: `rule {
+  check "custom" "foo" {
+    expression = "true"
+    message    = "{{ $alert"
+  }
+}`,
+			err: `invalid "foo" custom check message: template: regexp:1: unclosed action`,
+		},
+		{
+			config: `rule {
+  check "custom" "foo" {
+    expression = "true"
+    severity   = "foo"
+  }
+}`,
+			err: "unknown severity: foo",
+		},
 		{
 			config: `owners {
   allowed = [".+++"]
@@ -12,6 +12,7 @@ type CostSettings struct {
 	MaxSeries             int    `hcl:"maxSeries,optional" json:"maxSeries,omitempty"`
 	MaxPeakSamples        int    `hcl:"maxPeakSamples,optional" json:"maxPeakSamples,omitempty"`
 	MaxTotalSamples       int    `hcl:"maxTotalSamples,optional" json:"maxTotalSamples,omitempty"`
+	MaxSelectorSeries     int    `hcl:"maxSelectorSeries,optional" json:"maxSelectorSeries,omitempty"`
 }
 
 func (cs CostSettings) validate() error {
@@ -29,6 +30,9 @@ func (cs CostSettings) validate() error {
 	if cs.MaxPeakSamples < 0 {
 		return fmt.Errorf("maxPeakSamples value must be >= 0")
 	}
+	if cs.MaxSelectorSeries < 0 {
+		return fmt.Errorf("maxSelectorSeries value must be >= 0")
+	}
 	if cs.MaxEvaluationDuration != "" {
 		if _, err := parseDuration(cs.MaxEvaluationDuration); err != nil {
 			return err
@@ -43,6 +43,12 @@ func TestCostSettings(t *testing.T) {
 			},
 			err: errors.New("maxTotalSamples value must be >= 0"),
 		},
+		{
+			conf: CostSettings{
+				MaxSelectorSeries: -1,
+			},
+			err: errors.New("maxSelectorSeries value must be >= 0"),
+		},
 		{
 			conf: CostSettings{
 				MaxEvaluationDuration: "1abc",
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+const CustomCheckKind = "custom"
+
+type CustomCheckSettings struct {
+	Kind       string `hcl:",label" json:"kind"`
+	Name       string `hcl:",label" json:"name"`
+	Expression string `hcl:"expression" json:"expression"`
+	Message    string `hcl:"message,optional" json:"message,omitempty"`
+	Severity   string `hcl:"severity,optional" json:"severity,omitempty"`
+	// Compiled expression, set by validate so it's not compiled for every rule.
+	program cel.Program
+}
+
+func (cs *CustomCheckSettings) validate() error {
+	if cs.Kind != CustomCheckKind {
+		return fmt.Errorf("unknown check kind %q, only %q checks can be defined inside rule blocks", cs.Kind, CustomCheckKind)
+	}
+
+	if cs.Name == "" {
+		return errors.New("custom check name cannot be empty")
+	}
+
+	prg, err := checks.CompileCustomCheck(cs.Expression)
+	if err != nil {
+		return fmt.Errorf("invalid %q custom check expression: %w", cs.Name, err)
+	}
+	cs.program = prg
+
+	if cs.Message != "" {
+		if err := checks.ParseCustomCheckMessage(cs.Message); err != nil {
+			return fmt.Errorf("invalid %q custom check message: %w", cs.Name, err)
+		}
+	}
+
+	if cs.Severity != "" {
+		if _, err := checks.ParseSeverity(cs.Severity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs CustomCheckSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if cs.Severity != "" {
+		sev, _ := checks.ParseSeverity(cs.Severity)
+		return sev
+	}
+	return fallback
+}
+
+func (cs CustomCheckSettings) newCheck() checks.CustomCheck {
+	severity := cs.getSeverity(checks.Bug)
+	if cs.program == nil {
+		return checks.NewCustomCheck(cs.Name, cs.Expression, cs.Message, severity)
+	}
+	return checks.NewCompiledCustomCheck(cs.Name, cs.Expression, cs.Message, cs.program, severity)
+}
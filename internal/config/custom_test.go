@@ -0,0 +1,19 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomCheckSettingsProgram(t *testing.T) {
+	rule := Rule{
+		Custom: []CustomCheckSettings{
+			{Kind: CustomCheckKind, Name: "name", Expression: `rule.name != ""`},
+		},
+	}
+	require.Nil(t, rule.Custom[0].program)
+
+	require.NoError(t, rule.validate())
+	require.NotNil(t, rule.Custom[0].program, "validate() should store compiled program")
+}
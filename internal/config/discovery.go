@@ -3,17 +3,24 @@ package config
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 )
@@ -59,6 +66,8 @@ func isEqualFailoverGroup(a, b *promapi.FailoverGroup) bool {
 type Discovery struct {
 	FilePath        []FilePath        `hcl:"filepath,block" json:"filepath,omitempty"`
 	PrometheusQuery []PrometheusQuery `hcl:"prometheusQuery,block" json:"prometheusQuery,omitempty"`
+	FileSD          []FileSD          `hcl:"fileSD,block" json:"fileSD,omitempty"`
+	HTTPSD          []HTTPSD          `hcl:"httpSD,block" json:"httpSD,omitempty"`
 }
 
 func (d Discovery) validate() (err error) {
@@ -72,6 +81,16 @@ func (d Discovery) validate() (err error) {
 			return err
 		}
 	}
+	for _, fsd := range d.FileSD {
+		if err = fsd.validate(); err != nil {
+			return err
+		}
+	}
+	for _, hsd := range d.HTTPSD {
+		if err = hsd.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -98,6 +117,19 @@ func (d *Discovery) Discover(ctx context.Context) ([]*promapi.FailoverGroup, err
 			return nil, err
 		}
 	}
+	for _, pd := range d.FileSD {
+		servers, err = d.discover(ctx, pd, servers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// httpSD blocks remember the last response, so we need to pass a pointer.
+	for i := range d.HTTPSD {
+		servers, err = d.discover(ctx, &d.HTTPSD[i], servers)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return servers, nil
 }
 
@@ -120,9 +152,12 @@ func (d *Discovery) merge(dst, src []*promapi.FailoverGroup) ([]*promapi.Failove
 type PrometheusTemplate struct {
 	Headers     map[string]string `hcl:"headers,optional" json:"headers,omitempty"`
 	TLS         *TLSConfig        `hcl:"tls,block" json:"tls,omitempty"`
+	Auth        *AuthConfig       `hcl:"auth,block" json:"auth,omitempty"`
+	Retry       *RetryConfig      `hcl:"retry,block" json:"retry,omitempty"`
 	Name        string            `hcl:"name" json:"name"`
 	URI         string            `hcl:"uri" json:"uri"`
 	PublicURI   string            `hcl:"publicURI,optional" json:"publicURI,omitempty"`
+	Proxy       string            `hcl:"proxy,optional" json:"proxy,omitempty"`
 	Timeout     string            `hcl:"timeout,optional"  json:"timeout"`
 	Uptime      string            `hcl:"uptime,optional" json:"uptime"`
 	Failover    []string          `hcl:"failover,optional" json:"failover,omitempty"`
@@ -155,6 +190,24 @@ func (pt PrometheusTemplate) validate() (err error) {
 		}
 	}
 
+	if pt.Auth != nil {
+		if err := pt.Auth.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pt.Proxy != "" {
+		if err := validateProxy(pt.Proxy); err != nil {
+			return err
+		}
+	}
+
+	if pt.Retry != nil {
+		if err := pt.Retry.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -246,6 +299,9 @@ func (pt PrometheusTemplate) Render(data map[string]string) (*promapi.FailoverGr
 		Tags:        tags,
 		Required:    pt.Required,
 		TLS:         pt.TLS,
+		Auth:        pt.Auth,
+		Proxy:       pt.Proxy,
+		Retry:       pt.Retry,
 	}
 	prom.applyDefaults()
 	if err = prom.validate(); err != nil {
@@ -348,8 +404,10 @@ func (fp FilePath) Discover(_ context.Context) ([]*promapi.FailoverGroup, error)
 type PrometheusQuery struct {
 	URI      string               `hcl:"uri" json:"uri"`
 	Headers  map[string]string    `hcl:"headers,optional" json:"headers,omitempty"`
+	Proxy    string               `hcl:"proxy,optional" json:"proxy,omitempty"`
 	Timeout  string               `hcl:"timeout,optional"  json:"timeout"`
 	TLS      *TLSConfig           `hcl:"tls,block" json:"tls,omitempty"`
+	Auth     *AuthConfig          `hcl:"auth,block" json:"auth,omitempty"`
 	Query    string               `hcl:"query" json:"query"`
 	Template []PrometheusTemplate `hcl:"template,block" json:"template"`
 }
@@ -365,6 +423,16 @@ func (pq PrometheusQuery) validate() (err error) {
 			return err
 		}
 	}
+	if pq.Auth != nil {
+		if err = pq.Auth.validate(); err != nil {
+			return err
+		}
+	}
+	if pq.Proxy != "" {
+		if err = validateProxy(pq.Proxy); err != nil {
+			return err
+		}
+	}
 	if _, err = parser.DecodeExpr(pq.Query); err != nil {
 		return fmt.Errorf("failed to parse prometheus query %q: %w", pq.Query, err)
 	}
@@ -387,7 +455,7 @@ func (pq PrometheusQuery) Discover(ctx context.Context) ([]*promapi.FailoverGrou
 	timeout, _ := parseDuration(pq.Timeout)
 	tls, _ := pq.TLS.toHTTPConfig()
 
-	prom := promapi.NewPrometheus("discovery", pq.URI, "", pq.Headers, timeout, 1, 100, tls)
+	prom := promapi.NewPrometheus("discovery", pq.URI, "", pq.Headers, timeout, 1, 100, tls, parseProxy(pq.Proxy), pq.Auth.toAuthenticator(), promapi.RetryPolicy{})
 	prom.StartWorkers()
 	defer prom.Close()
 
@@ -419,6 +487,242 @@ func (pq PrometheusQuery) Discover(ctx context.Context) ([]*promapi.FailoverGrou
 	return servers, nil
 }
 
+// TargetGroup is a single entry in Prometheus file_sd and http_sd target lists.
+type TargetGroup struct {
+	Labels  map[string]string `json:"labels" yaml:"labels"`
+	Targets []string          `json:"targets" yaml:"targets"`
+}
+
+func renderTargetGroups(groups []TargetGroup, templates []PrometheusTemplate, meta map[string]string) ([]*promapi.FailoverGroup, error) {
+	servers := []*promapi.FailoverGroup{}
+	for _, tg := range groups {
+		for _, target := range tg.Targets {
+			data := make(map[string]string, len(tg.Labels)+len(meta)+1)
+			for k, v := range tg.Labels {
+				data[k] = v
+			}
+			for k, v := range meta {
+				data[k] = v
+			}
+			data["__address__"] = target
+			for _, t := range templates {
+				server, err := t.Render(data)
+				if err != nil {
+					return nil, err
+				}
+				servers = append(servers, server)
+			}
+		}
+	}
+	return servers, nil
+}
+
+type FileSD struct {
+	Files    []string             `hcl:"files" json:"files"`
+	Template []PrometheusTemplate `hcl:"template,block" json:"template"`
+}
+
+func (fsd FileSD) validate() (err error) {
+	if len(fsd.Files) == 0 {
+		return errors.New("fileSD discovery requires at least one file pattern")
+	}
+	for _, pattern := range fsd.Files {
+		if _, err = filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid fileSD file pattern %q: %w", pattern, err)
+		}
+		switch filepath.Ext(pattern) {
+		case ".json", ".yml", ".yaml":
+		default:
+			return fmt.Errorf("fileSD file pattern %q must end with one of .json, .yml or .yaml", pattern)
+		}
+	}
+	if len(fsd.Template) == 0 {
+		return errors.New("fileSD discovery requires at least one template")
+	}
+	for _, t := range fsd.Template {
+		if err = t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fsd FileSD) Discover(_ context.Context) ([]*promapi.FailoverGroup, error) {
+	servers := []*promapi.FailoverGroup{}
+	for _, pattern := range fsd.Files {
+		paths, _ := filepath.Glob(pattern)
+		slog.Info(
+			"Finding Prometheus servers using file_sd files",
+			slog.String("pattern", pattern),
+			slog.Int("files", len(paths)),
+		)
+		for _, path := range paths {
+			groups, err := readTargetGroups(path)
+			if err != nil {
+				return nil, fmt.Errorf("fileSD discovery failed to read %s: %w", path, err)
+			}
+			ss, err := renderTargetGroups(groups, fsd.Template, map[string]string{"__meta_filepath": path})
+			if err != nil {
+				return nil, fmt.Errorf("fileSD discovery failed to generate Prometheus config from a template: %w", err)
+			}
+			servers = append(servers, ss...)
+		}
+	}
+	return servers, nil
+}
+
+func readTargetGroups(path string) (groups []TargetGroup, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch filepath.Ext(path) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(content, &groups)
+	default:
+		err = json.Unmarshal(content, &groups)
+	}
+	return groups, err
+}
+
+type HTTPSD struct {
+	TLS             *TLSConfig           `hcl:"tls,block" json:"tls,omitempty"`
+	Auth            *AuthConfig          `hcl:"auth,block" json:"auth,omitempty"`
+	Headers         map[string]string    `hcl:"headers,optional" json:"headers,omitempty"`
+	URL             string               `hcl:"url" json:"url"`
+	Proxy           string               `hcl:"proxy,optional" json:"proxy,omitempty"`
+	Timeout         string               `hcl:"timeout,optional" json:"timeout"`
+	RefreshInterval string               `hcl:"refreshInterval,optional" json:"refreshInterval,omitempty"`
+	Template        []PrometheusTemplate `hcl:"template,block" json:"template"`
+	lastGroups      []TargetGroup
+	lastRefresh     time.Time
+}
+
+func (hsd HTTPSD) validate() (err error) {
+	u, err := url.Parse(hsd.URL)
+	if err != nil {
+		return fmt.Errorf("httpSD URL %q is invalid: %w", hsd.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("httpSD URL %q must use one of http or https schemes", sanitizeProxy(hsd.URL))
+	}
+	if hsd.Timeout != "" {
+		if _, err = parseDuration(hsd.Timeout); err != nil {
+			return err
+		}
+	}
+	if hsd.RefreshInterval != "" {
+		if _, err = parseDuration(hsd.RefreshInterval); err != nil {
+			return err
+		}
+	}
+	if hsd.TLS != nil {
+		if err = hsd.TLS.validate(); err != nil {
+			return err
+		}
+	}
+	if hsd.Auth != nil {
+		if err = hsd.Auth.validate(); err != nil {
+			return err
+		}
+	}
+	if hsd.Proxy != "" {
+		if err = validateProxy(hsd.Proxy); err != nil {
+			return err
+		}
+	}
+	if len(hsd.Template) == 0 {
+		return errors.New("httpSD discovery requires at least one template")
+	}
+	for _, t := range hsd.Template {
+		if err = t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Discover will fetch target groups from the httpSD URL.
+// If refreshInterval is set then the last response will be re-used until
+// it's older than refreshInterval, this is used by pint watch which runs
+// discovery on every iteration.
+func (hsd *HTTPSD) Discover(ctx context.Context) ([]*promapi.FailoverGroup, error) {
+	refresh, _ := parseDuration(hsd.RefreshInterval)
+	if hsd.lastGroups != nil && refresh > 0 && time.Since(hsd.lastRefresh) < refresh {
+		slog.Debug(
+			"Re-using cached http_sd targets",
+			slog.String("url", sanitizeProxy(hsd.URL)),
+			slog.String("age", time.Since(hsd.lastRefresh).Round(time.Second).String()),
+			slog.String("refreshInterval", hsd.RefreshInterval),
+		)
+	} else {
+		slog.Info("Finding Prometheus servers using http_sd endpoint", slog.String("url", sanitizeProxy(hsd.URL)))
+		groups, err := hsd.fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("httpSD discovery failed to fetch targets: %w", err)
+		}
+		hsd.lastGroups = groups
+		hsd.lastRefresh = time.Now()
+	}
+
+	servers, err := renderTargetGroups(hsd.lastGroups, hsd.Template, map[string]string{"__meta_url": sanitizeProxy(hsd.URL)})
+	if err != nil {
+		return nil, fmt.Errorf("httpSD discovery failed to generate Prometheus config from a template: %w", err)
+	}
+	return servers, nil
+}
+
+func (hsd HTTPSD) fetch(ctx context.Context) (groups []TargetGroup, err error) {
+	timeout := time.Minute * 2
+	if hsd.Timeout != "" {
+		timeout, _ = parseDuration(hsd.Timeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if hsd.TLS != nil {
+		if transport.TLSClientConfig, err = hsd.TLS.toHTTPConfig(); err != nil {
+			return nil, err
+		}
+	}
+	if proxy := parseProxy(hsd.Proxy); proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	var rt http.RoundTripper = transport
+	if auth := hsd.Auth.toAuthenticator(); auth != nil {
+		rt = auth.RoundTripper(rt)
+	}
+	client := http.Client{Transport: rt}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hsd.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range hsd.Headers {
+		req.Header.Set(k, v)
+	}
+	if refresh, _ := parseDuration(hsd.RefreshInterval); refresh > 0 {
+		req.Header.Set("X-Prometheus-Refresh-Interval-Seconds", strconv.FormatFloat(refresh.Seconds(), 'f', -1, 64))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned HTTP status %s", resp.Status)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return groups, nil
+}
+
 func formatAliases(data map[string]string, t string) string {
 	var vars strings.Builder
 	for k := range data {
@@ -1,9 +1,16 @@
 package config
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/neilotoole/slogt"
 	"github.com/stretchr/testify/require"
@@ -220,6 +227,98 @@ func TestDiscoveryConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{{}},
+			},
+			err: "fileSD discovery requires at least one file pattern",
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{{Files: []string{"targets/[.json"}}},
+			},
+			err: `invalid fileSD file pattern "targets/[.json": syntax error in pattern`,
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{{Files: []string{"targets/*.txt"}}},
+			},
+			err: `fileSD file pattern "targets/*.txt" must end with one of .json, .yml or .yaml`,
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{{Files: []string{"targets/*.json"}}},
+			},
+			err: "fileSD discovery requires at least one template",
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Files:    []string{"targets/*.json"},
+						Template: []PrometheusTemplate{{Name: "foo"}},
+					},
+				},
+			},
+			err: "prometheus template URI cannot be empty",
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Files:    []string{"targets/*.json", "targets/*.yaml"},
+						Template: []PrometheusTemplate{{Name: "foo", URI: "http://{{ $__address__ }}"}},
+					},
+				},
+			},
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{{URL: "ftp://localhost"}},
+			},
+			err: `httpSD URL "ftp://localhost" must use one of http or https schemes`,
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{{URL: "http://localhost", Timeout: "1z"}},
+			},
+			err: `unknown unit "z" in duration "1z"`,
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{{URL: "http://localhost", RefreshInterval: "1z"}},
+			},
+			err: `unknown unit "z" in duration "1z"`,
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{{URL: "http://localhost", Proxy: "ftp://localhost"}},
+			},
+			err: `prometheus proxy URI "ftp://localhost" must use one of http, https or socks5 schemes`,
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{{URL: "http://localhost", TLS: &TLSConfig{ClientKey: "xxx"}}},
+			},
+			err: "clientCert and clientKey must be set together",
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{{URL: "http://localhost"}},
+			},
+			err: "httpSD discovery requires at least one template",
+		},
+		{
+			conf: Discovery{
+				HTTPSD: []HTTPSD{
+					{
+						URL:             "http://localhost/sd",
+						RefreshInterval: "5m",
+						Template:        []PrometheusTemplate{{Name: "foo", URI: "http://{{ $__address__ }}"}},
+					},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -368,3 +467,111 @@ func TestPrometheusTemplateRender(t *testing.T) {
 		})
 	}
 }
+
+func TestFileSDDiscover(t *testing.T) {
+	slog.SetDefault(slogt.New(t))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`[
+  {"targets": ["prom1:9090", "prom2:9090"], "labels": {"cluster": "a"}}
+]`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte(`
+- targets: ["prom3:9090"]
+  labels:
+    cluster: b
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{}`), 0o644))
+
+	fsd := FileSD{
+		Files: []string{filepath.Join(dir, "*.yml"), filepath.Join(dir, "a.json")},
+		Template: []PrometheusTemplate{
+			{
+				Name: "{{ $cluster }}",
+				URI:  "http://{{ $__address__ }}",
+				Tags: []string{"{{ $__meta_filepath }}"},
+			},
+		},
+	}
+	servers, err := fsd.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 3)
+	require.Equal(t, "b", servers[0].Name())
+	require.Equal(t, []string{filepath.Join(dir, "b.yml")}, servers[0].Tags())
+	require.Equal(t, "a", servers[1].Name())
+	require.Equal(t, "a", servers[2].Name())
+
+	d := Discovery{FileSD: []FileSD{fsd}}
+	servers, err = d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	require.Equal(t, 2, servers[1].ServerCount())
+
+	fsd.Files = []string{filepath.Join(dir, "bad.json")}
+	_, err = fsd.Discover(context.Background())
+	require.EqualError(t, err, "fileSD discovery failed to read "+filepath.Join(dir, "bad.json")+": json: cannot unmarshal object into Go value of type []config.TargetGroup")
+
+	fsd.Files = []string{filepath.Join(dir, "a.json")}
+	fsd.Template = []PrometheusTemplate{{Name: "{{ $job }}", URI: "http://{{ $__address__ }}"}}
+	_, err = fsd.Discover(context.Background())
+	require.EqualError(t, err, `fileSD discovery failed to generate Prometheus config from a template: bad name template "{{ $job }}": template: discovery:1: undefined variable "$job"`)
+}
+
+func TestHTTPSDDiscover(t *testing.T) {
+	slog.SetDefault(slogt.New(t))
+
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/sd":
+			require.Equal(t, "bar", r.Header.Get("X-Foo"))
+			require.Equal(t, "300", r.Header.Get("X-Prometheus-Refresh-Interval-Seconds"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"targets": ["prom1:9090"], "labels": {"cluster": "a"}}]`))
+		case "/bad":
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	hsd := HTTPSD{
+		URL:             srv.URL + "/sd",
+		Headers:         map[string]string{"X-Foo": "bar"},
+		RefreshInterval: "5m",
+		Template: []PrometheusTemplate{
+			{
+				Name: "{{ $cluster }}",
+				URI:  "http://{{ $__address__ }}",
+				Tags: []string{"{{ $__meta_url }}"},
+			},
+		},
+	}
+	servers, err := hsd.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	require.Equal(t, "a", servers[0].Name())
+	require.Equal(t, []string{srv.URL + "/sd"}, servers[0].Tags())
+	require.Equal(t, int64(1), requests.Load())
+
+	// Second call should re-use the last response.
+	servers, err = hsd.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	require.Equal(t, int64(1), requests.Load())
+
+	// Force a refresh.
+	hsd.lastRefresh = time.Now().Add(time.Minute * -10)
+	_, err = hsd.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), requests.Load())
+
+	hsd = HTTPSD{URL: srv.URL + "/bad", Template: hsd.Template}
+	_, err = hsd.Discover(context.Background())
+	require.EqualError(t, err, "httpSD discovery failed to fetch targets: failed to decode response: invalid character 'o' in literal null (expecting 'u')")
+
+	hsd = HTTPSD{URL: srv.URL + "/missing", Template: hsd.Template}
+	_, err = hsd.Discover(context.Background())
+	require.EqualError(t, err, "httpSD discovery failed to fetch targets: server returned HTTP status 404 Not Found")
+}
@@ -26,6 +26,7 @@ var (
 	CICommand    ContextCommandVal = "ci"
 	LintCommand  ContextCommandVal = "lint"
 	WatchCommand ContextCommandVal = "watch"
+	ServeCommand ContextCommandVal = "serve"
 )
 
 type Match struct {
@@ -10,6 +10,8 @@ import (
 	"log/slog"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"time"
@@ -78,12 +80,185 @@ func (t *TLSConfig) toHTTPConfig() (*tls.Config, error) {
 	return nil, nil
 }
 
+type FixturesConfig struct {
+	Exposition []string `hcl:"exposition,optional" json:"exposition,omitempty"`
+	Series     []string `hcl:"series,optional" json:"series,omitempty"`
+	Blocks     string   `hcl:"blocks,optional" json:"blocks,omitempty"`
+	Metadata   string   `hcl:"metadata,optional" json:"metadata,omitempty"`
+	Flags      string   `hcl:"flags,optional" json:"flags,omitempty"`
+	Config     string   `hcl:"config,optional" json:"config,omitempty"`
+	Range      string   `hcl:"range,optional" json:"range,omitempty"`
+	Step       string   `hcl:"step,optional" json:"step,omitempty"`
+}
+
+func (fc FixturesConfig) validate() error {
+	if len(fc.Exposition) == 0 && len(fc.Series) == 0 && fc.Blocks == "" {
+		return errors.New("fixtures block must have at least one of exposition, series or blocks set")
+	}
+
+	for _, pattern := range append(append([]string{}, fc.Exposition...), fc.Series...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid fixtures file pattern %q: %w", pattern, err)
+		}
+	}
+
+	if fc.Range != "" {
+		if _, err := parseDuration(fc.Range); err != nil {
+			return err
+		}
+	}
+
+	if fc.Step != "" {
+		if _, err := parseDuration(fc.Step); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fc FixturesConfig) toOptions() (opts promapi.FixtureOptions, err error) {
+	if opts.Exposition, err = expandFixturePatterns(fc.Exposition); err != nil {
+		return opts, err
+	}
+	if opts.Series, err = expandFixturePatterns(fc.Series); err != nil {
+		return opts, err
+	}
+	opts.Blocks = fc.Blocks
+	opts.Metadata = fc.Metadata
+	opts.Flags = fc.Flags
+	opts.Config = fc.Config
+	if fc.Range != "" {
+		opts.Range, _ = parseDuration(fc.Range)
+	}
+	if fc.Step != "" {
+		opts.Step, _ = parseDuration(fc.Step)
+	}
+	return opts, nil
+}
+
+func expandFixturePatterns(patterns []string) (paths []string, err error) {
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no fixture files found matching %q", pattern)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+type RetryConfig struct {
+	Backoff     string `hcl:"backoff,optional" json:"backoff"`
+	MaxBackoff  string `hcl:"maxBackoff,optional" json:"maxBackoff"`
+	RetryOn     []int  `hcl:"retryOn,optional" json:"retryOn"`
+	MaxAttempts int    `hcl:"maxAttempts,optional" json:"maxAttempts"`
+}
+
+func (rc RetryConfig) validate() error {
+	if rc.MaxAttempts < 0 {
+		return fmt.Errorf("retry maxAttempts cannot be negative, got %d", rc.MaxAttempts)
+	}
+
+	var backoff, maxBackoff time.Duration
+	var err error
+	if rc.Backoff != "" {
+		if backoff, err = parseDuration(rc.Backoff); err != nil {
+			return err
+		}
+	}
+	if rc.MaxBackoff != "" {
+		if maxBackoff, err = parseDuration(rc.MaxBackoff); err != nil {
+			return err
+		}
+	}
+	if backoff > 0 && maxBackoff > 0 && backoff > maxBackoff {
+		return fmt.Errorf("retry backoff (%s) cannot be greater than maxBackoff (%s)", rc.Backoff, rc.MaxBackoff)
+	}
+
+	for _, code := range rc.RetryOn {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid HTTP status code in retryOn: %d", code)
+		}
+	}
+
+	return nil
+}
+
+func (rc *RetryConfig) applyDefaults() {
+	if rc.MaxAttempts == 0 {
+		rc.MaxAttempts = 3
+	}
+	if rc.Backoff == "" {
+		rc.Backoff = time.Second.String()
+	}
+	if rc.MaxBackoff == "" {
+		rc.MaxBackoff = (time.Second * 30).String()
+	}
+	if len(rc.RetryOn) == 0 {
+		rc.RetryOn = []int{429, 502, 503, 504}
+	}
+}
+
+func (rc *RetryConfig) toPolicy() (rp promapi.RetryPolicy) {
+	if rc == nil {
+		return rp
+	}
+	rp.MaxAttempts = rc.MaxAttempts
+	rp.InitialBackoff, _ = parseDuration(rc.Backoff)
+	rp.MaxBackoff, _ = parseDuration(rc.MaxBackoff)
+	rp.RetryOn = rc.RetryOn
+	return rp
+}
+
+func validateProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("prometheus proxy URI %q is invalid: %w", proxy, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("prometheus proxy URI %q must use one of http, https or socks5 schemes", sanitizeProxy(proxy))
+	}
+	if u.Host == "" {
+		return fmt.Errorf("prometheus proxy URI %q is missing a host", sanitizeProxy(proxy))
+	}
+	return nil
+}
+
+func sanitizeProxy(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxx")
+	}
+	return u.String()
+}
+
+func parseProxy(proxy string) *url.URL {
+	if proxy == "" {
+		return nil
+	}
+	u, _ := url.Parse(proxy)
+	return u
+}
+
 type PrometheusConfig struct {
 	Headers     map[string]string `hcl:"headers,optional" json:"headers,omitempty"`
 	TLS         *TLSConfig        `hcl:"tls,block" json:"tls,omitempty"`
+	Auth        *AuthConfig       `hcl:"auth,block" json:"auth,omitempty"`
+	Retry       *RetryConfig      `hcl:"retry,block" json:"retry,omitempty"`
+	Fixtures    *FixturesConfig   `hcl:"fixtures,block" json:"fixtures,omitempty"`
 	Name        string            `hcl:",label" json:"name"`
-	URI         string            `hcl:"uri" json:"uri"`
+	URI         string            `hcl:"uri,optional" json:"uri"`
 	PublicURI   string            `hcl:"publicURI,optional" json:"publicURI,omitempty"`
+	Proxy       string            `hcl:"proxy,optional" json:"proxy,omitempty"`
 	Timeout     string            `hcl:"timeout,optional"  json:"timeout"`
 	Uptime      string            `hcl:"uptime,optional" json:"uptime"`
 	Failover    []string          `hcl:"failover,optional" json:"failover,omitempty"`
@@ -96,7 +271,17 @@ type PrometheusConfig struct {
 }
 
 func (pc PrometheusConfig) validate() error {
-	if pc.URI == "" {
+	if pc.Fixtures != nil {
+		if pc.URI != "" {
+			return errors.New("prometheus URI cannot be set when using fixtures")
+		}
+		if len(pc.Failover) > 0 {
+			return errors.New("prometheus failover URIs cannot be set when using fixtures")
+		}
+		if err := pc.Fixtures.validate(); err != nil {
+			return err
+		}
+	} else if pc.URI == "" {
 		return errors.New("prometheus URI cannot be empty")
 	}
 	if _, err := url.Parse(pc.URI); err != nil {
@@ -141,6 +326,27 @@ func (pc PrometheusConfig) validate() error {
 		}
 	}
 
+	if pc.Auth != nil {
+		if err := pc.Auth.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.Proxy != "" {
+		if pc.Fixtures != nil {
+			return errors.New("prometheus proxy cannot be set when using fixtures")
+		}
+		if err := validateProxy(pc.Proxy); err != nil {
+			return err
+		}
+	}
+
+	if pc.Retry != nil {
+		if err := pc.Retry.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -160,6 +366,10 @@ func (pc *PrometheusConfig) applyDefaults() {
 	if pc.Uptime == "" {
 		pc.Uptime = "up"
 	}
+
+	if pc.Retry != nil {
+		pc.Retry.applyDefaults()
+	}
 }
 
 func newFailoverGroup(prom PrometheusConfig) *promapi.FailoverGroup {
@@ -167,12 +377,33 @@ func newFailoverGroup(prom PrometheusConfig) *promapi.FailoverGroup {
 
 	var tlsConf *tls.Config
 	tlsConf, _ = prom.TLS.toHTTPConfig()
+	proxy := parseProxy(prom.Proxy)
+	auth := prom.Auth.toAuthenticator()
+	retry := prom.Retry.toPolicy()
 	upstreams := []*promapi.Prometheus{
-		promapi.NewPrometheus(prom.Name, prom.URI, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, tlsConf),
+		promapi.NewPrometheus(prom.Name, prom.URI, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, tlsConf, proxy, auth, retry),
 	}
 	for _, uri := range prom.Failover {
-		upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, tlsConf))
+		upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, tlsConf, proxy, auth, retry))
 	}
+	return newFailoverGroupWithUpstreams(prom, upstreams)
+}
+
+func newFixtureFailoverGroup(prom PrometheusConfig) (*promapi.FailoverGroup, error) {
+	timeout, _ := parseDuration(prom.Timeout)
+
+	opts, err := prom.Fixtures.toOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures for %q Prometheus server: %w", prom.Name, err)
+	}
+	upstream, err := promapi.NewFixturePrometheus(prom.Name, opts, timeout, prom.Concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures for %q Prometheus server: %w", prom.Name, err)
+	}
+	return newFailoverGroupWithUpstreams(prom, []*promapi.Prometheus{upstream}), nil
+}
+
+func newFailoverGroupWithUpstreams(prom PrometheusConfig, upstreams []*promapi.Prometheus) *promapi.FailoverGroup {
 	include := make([]*regexp.Regexp, 0, len(prom.Include))
 	for _, path := range prom.Include {
 		include = append(include, strictRegex(path))
@@ -183,19 +414,25 @@ func newFailoverGroup(prom PrometheusConfig) *promapi.FailoverGroup {
 	}
 	tags := make([]string, 0, len(prom.Tags))
 	tags = append(tags, prom.Tags...)
-	return promapi.NewFailoverGroup(prom.Name, prom.PublicURI, upstreams, prom.Required, prom.Uptime, include, exclude, tags)
+	fg := promapi.NewFailoverGroup(prom.Name, prom.PublicURI, upstreams, prom.Required, prom.Uptime, include, exclude, tags)
+	fg.SetConfig(prom)
+	return fg
 }
 
 func NewPrometheusGenerator(cfg Config, metricsRegistry *prometheus.Registry) *PrometheusGenerator {
 	return &PrometheusGenerator{
 		metricsRegistry: metricsRegistry,
 		cfg:             cfg,
+		dynamic:         map[string]bool{},
+		static:          map[string]PrometheusConfig{},
 	}
 }
 
 type PrometheusGenerator struct {
 	cfg             Config
 	metricsRegistry *prometheus.Registry
+	dynamic         map[string]bool
+	static          map[string]PrometheusConfig
 	servers         []*promapi.FailoverGroup
 }
 
@@ -212,6 +449,8 @@ func (pg *PrometheusGenerator) Stop() {
 		server.Close(pg.metricsRegistry)
 	}
 	pg.servers = nil
+	pg.dynamic = map[string]bool{}
+	pg.static = map[string]PrometheusConfig{}
 }
 
 func (pg *PrometheusGenerator) ServersForPath(path string) []*promapi.FailoverGroup {
@@ -244,28 +483,201 @@ func (pg *PrometheusGenerator) addServer(server *promapi.FailoverGroup) error {
 	return nil
 }
 
+func newStaticFailoverGroup(pc PrometheusConfig) (*promapi.FailoverGroup, error) {
+	if pc.Fixtures != nil {
+		return newFixtureFailoverGroup(pc)
+	}
+	return newFailoverGroup(pc), nil
+}
+
 func (pg *PrometheusGenerator) GenerateStatic() (err error) {
 	for _, pc := range pg.cfg.Prometheus {
-		err = pg.addServer(newFailoverGroup(pc))
+		var server *promapi.FailoverGroup
+		if server, err = newStaticFailoverGroup(pc); err != nil {
+			return err
+		}
+		err = pg.addServer(server)
 		if err != nil {
 			return err
 		}
+		pg.static[pc.Name] = pc
 	}
 	return nil
 }
 
-func (pg *PrometheusGenerator) GenerateDynamic(ctx context.Context) (err error) {
-	if pg.cfg.Discovery != nil {
-		servers, err := pg.cfg.Discovery.Discover(ctx)
+// Reload replaces the configuration used by this generator.
+// Servers defined in prometheus blocks are only re-created if their
+// configuration changed, all other servers are kept together with their
+// query cache.
+// If the discovery configuration changed then all discovered servers are
+// stopped and will be re-created on the next GenerateDynamic call.
+// Just like GenerateDynamic it's not safe to call it while other goroutines
+// might send new queries to existing servers.
+func (pg *PrometheusGenerator) Reload(cfg Config) error {
+	// Create all new servers first so errors don't leave us with a partial list.
+	names := make(map[string]struct{}, len(cfg.Prometheus))
+	fresh := map[string]*promapi.FailoverGroup{}
+	for _, pc := range cfg.Prometheus {
+		if _, ok := names[pc.Name]; ok {
+			return fmt.Errorf("Duplicated name for Prometheus server definition: %s", pc.Name)
+		}
+		names[pc.Name] = struct{}{}
+		if old, ok := pg.static[pc.Name]; ok && reflect.DeepEqual(old, pc) {
+			continue
+		}
+		server, err := newStaticFailoverGroup(pc)
 		if err != nil {
+			for _, fs := range fresh {
+				fs.Close(pg.metricsRegistry)
+			}
 			return err
 		}
-		for _, server := range servers {
-			err = pg.addServer(server)
-			if err != nil {
-				return err
+		fresh[pc.Name] = server
+	}
+
+	discoveryChanged := !isEqualDiscovery(pg.cfg.Discovery, cfg.Discovery)
+	if !discoveryChanged {
+		// Keep the old discovery config, httpSD blocks store the last response there.
+		cfg.Discovery = pg.cfg.Discovery
+	}
+
+	keep := map[string]*promapi.FailoverGroup{}
+	dynamic := []*promapi.FailoverGroup{}
+	for _, server := range pg.servers {
+		_, isStatic := names[server.Name()]
+		_, isFresh := fresh[server.Name()]
+		switch {
+		case pg.dynamic[server.Name()] && (discoveryChanged || isStatic):
+			slog.Info("Removing discovered Prometheus server after config reload", slog.String("name", server.Name()))
+			server.Close(pg.metricsRegistry)
+			delete(pg.dynamic, server.Name())
+		case pg.dynamic[server.Name()]:
+			dynamic = append(dynamic, server)
+		case !isStatic:
+			slog.Info("Removing Prometheus server after config reload", slog.String("name", server.Name()))
+			server.Close(pg.metricsRegistry)
+		case isFresh:
+			slog.Info("Replacing Prometheus server with updated configuration", slog.String("name", server.Name()))
+			server.Close(pg.metricsRegistry)
+		default:
+			keep[server.Name()] = server
+		}
+	}
+
+	pg.cfg = cfg
+	pg.static = make(map[string]PrometheusConfig, len(cfg.Prometheus))
+	pg.servers = make([]*promapi.FailoverGroup, 0, len(cfg.Prometheus)+len(dynamic))
+	for _, pc := range cfg.Prometheus {
+		pg.static[pc.Name] = pc
+		if server, ok := keep[pc.Name]; ok {
+			pg.servers = append(pg.servers, server)
+			continue
+		}
+		// Names were already checked for duplicates above.
+		_ = pg.addServer(fresh[pc.Name])
+	}
+	pg.servers = append(pg.servers, dynamic...)
+	return nil
+}
+
+// isEqualDiscovery compares discovery configuration, including all secrets.
+// It can't use JSON since secrets are masked there, so rotating a password
+// would go unnoticed.
+func isEqualDiscovery(a, b *Discovery) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.DeepEqual(a.FilePath, b.FilePath) &&
+		reflect.DeepEqual(a.PrometheusQuery, b.PrometheusQuery) &&
+		reflect.DeepEqual(a.FileSD, b.FileSD) &&
+		reflect.DeepEqual(httpSDConfig(a.HTTPSD), httpSDConfig(b.HTTPSD))
+}
+
+// httpSDConfig returns a copy of httpSD blocks without the state they store
+// in unexported fields.
+func httpSDConfig(src []HTTPSD) []HTTPSD {
+	if src == nil {
+		return nil
+	}
+	dst := make([]HTTPSD, 0, len(src))
+	for _, hsd := range src {
+		hsd.lastGroups = nil
+		hsd.lastRefresh = time.Time{}
+		dst = append(dst, hsd)
+	}
+	return dst
+}
+
+// GenerateDynamic runs Prometheus server discovery and reconciles the results
+// with servers found on previous runs.
+// New servers are started, servers that are no longer discovered are stopped
+// and servers that changed are replaced, servers that didn't change are kept
+// as is, together with their query cache.
+// Stopped servers will first finish all queued queries, but it's not safe to
+// call it while other goroutines might send new queries to existing servers.
+func (pg *PrometheusGenerator) GenerateDynamic(ctx context.Context) (err error) {
+	if pg.cfg.Discovery == nil {
+		return nil
+	}
+
+	servers, err := pg.cfg.Discovery.Discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Check for duplicates first so we don't leave a partial list on errors.
+	names := make(map[string]struct{}, len(servers))
+	for _, server := range servers {
+		if _, ok := names[server.Name()]; ok {
+			return fmt.Errorf("Duplicated name for Prometheus server definition: %s", server.Name())
+		}
+		names[server.Name()] = struct{}{}
+		for _, s := range pg.servers {
+			if s.Name() == server.Name() && !pg.dynamic[s.Name()] {
+				return fmt.Errorf("Duplicated name for Prometheus server definition: %s", s.Name())
+			}
+		}
+	}
+
+	current := make([]*promapi.FailoverGroup, 0, len(pg.servers))
+	var added []*promapi.FailoverGroup
+	for _, s := range pg.servers {
+		if !pg.dynamic[s.Name()] {
+			current = append(current, s)
+			continue
+		}
+		if _, ok := names[s.Name()]; !ok {
+			slog.Info("Removing Prometheus server that is no longer discovered", slog.String("name", s.Name()))
+			s.Close(pg.metricsRegistry)
+			delete(pg.dynamic, s.Name())
+		}
+	}
+	for _, server := range servers {
+		var old *promapi.FailoverGroup
+		for _, s := range pg.servers {
+			if s.Name() == server.Name() && pg.dynamic[s.Name()] {
+				old = s
+				break
 			}
 		}
+		switch {
+		case old == nil:
+			added = append(added, server)
+		case old.IsEqual(server):
+			current = append(current, old)
+		default:
+			slog.Info("Replacing Prometheus server with updated configuration", slog.String("name", old.Name()))
+			old.Close(pg.metricsRegistry)
+			added = append(added, server)
+		}
+	}
+
+	pg.servers = current
+	for _, server := range added {
+		if err = pg.addServer(server); err != nil {
+			return err
+		}
+		pg.dynamic[server.Name()] = true
 	}
 	return nil
 }
@@ -14,17 +14,18 @@ import (
 )
 
 type Rule struct {
-	Match         []Match              `hcl:"match,block" json:"match,omitempty"`
-	Ignore        []Match              `hcl:"ignore,block" json:"ignore,omitempty"`
-	Aggregate     []AggregateSettings  `hcl:"aggregate,block" json:"aggregate,omitempty"`
-	Annotation    []AnnotationSettings `hcl:"annotation,block" json:"annotation,omitempty"`
-	Label         []AnnotationSettings `hcl:"label,block" json:"label,omitempty"`
-	Cost          *CostSettings        `hcl:"cost,block" json:"cost,omitempty"`
-	Alerts        *AlertsSettings      `hcl:"alerts,block" json:"alerts,omitempty"`
-	For           *ForSettings         `hcl:"for,block" json:"for,omitempty"`
-	KeepFiringFor *ForSettings         `hcl:"keep_firing_for,block" json:"keep_firing_for,omitempty"`
-	Reject        []RejectSettings     `hcl:"reject,block" json:"reject,omitempty"`
-	RuleLink      []RuleLinkSettings   `hcl:"link,block" json:"link,omitempty"`
+	Match         []Match               `hcl:"match,block" json:"match,omitempty"`
+	Ignore        []Match               `hcl:"ignore,block" json:"ignore,omitempty"`
+	Aggregate     []AggregateSettings   `hcl:"aggregate,block" json:"aggregate,omitempty"`
+	Annotation    []AnnotationSettings  `hcl:"annotation,block" json:"annotation,omitempty"`
+	Label         []AnnotationSettings  `hcl:"label,block" json:"label,omitempty"`
+	Cost          *CostSettings         `hcl:"cost,block" json:"cost,omitempty"`
+	Alerts        *AlertsSettings       `hcl:"alerts,block" json:"alerts,omitempty"`
+	For           *ForSettings          `hcl:"for,block" json:"for,omitempty"`
+	KeepFiringFor *ForSettings          `hcl:"keep_firing_for,block" json:"keep_firing_for,omitempty"`
+	Reject        []RejectSettings      `hcl:"reject,block" json:"reject,omitempty"`
+	RuleLink      []RuleLinkSettings    `hcl:"link,block" json:"link,omitempty"`
+	Custom        []CustomCheckSettings `hcl:"check,block" json:"check,omitempty"`
 }
 
 func (rule Rule) validate() (err error) {
@@ -94,6 +95,12 @@ func (rule Rule) validate() (err error) {
 		}
 	}
 
+	for i := range rule.Custom {
+		if err = rule.Custom[i].validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -147,7 +154,7 @@ func (rule Rule) resolveChecks(ctx context.Context, path string, r parser.Rule,
 		for _, prom := range prometheusServers {
 			enabled = append(enabled, checkMeta{
 				name:  checks.CostCheckName,
-				check: checks.NewCostCheck(prom, rule.Cost.MaxSeries, rule.Cost.MaxTotalSamples, rule.Cost.MaxPeakSamples, evalDur, severity),
+				check: checks.NewCostCheck(prom, rule.Cost.MaxSeries, rule.Cost.MaxTotalSamples, rule.Cost.MaxPeakSamples, rule.Cost.MaxSelectorSeries, evalDur, severity),
 				tags:  prom.Tags(),
 			})
 		}
@@ -272,6 +279,13 @@ func (rule Rule) resolveChecks(ctx context.Context, path string, r parser.Rule,
 		})
 	}
 
+	for _, custom := range rule.Custom {
+		enabled = append(enabled, checkMeta{
+			name:  checks.CustomCheckName,
+			check: custom.newCheck(),
+		})
+	}
+
 	return enabled
 }
 
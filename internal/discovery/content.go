@@ -0,0 +1,50 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+
+	"github.com/cloudflare/pint/internal/git"
+)
+
+// File is a rule file that's already loaded into memory.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+func NewContentFinder(files []File, filter git.PathFilter) ContentFinder {
+	return ContentFinder{
+		files:  files,
+		filter: filter,
+	}
+}
+
+type ContentFinder struct {
+	files  []File
+	filter git.PathFilter
+}
+
+func (f ContentFinder) Find() (entries []Entry, err error) {
+	for _, file := range f.files {
+		if !f.filter.IsPathAllowed(file.Path) {
+			continue
+		}
+
+		el, err := readRules(file.Path, file.Path, bytes.NewReader(file.Content), !f.filter.IsRelaxed(file.Path))
+		if err != nil {
+			return nil, fmt.Errorf("invalid file syntax: %w", err)
+		}
+		for _, e := range el {
+			e.State = Noop
+			if len(e.ModifiedLines) == 0 {
+				e.ModifiedLines = e.Rule.Lines.Expand()
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	slog.Debug("Content finder completed", slog.Int("count", len(entries)))
+	return entries, nil
+}
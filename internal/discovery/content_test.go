@@ -0,0 +1,61 @@
+package discovery_test
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestContentFinder(t *testing.T) {
+	p := parser.NewParser()
+	testRuleBody := "# pint file/owner bob\n\n- record: foo\n  expr: sum(foo)\n"
+	testRules, err := p.Parse([]byte(testRuleBody))
+	require.NoError(t, err)
+
+	type testCaseT struct {
+		finder  discovery.ContentFinder
+		entries []discovery.Entry
+	}
+
+	testCases := []testCaseT{
+		{
+			finder: discovery.NewContentFinder(nil, git.NewPathFilter(nil, nil, nil)),
+		},
+		{
+			finder: discovery.NewContentFinder(
+				[]discovery.File{{Path: "rules.yml", Content: []byte(testRuleBody)}},
+				git.NewPathFilter(nil, nil, []*regexp.Regexp{regexp.MustCompile(".*")}),
+			),
+			entries: []discovery.Entry{
+				{
+					State:         discovery.Noop,
+					ReportedPath:  "rules.yml",
+					SourcePath:    "rules.yml",
+					Rule:          testRules[0],
+					ModifiedLines: testRules[0].Lines.Expand(),
+					Owner:         "bob",
+				},
+			},
+		},
+		{
+			finder: discovery.NewContentFinder(
+				[]discovery.File{{Path: "rules.yml", Content: []byte(testRuleBody)}},
+				git.NewPathFilter(nil, []*regexp.Regexp{regexp.MustCompile("rules.yml")}, nil),
+			),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			entries, err := tc.finder.Find()
+			require.NoError(t, err)
+			require.Equal(t, tc.entries, entries)
+		})
+	}
+}
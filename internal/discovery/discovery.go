@@ -94,6 +94,7 @@ type Entry struct {
 	ModifiedLines  []int
 	DisabledChecks []string
 	Rule           parser.Rule
+	Runtime        *RuleRuntime // only set for rules loaded from Prometheus
 	State          ChangeType
 }
 
@@ -0,0 +1,184 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+// RuleRuntime describes the state of a rule loaded by a running Prometheus
+// server.
+type RuleRuntime struct {
+	Prometheus     string
+	Health         string
+	LastError      string
+	EvaluationTime time.Duration
+	GroupInterval  time.Duration
+}
+
+// RulesAPIPath returns the path used for all rules from given file loaded by
+// given Prometheus server.
+func RulesAPIPath(prom, file string) string {
+	return fmt.Sprintf("%s:%s", prom, file)
+}
+
+func NewRulesAPIFinder(ctx context.Context, servers []*promapi.FailoverGroup, filter git.PathFilter) RulesAPIFinder {
+	return RulesAPIFinder{
+		ctx:     ctx,
+		servers: servers,
+		filter:  filter,
+	}
+}
+
+// RulesAPIFinder returns all rules currently loaded by Prometheus servers
+// using the /api/v1/rules API.
+// Every rule file will be reported using the path returned by RulesAPIPath.
+type RulesAPIFinder struct {
+	ctx     context.Context
+	filter  git.PathFilter
+	servers []*promapi.FailoverGroup
+}
+
+func (f RulesAPIFinder) Find() (entries []Entry, err error) {
+	var failed int
+	for _, prom := range f.servers {
+		slog.Info("Fetching rules from Prometheus", slog.String("name", prom.Name()))
+		result, err := prom.Rules(f.ctx)
+		if err != nil {
+			slog.Error("Failed to fetch rules from Prometheus", slog.String("name", prom.Name()), slog.Any("err", err))
+			failed++
+			continue
+		}
+
+		var files []string
+		groups := map[string][]promapi.RuleGroup{}
+		for _, group := range result.Groups {
+			if _, ok := groups[group.File]; !ok {
+				files = append(files, group.File)
+			}
+			groups[group.File] = append(groups[group.File], group)
+		}
+
+		for _, file := range files {
+			path := RulesAPIPath(prom.Name(), file)
+			if !f.filter.IsPathAllowed(path) {
+				continue
+			}
+
+			content, runtimes, err := renderRuleGroups(groups[file], prom.Name())
+			if err != nil {
+				return nil, fmt.Errorf("failed to render rules from %s: %w", path, err)
+			}
+
+			el, err := readRules(path, path, bytes.NewReader(content), !f.filter.IsRelaxed(path))
+			if err != nil {
+				return nil, fmt.Errorf("invalid file syntax: %w", err)
+			}
+			// Rules are always parsed in the same order as they were rendered,
+			// unless there were errors and we have fewer rules than expected.
+			hasRuntime := len(el) == len(runtimes)
+			for i, e := range el {
+				e.State = Noop
+				if len(e.ModifiedLines) == 0 {
+					e.ModifiedLines = e.Rule.Lines.Expand()
+				}
+				if hasRuntime && e.PathError == nil {
+					e.Runtime = &runtimes[i]
+				}
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	if failed > 0 && failed == len(f.servers) {
+		return nil, fmt.Errorf("failed to fetch rules from all %d Prometheus server(s)", failed)
+	}
+
+	slog.Debug("Rules API finder completed", slog.Int("count", len(entries)))
+	return entries, nil
+}
+
+type apiRuleGroups struct {
+	Groups []apiRuleGroup `yaml:"groups"`
+}
+
+type apiRuleGroup struct {
+	Name     string    `yaml:"name"`
+	Interval string    `yaml:"interval,omitempty"`
+	Rules    []apiRule `yaml:"rules"`
+	Limit    int       `yaml:"limit,omitempty"`
+}
+
+type apiRule struct {
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           string            `yaml:"for,omitempty"`
+	KeepFiringFor string            `yaml:"keep_firing_for,omitempty"`
+}
+
+// renderRuleGroups converts rule groups returned by Prometheus back into
+// a rule file, so it can be parsed like any other file.
+func renderRuleGroups(groups []promapi.RuleGroup, prom string) ([]byte, []RuleRuntime, error) {
+	var doc apiRuleGroups
+	var runtimes []RuleRuntime
+	for _, group := range groups {
+		ag := apiRuleGroup{
+			Name:     group.Name,
+			Interval: formatSeconds(group.Interval),
+			Limit:    group.Limit,
+		}
+		for _, rule := range group.Rules {
+			ar := apiRule{
+				Labels: rule.Labels,
+				Expr:   rule.Query,
+			}
+			if rule.Type == "alerting" {
+				ar.Alert = rule.Name
+				ar.Annotations = rule.Annotations
+				ar.For = formatSeconds(rule.Duration)
+				ar.KeepFiringFor = formatSeconds(rule.KeepFiringFor)
+			} else {
+				ar.Record = rule.Name
+			}
+			ag.Rules = append(ag.Rules, ar)
+			runtimes = append(runtimes, RuleRuntime{
+				Prometheus:     prom,
+				Health:         rule.Health,
+				LastError:      rule.LastError,
+				EvaluationTime: secondsToDuration(rule.EvaluationTime),
+				GroupInterval:  secondsToDuration(group.Interval),
+			})
+		}
+		doc.Groups = append(doc.Groups, ag)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), runtimes, nil
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func formatSeconds(s float64) string {
+	if s <= 0 {
+		return ""
+	}
+	return model.Duration(secondsToDuration(s)).String()
+}
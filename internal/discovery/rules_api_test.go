@@ -0,0 +1,141 @@
+package discovery_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newRulesAPIGroup(t *testing.T, name, uri string) *promapi.FailoverGroup {
+	fg := promapi.NewFailoverGroup(name, uri, []*promapi.Prometheus{
+		promapi.NewPrometheus(name, uri, "", nil, time.Second, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	t.Cleanup(func() { fg.Close(reg) })
+	return fg
+}
+
+func TestRulesAPIFinder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rules/api/v1/rules":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"groups":[
+{"name":"foo","file":"/etc/rules/1.yml","interval":60,"evaluationTime":1,"rules":[
+	{"type":"recording","name":"sum:up","query":"sum(up)","labels":{"job":"foo"},"health":"ok","evaluationTime":0.1},
+	{"type":"alerting","name":"Down","query":"up == 0","duration":300,"annotations":{"summary":"down"},"health":"err","lastError":"timeout","evaluationTime":0.4}
+]},
+{"name":"bar","file":"/etc/rules/2.yml","interval":30,"evaluationTime":1,"rules":[
+	{"type":"recording","name":"count:up","query":"count(up)","health":"ok","evaluationTime":0.1}
+]},
+{"name":"baz","file":"/etc/rules/1.yml","interval":60,"evaluationTime":1,"rules":[
+	{"type":"recording","name":"max:up","query":"max(up)","health":"unknown","evaluationTime":0}
+]}
+]}}`))
+		default:
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	t.Run("all servers", func(t *testing.T) {
+		entries, err := discovery.NewRulesAPIFinder(ctx, []*promapi.FailoverGroup{
+			newRulesAPIGroup(t, "prom", srv.URL+"/rules"),
+			newRulesAPIGroup(t, "broken", srv.URL+"/error"),
+		}, git.NewPathFilter(nil, nil, nil)).Find()
+		require.NoError(t, err)
+		require.Len(t, entries, 4)
+
+		type result struct {
+			runtime discovery.RuleRuntime
+			path    string
+			name    string
+			first   int
+		}
+		results := make([]result, 0, len(entries))
+		for _, e := range entries {
+			require.NoError(t, e.PathError)
+			require.NoError(t, e.Rule.Error.Err)
+			require.NotNil(t, e.Runtime)
+			require.Equal(t, e.SourcePath, e.ReportedPath)
+			results = append(results, result{path: e.SourcePath, name: e.Rule.Name(), first: e.Rule.Lines.First, runtime: *e.Runtime})
+		}
+		require.Equal(t, []result{
+			{
+				path:  "prom:/etc/rules/1.yml",
+				name:  "sum:up",
+				first: 5,
+				runtime: discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         "ok",
+					EvaluationTime: time.Millisecond * 100,
+					GroupInterval:  time.Minute,
+				},
+			},
+			{
+				path:  "prom:/etc/rules/1.yml",
+				name:  "Down",
+				first: 9,
+				runtime: discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         "err",
+					LastError:      "timeout",
+					EvaluationTime: time.Millisecond * 400,
+					GroupInterval:  time.Minute,
+				},
+			},
+			{
+				path:  "prom:/etc/rules/1.yml",
+				name:  "max:up",
+				first: 17,
+				runtime: discovery.RuleRuntime{
+					Prometheus:    "prom",
+					Health:        "unknown",
+					GroupInterval: time.Minute,
+				},
+			},
+			{
+				path:  "prom:/etc/rules/2.yml",
+				name:  "count:up",
+				first: 5,
+				runtime: discovery.RuleRuntime{
+					Prometheus:     "prom",
+					Health:         "ok",
+					EvaluationTime: time.Millisecond * 100,
+					GroupInterval:  time.Second * 30,
+				},
+			},
+		}, results)
+		require.Equal(t, "5m", entries[1].Rule.AlertingRule.For.Value)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		entries, err := discovery.NewRulesAPIFinder(ctx, []*promapi.FailoverGroup{
+			newRulesAPIGroup(t, "prom", srv.URL+"/rules"),
+		}, git.NewPathFilter([]*regexp.Regexp{regexp.MustCompile(".*/2.yml")}, nil, nil)).Find()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "prom:/etc/rules/2.yml", entries[0].SourcePath)
+	})
+
+	t.Run("all servers failed", func(t *testing.T) {
+		_, err := discovery.NewRulesAPIFinder(ctx, []*promapi.FailoverGroup{
+			newRulesAPIGroup(t, "broken", srv.URL+"/error"),
+		}, git.NewPathFilter(nil, nil, nil)).Find()
+		require.EqualError(t, err, "failed to fetch rules from all 1 Prometheus server(s)")
+	})
+}
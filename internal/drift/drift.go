@@ -0,0 +1,236 @@
+package drift
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	promparser "github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	AlertingRule  = "alerting"
+	RecordingRule = "recording"
+)
+
+type Kind uint8
+
+const (
+	// Rule is present in a rule file but not on the Prometheus server.
+	Missing Kind = iota
+	// Rule is present on the Prometheus server but not in any rule file.
+	Extra
+	// Rule is present in both places but with a different definition.
+	Modified
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Missing:
+		return "missing"
+	case Extra:
+		return "extra"
+	case Modified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// Rule is a single alerting or recording rule definition.
+// Path is the rule file path, for rules returned by Prometheus
+// this is the path on the Prometheus server.
+type Rule struct {
+	Labels map[string]string
+	Group  string
+	Type   string
+	Name   string
+	Expr   string
+	Path   string
+	Line   int
+	For    time.Duration
+}
+
+func (r Rule) key() string {
+	return fmt.Sprintf("%s\n%s\n%s", r.Group, r.Type, r.Name)
+}
+
+// Difference describes a single rule that's not the same on the Prometheus server
+// and in rule files.
+// Local is nil for extra rules and Remote is nil for missing rules.
+type Difference struct {
+	Local   *Rule
+	Remote  *Rule
+	Details []string
+	Kind    Kind
+}
+
+// Compare matches local and remote rules by group and rule name and returns
+// all differences between them. If there are multiple rules with the same name
+// in the same group they are matched in order.
+func Compare(local, remote []Rule) (diffs []Difference) {
+	remoteByKey := map[string][]Rule{}
+	for _, r := range remote {
+		remoteByKey[r.key()] = append(remoteByKey[r.key()], r)
+	}
+
+	seen := map[string]int{}
+	for _, l := range local {
+		l := l
+		idx := seen[l.key()]
+		seen[l.key()]++
+
+		candidates := remoteByKey[l.key()]
+		if idx >= len(candidates) {
+			diffs = append(diffs, Difference{Kind: Missing, Local: &l})
+			continue
+		}
+		r := candidates[idx]
+		if details := compareRules(l, r); len(details) > 0 {
+			diffs = append(diffs, Difference{Kind: Modified, Local: &l, Remote: &r, Details: details})
+		}
+	}
+
+	for _, r := range remote {
+		r := r
+		if seen[r.key()] > 0 {
+			seen[r.key()]--
+			continue
+		}
+		diffs = append(diffs, Difference{Kind: Extra, Remote: &r})
+	}
+
+	return diffs
+}
+
+func compareRules(l, r Rule) (details []string) {
+	if normalizeExpr(l.Expr) != normalizeExpr(r.Expr) {
+		details = append(details, fmt.Sprintf("`expr` is `%s` in the rule file but `%s` on the server", l.Expr, r.Expr))
+	}
+	if l.For != r.For {
+		details = append(details, fmt.Sprintf("`for` is `%s` in the rule file but `%s` on the server",
+			output.HumanizeDuration(l.For), output.HumanizeDuration(r.For)))
+	}
+	if !maps.Equal(l.Labels, r.Labels) && (len(l.Labels) > 0 || len(r.Labels) > 0) {
+		details = append(details, fmt.Sprintf("`labels` are `%s` in the rule file but `%s` on the server",
+			formatLabels(l.Labels), formatLabels(r.Labels)))
+	}
+	return details
+}
+
+// Prometheus returns formatted queries, so compare parsed expressions
+// to ignore any whitespace or formatting changes.
+func normalizeExpr(expr string) string {
+	e, err := promparser.ParseExpr(expr)
+	if err != nil {
+		return strings.TrimSpace(expr)
+	}
+	return e.String()
+}
+
+func formatLabels(l map[string]string) string {
+	names := make([]string, 0, len(l))
+	for k := range l {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, k := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, l[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// FromAPI converts rule groups returned by Prometheus into a list of rules.
+func FromAPI(groups []promapi.RuleGroup) (rules []Rule) {
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			rules = append(rules, Rule{
+				Group:  group.Name,
+				Type:   rule.Type,
+				Name:   rule.Name,
+				Expr:   rule.Query,
+				For:    time.Duration(rule.Duration * float64(time.Second)),
+				Labels: rule.Labels,
+				Path:   group.File,
+			})
+		}
+	}
+	return rules
+}
+
+type fileGroups struct {
+	Groups []struct {
+		Name  string `yaml:"name"`
+		Rules []struct {
+			Labels map[string]string `yaml:"labels"`
+			Record yaml.Node         `yaml:"record"`
+			Alert  yaml.Node         `yaml:"alert"`
+			Expr   string            `yaml:"expr"`
+			For    string            `yaml:"for"`
+		} `yaml:"rules"`
+	} `yaml:"groups"`
+}
+
+// ReadRules returns all rules from a rule file.
+// Files without any rule groups are ignored.
+func ReadRules(path string, content []byte) (rules []Rule, err error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		if err = dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				return rules, nil
+			}
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		// Relaxed files with a list of rules have no groups we could compare.
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		var doc fileGroups
+		if err = node.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for _, group := range doc.Groups {
+			for _, rule := range group.Rules {
+				r := Rule{
+					Group:  group.Name,
+					Expr:   rule.Expr,
+					Labels: rule.Labels,
+					Path:   path,
+				}
+				switch {
+				case rule.Record.Value != "":
+					r.Type = RecordingRule
+					r.Name = rule.Record.Value
+					r.Line = rule.Record.Line
+				case rule.Alert.Value != "":
+					r.Type = AlertingRule
+					r.Name = rule.Alert.Value
+					r.Line = rule.Alert.Line
+				default:
+					continue
+				}
+				if rule.For != "" {
+					d, err := model.ParseDuration(rule.For)
+					if err != nil {
+						return nil, fmt.Errorf("invalid for value in %s:%d: %w", path, r.Line, err)
+					}
+					r.For = time.Duration(d)
+				}
+				rules = append(rules, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package drift_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/drift"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestReadRules(t *testing.T) {
+	type testCaseT struct {
+		title   string
+		content string
+		err     string
+		rules   []drift.Rule
+	}
+
+	testCases := []testCaseT{
+		{
+			title:   "empty",
+			content: "",
+		},
+		{
+			title:   "relaxed file",
+			content: "- record: foo\n  expr: sum(foo)\n",
+		},
+		{
+			title: "groups",
+			content: `groups:
+- name: foo
+  rules:
+  - record: foo:sum
+    expr: sum(foo)
+    labels:
+      job: foo
+  - alert: Foo
+    expr: foo:sum > 0
+    for: 5m
+---
+groups:
+- name: bar
+  rules:
+  - alert: Bar
+    expr: bar > 0
+  - expr: invalid
+`,
+			rules: []drift.Rule{
+				{Group: "foo", Type: drift.RecordingRule, Name: "foo:sum", Expr: "sum(foo)", Labels: map[string]string{"job": "foo"}, Path: "rules.yml", Line: 4},
+				{Group: "foo", Type: drift.AlertingRule, Name: "Foo", Expr: "foo:sum > 0", For: time.Minute * 5, Path: "rules.yml", Line: 8},
+				{Group: "bar", Type: drift.AlertingRule, Name: "Bar", Expr: "bar > 0", Path: "rules.yml", Line: 15},
+			},
+		},
+		{
+			title:   "invalid for",
+			content: "groups:\n- name: foo\n  rules:\n  - alert: Foo\n    expr: foo\n    for: abc\n",
+			err:     `invalid for value in rules.yml:4: not a valid duration string: "abc"`,
+		},
+		{
+			title:   "invalid yaml",
+			content: "groups: {\n",
+			err:     "failed to parse rules.yml: yaml: line 1: did not find expected node content",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			rules, err := drift.ReadRules("rules.yml", []byte(tc.content))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestFromAPI(t *testing.T) {
+	rules := drift.FromAPI([]promapi.RuleGroup{
+		{
+			Name: "foo",
+			File: "/etc/rules.yml",
+			Rules: []promapi.Rule{
+				{Type: "recording", Name: "foo:sum", Query: "sum(foo)"},
+				{Type: "alerting", Name: "Foo", Query: "foo:sum > 0", Duration: 300, Labels: map[string]string{"severity": "bug"}},
+			},
+		},
+	})
+	require.Equal(t, []drift.Rule{
+		{Group: "foo", Type: drift.RecordingRule, Name: "foo:sum", Expr: "sum(foo)", Path: "/etc/rules.yml"},
+		{Group: "foo", Type: drift.AlertingRule, Name: "Foo", Expr: "foo:sum > 0", For: time.Minute * 5, Labels: map[string]string{"severity": "bug"}, Path: "/etc/rules.yml"},
+	}, rules)
+}
+
+func TestCompare(t *testing.T) {
+	rule := func(group, typ, name, expr string, f time.Duration, labels map[string]string) drift.Rule {
+		return drift.Rule{Group: group, Type: typ, Name: name, Expr: expr, For: f, Labels: labels}
+	}
+
+	type testCaseT struct {
+		title  string
+		local  []drift.Rule
+		remote []drift.Rule
+		diffs  []drift.Difference
+	}
+
+	testCases := []testCaseT{
+		{
+			title: "empty",
+		},
+		{
+			title: "identical",
+			local: []drift.Rule{
+				rule("foo", drift.RecordingRule, "foo:sum", "sum by(job) (foo)", 0, nil),
+				rule("foo", drift.AlertingRule, "Foo", "foo:sum > 0", time.Minute, map[string]string{"a": "b"}),
+			},
+			remote: []drift.Rule{
+				rule("foo", drift.RecordingRule, "foo:sum", "sum by (job) (foo)", 0, map[string]string{}),
+				rule("foo", drift.AlertingRule, "Foo", "foo:sum > 0", time.Minute, map[string]string{"a": "b"}),
+			},
+		},
+		{
+			title: "missing and extra",
+			local: []drift.Rule{
+				rule("foo", drift.RecordingRule, "foo:sum", "sum(foo)", 0, nil),
+			},
+			remote: []drift.Rule{
+				rule("bar", drift.RecordingRule, "foo:sum", "sum(foo)", 0, nil),
+			},
+			diffs: []drift.Difference{
+				{Kind: drift.Missing, Local: &drift.Rule{Group: "foo", Type: drift.RecordingRule, Name: "foo:sum", Expr: "sum(foo)"}},
+				{Kind: drift.Extra, Remote: &drift.Rule{Group: "bar", Type: drift.RecordingRule, Name: "foo:sum", Expr: "sum(foo)"}},
+			},
+		},
+		{
+			title: "modified",
+			local: []drift.Rule{
+				rule("foo", drift.AlertingRule, "Foo", "foo > 0", time.Minute, map[string]string{"a": "b"}),
+			},
+			remote: []drift.Rule{
+				rule("foo", drift.AlertingRule, "Foo", "foo > 1", time.Minute*5, map[string]string{"a": "c"}),
+			},
+			diffs: []drift.Difference{
+				{
+					Kind:   drift.Modified,
+					Local:  &drift.Rule{Group: "foo", Type: drift.AlertingRule, Name: "Foo", Expr: "foo > 0", For: time.Minute, Labels: map[string]string{"a": "b"}},
+					Remote: &drift.Rule{Group: "foo", Type: drift.AlertingRule, Name: "Foo", Expr: "foo > 1", For: time.Minute * 5, Labels: map[string]string{"a": "c"}},
+					Details: []string{
+						"`expr` is `foo > 0` in the rule file but `foo > 1` on the server",
+						"`for` is `1m` in the rule file but `5m` on the server",
+						"`labels` are `{a=\"b\"}` in the rule file but `{a=\"c\"}` on the server",
+					},
+				},
+			},
+		},
+		{
+			title: "duplicated names",
+			local: []drift.Rule{
+				rule("foo", drift.AlertingRule, "Foo", "foo > 0", 0, map[string]string{"severity": "warning"}),
+				rule("foo", drift.AlertingRule, "Foo", "foo > 10", 0, map[string]string{"severity": "critical"}),
+			},
+			remote: []drift.Rule{
+				rule("foo", drift.AlertingRule, "Foo", "foo > 0", 0, map[string]string{"severity": "warning"}),
+			},
+			diffs: []drift.Difference{
+				{Kind: drift.Missing, Local: &drift.Rule{Group: "foo", Type: drift.AlertingRule, Name: "Foo", Expr: "foo > 10", Labels: map[string]string{"severity": "critical"}}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			require.Equal(t, tc.diffs, drift.Compare(tc.local, tc.remote))
+		})
+	}
+}
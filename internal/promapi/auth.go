@@ -0,0 +1,173 @@
+package promapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/sigv4"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Authenticator adds authentication to all requests sent to Prometheus.
+type Authenticator interface {
+	RoundTripper(next http.RoundTripper) http.RoundTripper
+}
+
+// readSecret returns the secret value, if secret file is set it will
+// be read on every call, so any secret rotation is picked up.
+func readSecret(value, path string) (string, error) {
+	if path == "" {
+		return value, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+type BasicAuth struct {
+	Username     string
+	Password     string
+	PasswordFile string
+}
+
+func (ba BasicAuth) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return basicAuthRoundTripper{auth: ba, next: next}
+}
+
+type basicAuthRoundTripper struct {
+	next http.RoundTripper
+	auth BasicAuth
+}
+
+func (rt basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	password, err := readSecret(rt.auth.Password, rt.auth.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(rt.auth.Username, password)
+	return rt.next.RoundTrip(req)
+}
+
+type BearerToken struct {
+	Token     string
+	TokenFile string
+}
+
+func (bt BearerToken) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return bearerTokenRoundTripper{auth: bt, next: next}
+}
+
+type bearerTokenRoundTripper struct {
+	next http.RoundTripper
+	auth BearerToken
+}
+
+func (rt bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := readSecret(rt.auth.Token, rt.auth.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return rt.next.RoundTrip(req)
+}
+
+type OAuth2 struct {
+	EndpointParams   map[string]string
+	ClientID         string
+	ClientSecret     string
+	ClientSecretFile string
+	TokenURL         string
+	Scopes           []string
+}
+
+func (o OAuth2) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	src := oauth2TokenSource{
+		auth:   o,
+		client: &http.Client{Transport: next},
+	}
+	return &oauth2.Transport{
+		Source: oauth2.ReuseTokenSource(nil, src),
+		Base:   next,
+	}
+}
+
+// oauth2TokenSource fetches a new token using client credentials flow
+// every time the previous token expires.
+type oauth2TokenSource struct {
+	client *http.Client
+	auth   OAuth2
+}
+
+func (ts oauth2TokenSource) Token() (*oauth2.Token, error) {
+	secret, err := readSecret(ts.auth.ClientSecret, ts.auth.ClientSecretFile)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	for k, v := range ts.auth.EndpointParams {
+		params.Set(k, v)
+	}
+	cfg := clientcredentials.Config{
+		ClientID:       ts.auth.ClientID,
+		ClientSecret:   secret,
+		TokenURL:       ts.auth.TokenURL,
+		Scopes:         ts.auth.Scopes,
+		EndpointParams: params,
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, ts.client)
+	return cfg.Token(ctx)
+}
+
+type SigV4 struct {
+	Region    string
+	AccessKey string
+	SecretKey string
+	Profile   string
+	RoleARN   string
+}
+
+func (s SigV4) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &sigV4RoundTripper{auth: s, next: next}
+}
+
+// sigV4RoundTripper creates the signing RoundTripper on the first request,
+// so any error with finding AWS credentials is reported as a query error.
+type sigV4RoundTripper struct {
+	next   http.RoundTripper
+	signer http.RoundTripper
+	err    error
+	auth   SigV4
+	once   sync.Once
+}
+
+func (rt *sigV4RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.once.Do(func() {
+		rt.signer, rt.err = sigv4.NewSigV4RoundTripper(&sigv4.SigV4Config{
+			Region:    rt.auth.Region,
+			AccessKey: rt.auth.AccessKey,
+			SecretKey: config.Secret(rt.auth.SecretKey),
+			Profile:   rt.auth.Profile,
+			RoleARN:   rt.auth.RoleARN,
+		}, rt.next)
+	})
+	if rt.err != nil {
+		return nil, rt.err
+	}
+	if req.Body == nil {
+		req = req.Clone(req.Context())
+		req.Body = http.NoBody
+	}
+	return rt.signer.RoundTrip(req)
+}
@@ -0,0 +1,148 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func authTestServer(t *testing.T, check func(r *http.Request) bool) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !check(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"unauthorized","error":"bad credentials"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func authTestQuery(t *testing.T, uri string, auth promapi.Authenticator) error {
+	prom := promapi.NewPrometheus("test", uri, "", nil, time.Second, 1, 100, nil, nil, auth, promapi.RetryPolicy{})
+	prom.StartWorkers()
+	defer prom.Close()
+	// Use a unique query each time so we never get a cached response.
+	_, err := prom.Query(context.Background(), "up{id=\""+time.Now().String()+"\"}")
+	return err
+}
+
+func TestBasicAuth(t *testing.T) {
+	srv := authTestServer(t, func(r *http.Request) bool {
+		user, pass, ok := r.BasicAuth()
+		return ok && user == "bob" && (pass == "secret" || pass == "rotated")
+	})
+
+	require.NoError(t, authTestQuery(t, srv.URL, promapi.BasicAuth{Username: "bob", Password: "secret"}))
+	require.Error(t, authTestQuery(t, srv.URL, promapi.BasicAuth{Username: "bob", Password: "bad"}))
+	require.Error(t, authTestQuery(t, srv.URL, nil))
+
+	file := path.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(file, []byte("secret\n"), 0o600))
+	auth := promapi.BasicAuth{Username: "bob", PasswordFile: file}
+	require.NoError(t, authTestQuery(t, srv.URL, auth))
+
+	require.NoError(t, os.WriteFile(file, []byte("wrong"), 0o600))
+	require.Error(t, authTestQuery(t, srv.URL, auth))
+
+	require.NoError(t, os.WriteFile(file, []byte("rotated"), 0o600))
+	require.NoError(t, authTestQuery(t, srv.URL, auth))
+
+	require.NoError(t, os.Remove(file))
+	err := authTestQuery(t, srv.URL, auth)
+	require.ErrorContains(t, err, "failed to read secret file")
+}
+
+func TestBearerToken(t *testing.T) {
+	srv := authTestServer(t, func(r *http.Request) bool {
+		h := r.Header.Get("Authorization")
+		return h == "Bearer abc" || h == "Bearer def"
+	})
+
+	require.NoError(t, authTestQuery(t, srv.URL, promapi.BearerToken{Token: "abc"}))
+	require.Error(t, authTestQuery(t, srv.URL, promapi.BearerToken{Token: "xyz"}))
+
+	file := path.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(file, []byte("abc"), 0o600))
+	auth := promapi.BearerToken{TokenFile: file}
+	require.NoError(t, authTestQuery(t, srv.URL, auth))
+
+	require.NoError(t, os.WriteFile(file, []byte("xyz"), 0o600))
+	require.Error(t, authTestQuery(t, srv.URL, auth))
+
+	require.NoError(t, os.WriteFile(file, []byte("def\n"), 0o600))
+	require.NoError(t, authTestQuery(t, srv.URL, auth))
+}
+
+func TestOAuth2(t *testing.T) {
+	var tokens atomic.Int64
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		user, pass, _ := r.BasicAuth()
+		if user != "client" || pass != "secret" || r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("audience") != "prom" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		tokens.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		// Token expires immediately so every request will fetch a new one.
+		_, _ = w.Write([]byte(`{"access_token":"token-` + r.Form.Get("scope") + `","token_type":"Bearer","expires_in":1}`))
+	}))
+	defer tokenSrv.Close()
+
+	srv := authTestServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer token-read write"
+	})
+
+	file := path.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("secret"), 0o600))
+
+	auth := promapi.OAuth2{
+		ClientID:         "client",
+		ClientSecretFile: file,
+		TokenURL:         tokenSrv.URL,
+		Scopes:           []string{"read", "write"},
+		EndpointParams:   map[string]string{"audience": "prom"},
+	}
+	require.NoError(t, authTestQuery(t, srv.URL, auth))
+	require.NoError(t, authTestQuery(t, srv.URL, auth))
+	require.Equal(t, int64(2), tokens.Load())
+
+	auth.ClientSecretFile = ""
+	auth.ClientSecret = "bad"
+	err := authTestQuery(t, srv.URL, auth)
+	require.ErrorContains(t, err, "invalid_client")
+}
+
+func TestSigV4(t *testing.T) {
+	srv := authTestServer(t, func(r *http.Request) bool {
+		h := r.Header.Get("Authorization")
+		return strings.HasPrefix(h, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") &&
+			strings.Contains(h, "/eu-west-1/aps/aws4_request") &&
+			r.Header.Get("X-Amz-Date") != ""
+	})
+
+	require.NoError(t, authTestQuery(t, srv.URL, promapi.SigV4{
+		Region:    "eu-west-1",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+	}))
+	require.Error(t, authTestQuery(t, srv.URL, promapi.SigV4{
+		Region:    "us-east-1",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+	}))
+}
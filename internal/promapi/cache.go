@@ -14,12 +14,16 @@ type cacheEntry struct {
 }
 
 type endpointStats struct {
-	hits   int
-	misses int
+	hits       int
+	misses     int
+	diskHits   int
+	diskMisses int
 }
 
-func (e *endpointStats) hit()  { e.hits++ }
-func (e *endpointStats) miss() { e.misses++ }
+func (e *endpointStats) hit()      { e.hits++ }
+func (e *endpointStats) miss()     { e.misses++ }
+func (e *endpointStats) diskHit()  { e.diskHits++ }
+func (e *endpointStats) diskMiss() { e.diskMisses++ }
 
 func newQueryCache(maxStale time.Duration) *queryCache {
 	return &queryCache{
@@ -32,6 +36,7 @@ func newQueryCache(maxStale time.Duration) *queryCache {
 type queryCache struct {
 	entries   map[uint64]*cacheEntry
 	stats     map[string]*endpointStats
+	disk      *DiskCache
 	maxStale  time.Duration
 	evictions int
 	mu        sync.Mutex
@@ -80,6 +85,41 @@ func (c *queryCache) set(key uint64, val any, ttl time.Duration) {
 	}
 }
 
+// load tries to find query results in the on-disk cache, if one is used.
+// Any result found there will also be stored in memory.
+func (c *queryCache) load(q querier) (result queryResult, ok bool) {
+	if c.disk == nil {
+		return result, false
+	}
+
+	var expiresAt time.Time
+	result, expiresAt, ok = c.disk.get(q)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !ok {
+		c.endpointStats(q.Endpoint()).diskMiss()
+		return result, false
+	}
+
+	c.endpointStats(q.Endpoint()).diskHit()
+	c.entries[q.CacheKey()] = &cacheEntry{
+		data:      result,
+		expiresAt: expiresAt,
+		lastGet:   time.Now(),
+	}
+	return result, true
+}
+
+// persist stores query results in the on-disk cache, if one is used.
+func (c *queryCache) persist(q querier, result queryResult) {
+	if c.disk == nil {
+		return
+	}
+	c.disk.set(q, result)
+}
+
 func (c *queryCache) gc() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -98,11 +138,13 @@ func (c *queryCache) gc() {
 }
 
 type cacheCollector struct {
-	cache     *queryCache
-	entries   *prometheus.Desc
-	hits      *prometheus.Desc
-	misses    *prometheus.Desc
-	evictions *prometheus.Desc
+	cache      *queryCache
+	entries    *prometheus.Desc
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	evictions  *prometheus.Desc
+	diskHits   *prometheus.Desc
+	diskMisses *prometheus.Desc
 }
 
 func newCacheCollector(cache *queryCache, name string) *cacheCollector {
@@ -132,6 +174,18 @@ func newCacheCollector(cache *queryCache, name string) *cacheCollector {
 			nil,
 			prometheus.Labels{"name": name},
 		),
+		diskHits: prometheus.NewDesc(
+			"pint_prometheus_disk_cache_hits_total",
+			"Total number of on-disk query cache hits",
+			[]string{"endpoint"},
+			prometheus.Labels{"name": name},
+		),
+		diskMisses: prometheus.NewDesc(
+			"pint_prometheus_disk_cache_miss_total",
+			"Total number of on-disk query cache misses",
+			[]string{"endpoint"},
+			prometheus.Labels{"name": name},
+		),
 	}
 }
 
@@ -140,6 +194,8 @@ func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.hits
 	ch <- c.misses
 	ch <- c.evictions
+	ch <- c.diskHits
+	ch <- c.diskMisses
 }
 
 func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
@@ -150,6 +206,10 @@ func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
 	for endpoint, stats := range c.cache.stats {
 		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.hits), endpoint)
 		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.misses), endpoint)
+		if c.cache.disk != nil {
+			ch <- prometheus.MustNewConstMetric(c.diskHits, prometheus.CounterValue, float64(stats.diskHits), endpoint)
+			ch <- prometheus.MustNewConstMetric(c.diskMisses, prometheus.CounterValue, float64(stats.diskMisses), endpoint)
+		}
 	}
 	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(c.cache.evictions))
 }
@@ -0,0 +1,269 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+const cassetteInfoFile = "cassette.json"
+
+var (
+	cassetteMu     sync.RWMutex
+	activeCassette *Cassette
+)
+
+// timeNow is used for all relative query ranges.
+// When a cassette is active it will always return the time the cassette
+// was first recorded, so range queries will be the same when replaying it.
+func timeNow() time.Time {
+	cassetteMu.RLock()
+	defer cassetteMu.RUnlock()
+	if activeCassette != nil {
+		return activeCassette.time
+	}
+	return time.Now()
+}
+
+func currentCassette() *Cassette {
+	cassetteMu.RLock()
+	defer cassetteMu.RUnlock()
+	return activeCassette
+}
+
+// Cassette stores responses for all Prometheus API queries in a directory.
+// When recording every query result is written to disk, keyed by the query
+// cache key. When replaying results are read back from disk and no
+// request is sent to any Prometheus server.
+type Cassette struct {
+	time     time.Time
+	dir      string
+	isReplay bool
+}
+
+type cassetteInfo struct {
+	Time time.Time `json:"time"`
+}
+
+// RecordTo starts recording all Prometheus API queries into given directory.
+func RecordTo(dir string) (*Cassette, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	c := Cassette{dir: dir, time: time.Now().Truncate(time.Second)}
+	if err := writeJSONFile(filepath.Join(dir, cassetteInfoFile), cassetteInfo{Time: c.time}); err != nil {
+		return nil, fmt.Errorf("failed to write cassette info: %w", err)
+	}
+	setCassette(&c)
+	slog.Info("Recording Prometheus API responses", slog.String("dir", dir))
+	return &c, nil
+}
+
+// ReplayFrom starts replaying all Prometheus API queries from given directory.
+func ReplayFrom(dir string) (*Cassette, error) {
+	var info cassetteInfo
+	if err := readJSONFile(filepath.Join(dir, cassetteInfoFile), &info); err != nil {
+		return nil, fmt.Errorf("failed to read cassette info: %w", err)
+	}
+
+	c := Cassette{dir: dir, time: info.Time, isReplay: true}
+	setCassette(&c)
+	slog.Info("Replaying Prometheus API responses", slog.String("dir", dir))
+	return &c, nil
+}
+
+// Stop will stop recording or replaying queries.
+func (c *Cassette) Stop() {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+	if activeCassette == c {
+		activeCassette = nil
+	}
+}
+
+func setCassette(c *Cassette) {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+	activeCassette = c
+}
+
+func (c *Cassette) path(key uint64) string {
+	return filepath.Join(c.dir, strconv.FormatUint(key, 16)+".json")
+}
+
+type cassetteError struct {
+	API     *APIError `json:"api,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+type cassetteEntry struct {
+	Value    json.RawMessage `json:"value,omitempty"`
+	Error    *cassetteError  `json:"error,omitempty"`
+	URI      string          `json:"uri"`
+	Endpoint string          `json:"endpoint"`
+	Query    string          `json:"query"`
+	Stats    QueryStats      `json:"stats"`
+}
+
+type cassetteSample struct {
+	Labels labels.Labels `json:"labels"`
+	Value  string        `json:"value"`
+}
+
+func (c *Cassette) record(prom *Prometheus, q querier, result queryResult) {
+	if errors.Is(result.err, context.Canceled) {
+		return
+	}
+
+	entry := cassetteEntry{
+		URI:      prom.safeURI,
+		Endpoint: q.Endpoint(),
+		Query:    q.String(),
+		Stats:    result.stats,
+	}
+
+	if result.err != nil {
+		var apiErr APIError
+		if errors.As(result.err, &apiErr) {
+			entry.Error = &cassetteError{API: &apiErr}
+		} else {
+			entry.Error = &cassetteError{Message: decodeError(result.err)}
+		}
+	} else {
+		var err error
+		if entry.Value, err = encodeResultValue(result.value); err != nil {
+			slog.Error("Failed to encode query result for the cassette", slog.Any("err", err), slog.String("query", q.String()))
+			return
+		}
+	}
+
+	if err := writeJSONFile(c.path(q.CacheKey()), entry); err != nil {
+		slog.Error("Failed to record query result", slog.Any("err", err), slog.String("query", q.String()))
+	}
+}
+
+func (c *Cassette) replay(prom *Prometheus, q querier) (result queryResult) {
+	var entry cassetteEntry
+	if err := readJSONFile(c.path(q.CacheKey()), &entry); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			result.err = fmt.Errorf("no recorded response for %s query %q on %s", q.Endpoint(), q.String(), prom.safeURI)
+		} else {
+			result.err = fmt.Errorf("failed to read recorded response: %w", err)
+		}
+		return result
+	}
+
+	result.stats = entry.Stats
+	if entry.Error != nil {
+		if entry.Error.API != nil {
+			result.err = *entry.Error.API
+		} else {
+			result.err = errors.New(entry.Error.Message)
+		}
+		return result
+	}
+
+	var err error
+	result.value, err = decodeResultValue(entry.Endpoint, entry.Value)
+	if err != nil {
+		result.value = nil
+		result.err = fmt.Errorf("failed to decode recorded response: %w", err)
+	}
+	return result
+}
+
+// encodeResultValue returns the JSON representation of a query result value,
+// it's used to store results on disk.
+func encodeResultValue(value any) (json.RawMessage, error) {
+	if samples, ok := value.([]Sample); ok {
+		cs := make([]cassetteSample, 0, len(samples))
+		for _, s := range samples {
+			cs = append(cs, cassetteSample{
+				Labels: s.Labels,
+				Value:  strconv.FormatFloat(s.Value, 'g', -1, 64),
+			})
+		}
+		value = cs
+	}
+	return json.Marshal(value)
+}
+
+// decodeResultValue is the reverse of encodeResultValue, it needs to know
+// the API endpoint to decode the value into the correct type.
+func decodeResultValue(endpoint string, raw json.RawMessage) (value any, err error) {
+	switch endpoint {
+	case instantQuery{}.Endpoint():
+		var cs []cassetteSample
+		err = json.Unmarshal(raw, &cs)
+		samples := make([]Sample, 0, len(cs))
+		for _, s := range cs {
+			var v float64
+			if v, err = strconv.ParseFloat(s.Value, 64); err != nil {
+				break
+			}
+			samples = append(samples, Sample{Labels: s.Labels, Value: v})
+		}
+		value = samples
+	case rangeQuery{}.Endpoint():
+		var ranges MetricTimeRanges
+		err = json.Unmarshal(raw, &ranges)
+		value = ranges
+	case metadataQuery{}.Endpoint():
+		var meta map[string][]v1.Metadata
+		err = json.Unmarshal(raw, &meta)
+		value = meta
+	case flagsQuery{}.Endpoint():
+		var flags v1.FlagsResult
+		err = json.Unmarshal(raw, &flags)
+		value = flags
+	case seriesQuery{}.Endpoint():
+		var series []labels.Labels
+		err = json.Unmarshal(raw, &series)
+		value = series
+	case labelNamesQuery{}.Endpoint(), labelValuesQuery{}.Endpoint():
+		var values []string
+		err = json.Unmarshal(raw, &values)
+		value = values
+	case tsdbStatusQuery{}.Endpoint():
+		var status v1.TSDBResult
+		err = json.Unmarshal(raw, &status)
+		value = status
+	case rulesQuery{}.Endpoint():
+		var groups []RuleGroup
+		err = json.Unmarshal(raw, &groups)
+		value = groups
+	case configQuery{}.Endpoint():
+		var cfg PrometheusConfig
+		err = json.Unmarshal(raw, &cfg)
+		value = cfg
+	default:
+		err = fmt.Errorf("unsupported endpoint %q", endpoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func writeJSONFile(path string, v any) error {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
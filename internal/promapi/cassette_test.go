@@ -0,0 +1,118 @@
+package promapi_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+type cassetteResults struct {
+	query    *promapi.QueryResult
+	rng      *promapi.RangeQueryResult
+	metadata *promapi.MetadataResult
+	flags    *promapi.FlagsResult
+	config   *promapi.ConfigResult
+	queryErr string
+}
+
+// cassetteRange is a fixed range query time range, so the number of
+// range query slices doesn't depend on when the test runs.
+type cassetteRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func (cr cassetteRange) Start() time.Time    { return cr.start }
+func (cr cassetteRange) End() time.Time      { return cr.end }
+func (cr cassetteRange) Dur() time.Duration  { return cr.end.Sub(cr.start) }
+func (cr cassetteRange) Step() time.Duration { return time.Minute }
+func (cr cassetteRange) String() string      { return cr.start.String() + "/" + cr.end.String() }
+
+func runCassetteQueries(t *testing.T, fg *promapi.FailoverGroup, rng cassetteRange) (r cassetteResults) {
+	ctx := context.Background()
+	var err error
+
+	r.query, err = fg.Query(ctx, `sum(http_requests_total) by (job)`)
+	require.NoError(t, err)
+	r.rng, err = fg.RangeQuery(ctx, `http_requests_total`, rng)
+	require.NoError(t, err)
+	r.metadata, err = fg.Metadata(ctx, "http_requests_total")
+	require.NoError(t, err)
+	r.flags, err = fg.Flags(ctx)
+	require.NoError(t, err)
+	r.config, err = fg.Config(ctx)
+	require.NoError(t, err)
+	_, err = fg.Query(ctx, `sum(`)
+	require.Error(t, err)
+	r.queryErr = err.Error()
+	return r
+}
+
+func TestCassette(t *testing.T) {
+	dir := t.TempDir()
+	cassetteDir := path.Join(dir, "cassette")
+
+	// 3h range starting at a 2h slice boundary is always split into 2 slices.
+	start := time.Now().UTC().Truncate(time.Hour * 2).Add(time.Hour * -4)
+	rng := cassetteRange{start: start, end: start.Add(time.Hour * 3)}
+
+	recorder, err := promapi.RecordTo(cassetteDir)
+	require.NoError(t, err)
+	recorded := runCassetteQueries(t, newFixtureGroup(t, promapi.FixtureOptions{
+		Exposition: []string{writeFixtureFile(t, dir, "metrics.txt", `# TYPE http_requests_total counter
+http_requests_total{job="api",code="200"} 100
+http_requests_total{job="api",code="500"} 5
+`)},
+		Flags: writeFixtureFile(t, dir, "flags.json", `{"storage.tsdb.retention.time": "30d"}`),
+		Range: time.Hour * 6,
+	}), rng)
+	recorder.Stop()
+
+	require.Len(t, recorded.query.Series, 1)
+	require.Len(t, recorded.rng.Series.Ranges, 2)
+	require.Len(t, recorded.metadata.Metadata, 1)
+	require.Len(t, recorded.flags.Flags, 1)
+
+	entries, err := os.ReadDir(cassetteDir)
+	require.NoError(t, err)
+	// cassette.json, query, query error, 2 range query slices, metadata, flags and config.
+	require.Len(t, entries, 8)
+
+	// Replay using a server without any data, all responses must come from the cassette.
+	player, err := promapi.ReplayFrom(cassetteDir)
+	require.NoError(t, err)
+	defer player.Stop()
+
+	fg := newFixtureGroup(t, promapi.FixtureOptions{})
+	replayed := runCassetteQueries(t, fg, rng)
+	require.Equal(t, recorded.query, replayed.query)
+	require.Equal(t, recorded.metadata, replayed.metadata)
+	require.Equal(t, recorded.flags, replayed.flags)
+	require.Equal(t, recorded.config, replayed.config)
+	require.Equal(t, recorded.queryErr, replayed.queryErr)
+	// Time locations are lost when encoding ranges, so compare them as strings.
+	require.Equal(t, recorded.rng.Series.Ranges.String(), replayed.rng.Series.Ranges.String())
+	require.True(t, recorded.rng.Series.From.Equal(replayed.rng.Series.From))
+	require.True(t, recorded.rng.Series.Until.Equal(replayed.rng.Series.Until))
+	require.Equal(t, recorded.rng.Stats, replayed.rng.Stats)
+
+	_, err = fg.Query(context.Background(), `count(up)`)
+	require.EqualError(t, err, `no recorded response for /api/v1/query query "count(up)" on fixture://fixture`)
+}
+
+func TestCassetteErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := promapi.ReplayFrom(dir)
+	require.EqualError(t, err, "failed to read cassette info: open "+path.Join(dir, "cassette.json")+": no such file or directory")
+
+	file := writeFixtureFile(t, dir, "file", "")
+	_, err = promapi.RecordTo(path.Join(file, "cassette"))
+	require.EqualError(t, err, "failed to create cassette directory: mkdir "+file+": not a directory")
+}
@@ -67,6 +67,10 @@ func (q configQuery) Run() queryResult {
 	return qr
 }
 
+func (q configQuery) Context() context.Context {
+	return q.ctx
+}
+
 func (q configQuery) Endpoint() string {
 	return "/api/v1/status/config"
 }
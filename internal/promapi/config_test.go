@@ -125,7 +125,7 @@ func TestConfig(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(strings.TrimPrefix(tc.prefix, "/"), func(t *testing.T) {
-			prom := promapi.NewPrometheus("test", srv.URL+tc.prefix, "", nil, tc.timeout, 1, 100, nil)
+			prom := promapi.NewPrometheus("test", srv.URL+tc.prefix, "", nil, tc.timeout, 1, 100, nil, nil, nil, promapi.RetryPolicy{})
 			prom.StartWorkers()
 			defer prom.Close()
 
@@ -180,7 +180,7 @@ func TestConfigHeaders(t *testing.T) {
 			defer srv.Close()
 
 			fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL, "", tc.config, time.Second, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL, "", tc.config, time.Second, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
 			}, true, "up", nil, nil, nil)
 
 			reg := prometheus.NewRegistry()
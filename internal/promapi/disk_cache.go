@@ -0,0 +1,270 @@
+package promapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	diskCacheVersion = 1
+	diskCacheTmpTTL  = time.Hour
+)
+
+var (
+	diskCacheMu     sync.RWMutex
+	activeDiskCache *DiskCache
+)
+
+func currentDiskCache() *DiskCache {
+	diskCacheMu.RLock()
+	defer diskCacheMu.RUnlock()
+	return activeDiskCache
+}
+
+// DiskCache stores results of successful queries in a directory, so they can
+// be reused by other pint processes, for example CI jobs running on the same host.
+// Every result is stored in a separate file named after the query cache key.
+// Files are first written to a temporary location and then renamed, so
+// multiple pint processes can safely share the same directory.
+// Modification time of each file is set to the time it expires, which allows
+// to find expired files without reading them.
+// Range queries are split into time slices aligned to full hours, so a query
+// for the last 7 days can reuse all slices stored by earlier runs and only
+// needs to send requests for the most recent ones.
+type DiskCache struct {
+	dir       string
+	maxSize   int64
+	size      int64
+	evictions int
+	mu        sync.Mutex
+}
+
+type diskCacheEntry struct {
+	Expires  time.Time       `json:"expires"`
+	Value    json.RawMessage `json:"value"`
+	Endpoint string          `json:"endpoint"`
+	Query    string          `json:"query"`
+	Stats    QueryStats      `json:"stats"`
+	Version  int             `json:"version"`
+}
+
+// UseDiskCache will store all successful query results in given directory
+// and use stored results for queries that are not in the memory cache.
+// If the total size of all files exceeds maxSize then entries that expire
+// first will be removed.
+func UseDiskCache(dir string, maxSize int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	dc := DiskCache{dir: dir, maxSize: maxSize}
+	dc.gc()
+
+	diskCacheMu.Lock()
+	activeDiskCache = &dc
+	diskCacheMu.Unlock()
+
+	slog.Info("Using on-disk query cache", slog.String("dir", dir), slog.Int64("maxSize", maxSize))
+	return &dc, nil
+}
+
+// Close will stop using this cache and remove all expired entries.
+func (dc *DiskCache) Close() {
+	diskCacheMu.Lock()
+	if activeDiskCache == dc {
+		activeDiskCache = nil
+	}
+	diskCacheMu.Unlock()
+	dc.gc()
+}
+
+func (dc *DiskCache) path(key uint64) string {
+	return filepath.Join(dc.dir, strconv.FormatUint(key, 16)+".json")
+}
+
+func (dc *DiskCache) get(q querier) (result queryResult, expiresAt time.Time, ok bool) {
+	var entry diskCacheEntry
+	if err := readJSONFile(dc.path(q.CacheKey()), &entry); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Debug("Failed to read on-disk cache entry", slog.Any("err", err), slog.String("query", q.String()))
+		}
+		return result, expiresAt, false
+	}
+
+	// Entries written by a different pint version or hash collisions.
+	if entry.Version != diskCacheVersion || entry.Endpoint != q.Endpoint() || entry.Query != q.String() {
+		return result, expiresAt, false
+	}
+
+	if !entry.Expires.After(time.Now()) {
+		return result, expiresAt, false
+	}
+
+	var err error
+	if result.value, err = decodeResultValue(entry.Endpoint, entry.Value); err != nil {
+		slog.Debug("Failed to decode on-disk cache entry", slog.Any("err", err), slog.String("query", q.String()))
+		return queryResult{}, expiresAt, false
+	}
+	result.stats = entry.Stats
+	return result, entry.Expires, true
+}
+
+func (dc *DiskCache) set(q querier, result queryResult) {
+	ttl := q.CacheTTL()
+	if ttl <= 0 {
+		return
+	}
+
+	entry := diskCacheEntry{
+		Version:  diskCacheVersion,
+		Expires:  time.Now().Add(ttl),
+		Endpoint: q.Endpoint(),
+		Query:    q.String(),
+		Stats:    result.stats,
+	}
+
+	var err error
+	if entry.Value, err = encodeResultValue(result.value); err != nil {
+		slog.Error("Failed to encode query result for the on-disk cache", slog.Any("err", err), slog.String("query", q.String()))
+		return
+	}
+
+	size, err := dc.write(q.CacheKey(), entry)
+	if err != nil {
+		slog.Error("Failed to write on-disk cache entry", slog.Any("err", err), slog.String("query", q.String()))
+		return
+	}
+
+	dc.mu.Lock()
+	dc.size += size
+	isFull := dc.size > dc.maxSize
+	dc.mu.Unlock()
+
+	if isFull {
+		dc.gc()
+	}
+}
+
+func (dc *DiskCache) write(key uint64, entry diskCacheEntry) (int64, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+
+	// Each process must use a unique temporary file, rename is atomic
+	// so other processes will only ever see complete entries.
+	f, err := os.CreateTemp(dc.dir, "*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	_, err = f.Write(body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chtimes(f.Name(), time.Now(), entry.Expires)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), dc.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return 0, err
+	}
+	return int64(len(body)), nil
+}
+
+type diskCacheFile struct {
+	expiresAt time.Time
+	path      string
+	size      int64
+}
+
+// gc removes expired entries and, if the cache is still too big,
+// entries that would expire first until the total size drops
+// below 90% of the size limit.
+// Files might be removed by other processes at the same time,
+// so any missing file is ignored.
+func (dc *DiskCache) gc() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dirEntries, err := os.ReadDir(dc.dir)
+	if err != nil {
+		slog.Error("Failed to read on-disk cache directory", slog.Any("err", err), slog.String("dir", dc.dir))
+		return
+	}
+
+	now := time.Now()
+	var total int64
+	var evicted int
+	files := make([]diskCacheFile, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		if de.IsDir() {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dc.dir, de.Name())
+		switch {
+		case strings.HasSuffix(de.Name(), ".tmp"):
+			// Leftovers from a process that was killed while writing.
+			if now.Sub(info.ModTime()) > diskCacheTmpTTL {
+				_ = os.Remove(path)
+			}
+		case strings.HasSuffix(de.Name(), ".json"):
+			if !info.ModTime().After(now) {
+				if dc.remove(path) {
+					evicted++
+				}
+				continue
+			}
+			files = append(files, diskCacheFile{path: path, expiresAt: info.ModTime(), size: info.Size()})
+			total += info.Size()
+		}
+	}
+
+	if total > dc.maxSize {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].expiresAt.Before(files[j].expiresAt)
+		})
+		for _, f := range files {
+			if total <= dc.maxSize/10*9 {
+				break
+			}
+			if dc.remove(f.path) {
+				evicted++
+			}
+			total -= f.size
+		}
+	}
+
+	dc.size = total
+	dc.evictions += evicted
+	slog.Debug(
+		"Cleaned on-disk query cache",
+		slog.String("dir", dc.dir),
+		slog.Int64("size", total),
+		slog.Int("evicted", evicted),
+	)
+}
+
+func (dc *DiskCache) remove(path string) bool {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Debug("Failed to remove on-disk cache entry", slog.Any("err", err), slog.String("path", path))
+		return false
+	}
+	return err == nil
+}
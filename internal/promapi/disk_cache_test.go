@@ -0,0 +1,214 @@
+package promapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+type testQuery struct {
+	expr string
+	ttl  time.Duration
+}
+
+func (q testQuery) Context() context.Context { return context.Background() }
+func (q testQuery) Endpoint() string         { return instantQuery{}.Endpoint() }
+func (q testQuery) String() string           { return q.expr }
+func (q testQuery) CacheKey() uint64         { return hash(q.Endpoint(), q.expr) }
+func (q testQuery) CacheTTL() time.Duration  { return q.ttl }
+func (q testQuery) Run() queryResult         { return queryResult{} }
+
+func TestDiskCacheSharedDir(t *testing.T) {
+	dir := t.TempDir()
+	dc1 := &DiskCache{dir: dir, maxSize: 1024 * 1024}
+	dc2 := &DiskCache{dir: dir, maxSize: 1024 * 1024}
+
+	q := testQuery{expr: "up", ttl: time.Minute}
+	_, _, ok := dc2.get(q)
+	require.False(t, ok)
+
+	dc1.set(q, queryResult{
+		value: []Sample{{Labels: labels.FromStrings("job", "foo"), Value: 1.5}},
+		stats: QueryStats{Samples: QuerySamples{PeakSamples: 5}},
+	})
+
+	result, expiresAt, ok := dc2.get(q)
+	require.True(t, ok)
+	require.Equal(t, []Sample{{Labels: labels.FromStrings("job", "foo"), Value: 1.5}}, result.value)
+	require.Equal(t, 5, result.stats.Samples.PeakSamples)
+	require.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second*5)
+
+	info, err := os.Stat(dc1.path(q.CacheKey()))
+	require.NoError(t, err)
+	require.Equal(t, expiresAt.Unix(), info.ModTime().Unix())
+
+	// Same key but a different query, which should never be returned.
+	_, _, ok = dc2.get(testQuery{expr: "down", ttl: time.Minute})
+	require.False(t, ok)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestDiskCacheNoTTL(t *testing.T) {
+	dc := &DiskCache{dir: t.TempDir(), maxSize: 1024 * 1024}
+	q := testQuery{expr: "up"}
+	dc.set(q, queryResult{value: []Sample{}})
+	_, _, ok := dc.get(q)
+	require.False(t, ok)
+}
+
+func TestDiskCacheExpired(t *testing.T) {
+	dc := &DiskCache{dir: t.TempDir(), maxSize: 1024 * 1024}
+	q := testQuery{expr: "up", ttl: time.Minute}
+	dc.set(q, queryResult{value: []Sample{}})
+
+	_, err := dc.write(q.CacheKey(), diskCacheEntry{
+		Version:  diskCacheVersion,
+		Expires:  time.Now().Add(time.Minute * -1),
+		Endpoint: q.Endpoint(),
+		Query:    q.String(),
+		Value:    []byte("[]"),
+	})
+	require.NoError(t, err)
+
+	_, _, ok := dc.get(q)
+	require.False(t, ok)
+
+	dc.gc()
+	require.Equal(t, 1, dc.evictions)
+	require.Equal(t, int64(0), dc.size)
+	_, err = os.Stat(dc.path(q.CacheKey()))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDiskCacheVersionMismatch(t *testing.T) {
+	dc := &DiskCache{dir: t.TempDir(), maxSize: 1024 * 1024}
+	q := testQuery{expr: "up", ttl: time.Minute}
+
+	_, err := dc.write(q.CacheKey(), diskCacheEntry{
+		Version:  diskCacheVersion + 1,
+		Expires:  time.Now().Add(time.Minute),
+		Endpoint: q.Endpoint(),
+		Query:    q.String(),
+		Value:    []byte(`"some new format"`),
+	})
+	require.NoError(t, err)
+
+	_, _, ok := dc.get(q)
+	require.False(t, ok)
+}
+
+func TestDiskCacheMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	dc := &DiskCache{dir: dir, maxSize: 2000}
+
+	for i := 1; i <= 20; i++ {
+		dc.set(
+			testQuery{expr: strings.Repeat("x", i), ttl: time.Minute * time.Duration(i)},
+			queryResult{value: []Sample{{Labels: labels.FromStrings("job", "foo"), Value: 1}}},
+		)
+	}
+	require.LessOrEqual(t, dc.size, dc.maxSize)
+	require.Positive(t, dc.evictions)
+
+	// Entries that expire first should be evicted first.
+	_, _, ok := dc.get(testQuery{expr: "x", ttl: time.Minute})
+	require.False(t, ok)
+	_, _, ok = dc.get(testQuery{expr: strings.Repeat("x", 20), ttl: time.Minute * 20})
+	require.True(t, ok)
+}
+
+func TestDiskCacheTmpFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	fresh := filepath.Join(dir, "fresh.tmp")
+	require.NoError(t, os.WriteFile(fresh, []byte("{"), 0o644))
+	stale := filepath.Join(dir, "stale.tmp")
+	require.NoError(t, os.WriteFile(stale, []byte("{"), 0o644))
+	require.NoError(t, os.Chtimes(stale, time.Now(), time.Now().Add(diskCacheTmpTTL*-2)))
+
+	dc := &DiskCache{dir: dir, maxSize: 1024}
+	dc.gc()
+
+	_, err := os.Stat(fresh)
+	require.NoError(t, err)
+	_, err = os.Stat(stale)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDiskCacheFailoverGroup(t *testing.T) {
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[1614859502.068,"1"]}]}}`))
+	}))
+	defer srv.Close()
+
+	dc, err := UseDiskCache(t.TempDir(), 1024*1024)
+	require.NoError(t, err)
+	defer dc.Close()
+
+	type runResult struct {
+		series     []Sample
+		diskHits   float64
+		diskMisses float64
+	}
+	run := func() (rr runResult) {
+		fg := NewFailoverGroup("test", srv.URL, []*Prometheus{
+			NewPrometheus("test", srv.URL, "", nil, time.Second, 1, 100, nil, nil, nil, RetryPolicy{}),
+		}, true, "up", nil, nil, nil)
+		reg := prometheus.NewRegistry()
+		fg.StartWorkers(reg)
+		defer fg.Close(reg)
+
+		qr, err := fg.Query(context.Background(), "up")
+		require.NoError(t, err)
+		rr.series = qr.Series
+
+		mfs, err := reg.Gather()
+		require.NoError(t, err)
+		for _, mf := range mfs {
+			for _, m := range mf.GetMetric() {
+				switch mf.GetName() {
+				case "pint_prometheus_disk_cache_hits_total":
+					rr.diskHits += m.GetCounter().GetValue()
+				case "pint_prometheus_disk_cache_miss_total":
+					rr.diskMisses += m.GetCounter().GetValue()
+				}
+			}
+		}
+		return rr
+	}
+
+	rr := run()
+	require.Equal(t, int64(1), requests.Load())
+	require.Len(t, rr.series, 1)
+	require.Equal(t, 0.0, rr.diskHits)
+	require.Equal(t, 1.0, rr.diskMisses)
+
+	// A new group has an empty memory cache but should read results stored on disk.
+	rr = run()
+	require.Equal(t, int64(1), requests.Load())
+	require.Len(t, rr.series, 1)
+	require.Equal(t, 1.0, rr.diskHits)
+	require.Equal(t, 0.0, rr.diskMisses)
+
+	dc.Close()
+	rr = run()
+	require.Equal(t, int64(2), requests.Load())
+	require.Equal(t, 0.0, rr.diskHits)
+	require.Equal(t, 0.0, rr.diskMisses)
+}
@@ -23,6 +23,20 @@ func IsUnavailableError(err error) bool {
 	return true
 }
 
+// IsUnsupportedError returns true if the server doesn't support requested
+// API endpoint, which is the case with some Prometheus compatible services,
+// like Thanos, that don't implement all Prometheus APIs.
+func IsUnsupportedError(err error) bool {
+	var se statusError
+	if ok := errors.As(err, &se); ok {
+		switch se.code {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return true
+		}
+	}
+	return false
+}
+
 func IsQueryTooExpensive(err error) bool {
 	var e1 APIError
 	if ok := errors.As(err, &e1); ok {
@@ -103,6 +117,14 @@ func decodeError(err error) string {
 }
 
 func tryDecodingAPIError(resp *http.Response) error {
+	return statusError{
+		err:        decodeAPIError(resp),
+		code:       resp.StatusCode,
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+func decodeAPIError(resp *http.Response) error {
 	slog.Debug("Trying to parse Prometheus error response", slog.Int("code", resp.StatusCode))
 
 	var status, errType, errText string
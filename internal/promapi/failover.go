@@ -3,7 +3,10 @@ package promapi
 import (
 	"context"
 	"log/slog"
+	"maps"
+	"reflect"
 	"regexp"
+	"slices"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -33,6 +36,7 @@ func (e *FailoverGroupError) IsStrict() bool {
 
 func cacheCleaner(cache *queryCache, interval time.Duration, quit chan bool) {
 	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-quit:
@@ -50,6 +54,7 @@ type FailoverGroup struct {
 	uptimeMetric   string
 	cacheCollector *cacheCollector
 	quitChan       chan bool
+	config         any
 
 	pathsInclude []*regexp.Regexp
 	pathsExclude []*regexp.Regexp
@@ -127,6 +132,43 @@ func (fg *FailoverGroup) MergeUpstreams(src *FailoverGroup) {
 	}
 }
 
+// SetConfig stores the configuration this group was created from, so IsEqual
+// can also detect changes to settings that can't be compared once applied,
+// like TLS or authentication.
+func (fg *FailoverGroup) SetConfig(cfg any) {
+	fg.config = cfg
+}
+
+// IsEqual returns true if both groups have the same configuration and
+// the same list of upstream servers, in the same order.
+func (fg *FailoverGroup) IsEqual(other *FailoverGroup) bool {
+	if fg.name != other.name ||
+		!reflect.DeepEqual(fg.config, other.config) ||
+		fg.publicURI != other.publicURI ||
+		fg.uptimeMetric != other.uptimeMetric ||
+		fg.strictErrors != other.strictErrors ||
+		!slices.Equal(fg.Include(), other.Include()) ||
+		!slices.Equal(fg.Exclude(), other.Exclude()) ||
+		!slices.Equal(fg.tags, other.tags) ||
+		len(fg.servers) != len(other.servers) {
+		return false
+	}
+	for i, prom := range fg.servers {
+		op := other.servers[i]
+		if prom.unsafeURI != op.unsafeURI ||
+			prom.publicURI != op.publicURI ||
+			prom.timeout != op.timeout ||
+			prom.concurrency != op.concurrency ||
+			prom.rateLimit != op.rateLimit ||
+			prom.proxy != op.proxy ||
+			!reflect.DeepEqual(prom.retry, op.retry) ||
+			!maps.Equal(prom.headers, op.headers) {
+			return false
+		}
+	}
+	return true
+}
+
 func (fg *FailoverGroup) IsEnabledForPath(path string) bool {
 	if len(fg.pathsInclude) == 0 && len(fg.pathsExclude) == 0 {
 		return true
@@ -150,6 +192,14 @@ func (fg *FailoverGroup) StartWorkers(reg *prometheus.Registry) {
 	}
 
 	queryCache := newQueryCache(time.Hour)
+	// Fixture servers are cheap to query and their data can change
+	// between runs, so there's no point in storing their results on disk.
+	queryCache.disk = currentDiskCache()
+	for _, prom := range fg.servers {
+		if prom.fixture != nil {
+			queryCache.disk = nil
+		}
+	}
 	fg.quitChan = make(chan bool)
 	go cacheCleaner(queryCache, time.Minute*2, fg.quitChan)
 
@@ -171,6 +221,7 @@ func (fg *FailoverGroup) Close(reg *prometheus.Registry) {
 	}
 	reg.Unregister(fg.cacheCollector)
 	fg.quitChan <- true
+	fg.started = false
 }
 
 func (fg *FailoverGroup) CleanCache() {
@@ -256,3 +307,78 @@ func (fg *FailoverGroup) Flags(ctx context.Context) (flags *FlagsResult, err err
 	}
 	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
 }
+
+func (fg *FailoverGroup) Rules(ctx context.Context) (rules *RulesResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.safeURI
+		rules, err = prom.Rules(ctx)
+		if err == nil {
+			return rules, nil
+		}
+		if !IsUnavailableError(err) {
+			return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) TSDBStatus(ctx context.Context, limit int) (ts *TSDBStatusResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.safeURI
+		ts, err = prom.TSDBStatus(ctx, limit)
+		if err == nil {
+			return ts, nil
+		}
+		if !IsUnavailableError(err) {
+			return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) Series(ctx context.Context, matches []string, start, end time.Time, limit int) (sr *SeriesResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.safeURI
+		sr, err = prom.Series(ctx, matches, start, end, limit)
+		if err == nil {
+			return sr, nil
+		}
+		if !IsUnavailableError(err) {
+			return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) LabelNames(ctx context.Context, matches []string, start, end time.Time, limit int) (lr *LabelNamesResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.safeURI
+		lr, err = prom.LabelNames(ctx, matches, start, end, limit)
+		if err == nil {
+			return lr, nil
+		}
+		if !IsUnavailableError(err) {
+			return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
+func (fg *FailoverGroup) LabelValues(ctx context.Context, name string, matches []string, start, end time.Time, limit int) (lr *LabelValuesResult, err error) {
+	var uri string
+	for _, prom := range fg.servers {
+		uri = prom.safeURI
+		lr, err = prom.LabelValues(ctx, name, matches, start, end, limit)
+		if err == nil {
+			return lr, nil
+		}
+		if !IsUnavailableError(err) {
+			return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
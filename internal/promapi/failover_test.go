@@ -0,0 +1,101 @@
+package promapi_test
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestFailoverGroupIsEqual(t *testing.T) {
+	newGroup := func(name string, uris []string, headers map[string]string, tags []string) *promapi.FailoverGroup {
+		servers := make([]*promapi.Prometheus, 0, len(uris))
+		for _, uri := range uris {
+			servers = append(servers, promapi.NewPrometheus(name, uri, "", headers, time.Minute, 1, 100, nil, nil, nil, promapi.RetryPolicy{}))
+		}
+		return promapi.NewFailoverGroup(name, uris[0], servers, true, "up", []*regexp.Regexp{regexp.MustCompile("^foo$")}, nil, tags)
+	}
+
+	a := newGroup("a", []string{"http://a1", "http://a2"}, map[string]string{"X-Foo": "bar"}, []string{"foo"})
+	require.True(t, a.IsEqual(newGroup("a", []string{"http://a1", "http://a2"}, map[string]string{"X-Foo": "bar"}, []string{"foo"})))
+	require.False(t, a.IsEqual(newGroup("b", []string{"http://a1", "http://a2"}, map[string]string{"X-Foo": "bar"}, []string{"foo"})))
+	require.False(t, a.IsEqual(newGroup("a", []string{"http://a2", "http://a1"}, map[string]string{"X-Foo": "bar"}, []string{"foo"})))
+	require.False(t, a.IsEqual(newGroup("a", []string{"http://a1"}, map[string]string{"X-Foo": "bar"}, []string{"foo"})))
+	require.False(t, a.IsEqual(newGroup("a", []string{"http://a1", "http://a2"}, map[string]string{"X-Foo": "foo"}, []string{"foo"})))
+	require.False(t, a.IsEqual(newGroup("a", []string{"http://a1", "http://a2"}, map[string]string{"X-Foo": "bar"}, []string{"bar"})))
+}
+
+func TestFailoverGroupIsEqualSettings(t *testing.T) {
+	type settings struct {
+		timeout     time.Duration
+		concurrency int
+		rateLimit   int
+		proxy       *url.URL
+		retry       promapi.RetryPolicy
+		config      any
+	}
+	type groupConfig struct {
+		TLS      map[string]string
+		Password string
+	}
+	newGroup := func(s settings) *promapi.FailoverGroup {
+		fg := promapi.NewFailoverGroup("a", "http://a", []*promapi.Prometheus{
+			promapi.NewPrometheus("a", "http://a", "", nil, s.timeout, s.concurrency, s.rateLimit, nil, s.proxy, nil, s.retry),
+		}, true, "up", nil, nil, nil)
+		fg.SetConfig(s.config)
+		return fg
+	}
+	base := func() settings {
+		return settings{
+			timeout:     time.Minute,
+			concurrency: 1,
+			rateLimit:   100,
+			proxy:       &url.URL{Scheme: "http", Host: "proxy1"},
+			retry:       promapi.RetryPolicy{RetryOn: []int{503}, MaxAttempts: 3},
+			config:      groupConfig{TLS: map[string]string{"serverName": "a"}, Password: "foo"},
+		}
+	}
+
+	a := newGroup(base())
+	require.True(t, a.IsEqual(newGroup(base())))
+
+	for name, modify := range map[string]func(s *settings){
+		"timeout":     func(s *settings) { s.timeout = time.Second },
+		"concurrency": func(s *settings) { s.concurrency = 2 },
+		"rateLimit":   func(s *settings) { s.rateLimit = 10 },
+		"proxy":       func(s *settings) { s.proxy = &url.URL{Scheme: "http", Host: "proxy2"} },
+		"noProxy":     func(s *settings) { s.proxy = nil },
+		"retryOn":     func(s *settings) { s.retry.RetryOn = []int{502} },
+		"maxAttempts": func(s *settings) { s.retry.MaxAttempts = 5 },
+		"tls":         func(s *settings) { s.config = groupConfig{TLS: map[string]string{"serverName": "b"}, Password: "foo"} },
+		"auth":        func(s *settings) { s.config = groupConfig{TLS: map[string]string{"serverName": "a"}, Password: "bar"} },
+		"noConfig":    func(s *settings) { s.config = nil },
+	} {
+		s := base()
+		modify(&s)
+		require.False(t, a.IsEqual(newGroup(s)), name)
+	}
+}
+
+func TestFailoverGroupRestart(t *testing.T) {
+	fg := promapi.NewFailoverGroup("a", "http://a", []*promapi.Prometheus{
+		promapi.NewPrometheus("a", "http://a", "", nil, time.Minute, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+
+	// Workers can be restarted and the cache collector must be registered only once.
+	fg.StartWorkers(reg)
+	fg.Close(reg)
+	fg.StartWorkers(reg)
+	fg.Close(reg)
+	fg.Close(reg)
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+	require.Empty(t, mfs)
+}
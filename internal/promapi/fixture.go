@@ -0,0 +1,800 @@
+package promapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/util/stats"
+	"go.uber.org/ratelimit"
+)
+
+const (
+	FixtureURIPrefix = "fixture://"
+
+	defaultFixtureConfig = `global:
+  scrape_interval: 1m
+  scrape_timeout: 10s
+  evaluation_interval: 1m
+`
+	fixtureMaxSamples = 50000000
+)
+
+// FixtureOptions describes all the data files used to build a fixture
+// backed Prometheus server.
+type FixtureOptions struct {
+	// Files with metrics in the Prometheus text or OpenMetrics exposition format.
+	// Samples without a timestamp are repeated over the whole Range.
+	Exposition []string
+	// Files with series written using the promtool notation,
+	// one series per line, for example: foo{job="bar"} 1+1x10
+	// The last value of every series is placed at the load time
+	// and all previous values are Step apart.
+	Series []string
+	// Directory with TSDB blocks, as created by promtool tsdb create-blocks-from.
+	Blocks string
+	// JSON file with metadata in the format returned by /api/v1/metadata.
+	// If empty then metadata is taken from exposition files.
+	Metadata string
+	// JSON file with a map of flags returned by /api/v1/status/flags.
+	Flags string
+	// Prometheus configuration file returned by /api/v1/status/config.
+	Config string
+	Range  time.Duration
+	Step   time.Duration
+}
+
+type fixtureSample struct {
+	lset labels.Labels
+	ts   int64
+	val  float64
+}
+
+type fixtureServer struct {
+	now        time.Time
+	queryable  storage.Queryable
+	engine     *promql.Engine
+	db         *tsdb.DB
+	blocks     *tsdb.DBReadOnly
+	metadata   map[string][]v1.Metadata
+	flags      map[string]string
+	config     string
+	dir        string
+	closeOnce  sync.Once
+	timeout    time.Duration
+	handler    http.Handler
+	closeError error
+}
+
+func newFixtureServer(opts FixtureOptions, timeout time.Duration) (_ *fixtureServer, err error) {
+	if opts.Range <= 0 {
+		opts.Range = time.Hour
+	}
+	if opts.Step <= 0 {
+		opts.Step = time.Minute
+	}
+
+	fs := &fixtureServer{
+		now:      time.Now().Truncate(time.Second),
+		metadata: map[string][]v1.Metadata{},
+		flags:    map[string]string{},
+		config:   defaultFixtureConfig,
+		timeout:  timeout,
+	}
+	defer func() {
+		if err != nil {
+			_ = fs.Close()
+		}
+	}()
+
+	var samples []fixtureSample
+	for _, path := range opts.Exposition {
+		s, err := fs.loadExposition(path, opts.Range, opts.Step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixture exposition file %q: %w", path, err)
+		}
+		samples = append(samples, s...)
+	}
+	for _, path := range opts.Series {
+		s, err := fs.loadSeries(path, opts.Step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixture series file %q: %w", path, err)
+		}
+		samples = append(samples, s...)
+	}
+
+	if opts.Metadata != "" {
+		if err = readJSONFile(opts.Metadata, &fs.metadata); err != nil {
+			return nil, fmt.Errorf("failed to load fixture metadata file %q: %w", opts.Metadata, err)
+		}
+	}
+	if opts.Flags != "" {
+		if err = readJSONFile(opts.Flags, &fs.flags); err != nil {
+			return nil, fmt.Errorf("failed to load fixture flags file %q: %w", opts.Flags, err)
+		}
+	}
+	if opts.Config != "" {
+		cfg, err := os.ReadFile(opts.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixture config file %q: %w", opts.Config, err)
+		}
+		fs.config = string(cfg)
+	}
+
+	if err = fs.openStorage(samples, opts); err != nil {
+		return nil, err
+	}
+
+	fs.engine = promql.NewEngine(promql.EngineOpts{
+		Logger:               log.NewNopLogger(),
+		MaxSamples:           fixtureMaxSamples,
+		Timeout:              timeout,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query", fs.handleQuery)
+	mux.HandleFunc("/api/v1/query_range", fs.handleQueryRange)
+	mux.HandleFunc("/api/v1/metadata", fs.handleMetadata)
+	mux.HandleFunc("/api/v1/series", fs.handleSeries)
+	mux.HandleFunc("/api/v1/labels", fs.handleLabelNames)
+	mux.HandleFunc("/api/v1/label/", fs.handleLabelValues)
+	mux.HandleFunc("/api/v1/status/flags", fs.handleFlags)
+	mux.HandleFunc("/api/v1/status/config", fs.handleConfig)
+	mux.HandleFunc("/api/v1/status/tsdb", fs.handleTSDBStatus)
+	mux.HandleFunc("/api/v1/rules", fs.handleRules)
+	fs.handler = mux
+
+	return fs, nil
+}
+
+func (fs *fixtureServer) openStorage(samples []fixtureSample, opts FixtureOptions) (err error) {
+	if fs.dir, err = os.MkdirTemp("", "pint-fixture-"); err != nil {
+		return fmt.Errorf("failed to create fixture storage directory: %w", err)
+	}
+
+	tsdbOpts := tsdb.DefaultOptions()
+	tsdbOpts.RetentionDuration = 0
+	// Fixture samples can have any timestamp, so allow appending them in any order.
+	tsdbOpts.OutOfOrderTimeWindow = math.MaxInt64 / 2
+	if fs.db, err = tsdb.Open(fs.dir, log.NewNopLogger(), nil, tsdbOpts, nil); err != nil {
+		return fmt.Errorf("failed to open fixture storage: %w", err)
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].ts < samples[j].ts
+	})
+	app := fs.db.Appender(context.Background())
+	for _, s := range samples {
+		if _, err = app.Append(0, s.lset, s.ts, s.val); err != nil {
+			_ = app.Rollback()
+			return fmt.Errorf("failed to append fixture sample %s: %w", s.lset, err)
+		}
+	}
+	if err = app.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fixture samples: %w", err)
+	}
+
+	if opts.Blocks == "" {
+		fs.queryable = fs.db
+		return nil
+	}
+
+	if _, err = os.Stat(opts.Blocks); err != nil {
+		return fmt.Errorf("failed to open fixture blocks directory: %w", err)
+	}
+	if fs.blocks, err = tsdb.OpenDBReadOnly(opts.Blocks, log.NewNopLogger()); err != nil {
+		return fmt.Errorf("failed to open fixture blocks directory %q: %w", opts.Blocks, err)
+	}
+	fs.queryable = storage.QueryableFunc(func(mint, maxt int64) (storage.Querier, error) {
+		hq, err := fs.db.Querier(mint, maxt)
+		if err != nil {
+			return nil, err
+		}
+		bq, err := fs.blocks.Querier(mint, maxt)
+		if err != nil {
+			_ = hq.Close()
+			return nil, err
+		}
+		return storage.NewMergeQuerier([]storage.Querier{hq, bq}, nil, storage.ChainedSeriesMerge), nil
+	})
+	return nil
+}
+
+func (fs *fixtureServer) loadExposition(path string, loadRange, step time.Duration) (samples []fixtureSample, err error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentType string
+	if strings.Contains(string(body), "# EOF") {
+		contentType = "application/openmetrics-text"
+	}
+	p, err := textparse.New(body, contentType, false)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := map[string]*v1.Metadata{}
+	getMeta := func(name []byte) *v1.Metadata {
+		m, ok := meta[string(name)]
+		if !ok {
+			m = &v1.Metadata{Type: v1.MetricTypeUnknown}
+			meta[string(name)] = m
+		}
+		return m
+	}
+
+	end := fs.now.UnixMilli()
+	start := fs.now.Add(loadRange * -1).UnixMilli()
+	for {
+		entry, err := p.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// nolint: exhaustive
+		switch entry {
+		case textparse.EntryType:
+			name, typ := p.Type()
+			getMeta(name).Type = v1.MetricType(typ)
+		case textparse.EntryHelp:
+			name, help := p.Help()
+			getMeta(name).Help = string(help)
+		case textparse.EntryUnit:
+			name, unit := p.Unit()
+			getMeta(name).Unit = string(unit)
+		case textparse.EntrySeries:
+			var lset labels.Labels
+			_, ts, val := p.Series()
+			p.Metric(&lset)
+			if ts != nil {
+				samples = append(samples, fixtureSample{lset: lset, ts: *ts, val: val})
+				continue
+			}
+			for t := start; t <= end; t += step.Milliseconds() {
+				samples = append(samples, fixtureSample{lset: lset, ts: t, val: val})
+			}
+		}
+	}
+
+	for name, m := range meta {
+		fs.metadata[name] = append(fs.metadata[name], *m)
+	}
+
+	return samples, nil
+}
+
+func (fs *fixtureServer) loadSeries(path string, step time.Duration) (samples []fixtureSample, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lineno int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lset, values, err := parser.ParseSeriesDesc(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineno, err)
+		}
+		for i, v := range values {
+			if v.Omitted {
+				continue
+			}
+			ts := fs.now.Add(step * time.Duration(i-len(values)+1)).UnixMilli()
+			samples = append(samples, fixtureSample{lset: lset, ts: ts, val: v.Value})
+		}
+	}
+
+	return samples, scanner.Err()
+}
+
+func readJSONFile(path string, dst any) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, dst)
+}
+
+func (fs *fixtureServer) Close() error {
+	fs.closeOnce.Do(func() {
+		if fs.blocks != nil {
+			fs.closeError = errors.Join(fs.closeError, fs.blocks.Close())
+		}
+		if fs.db != nil {
+			fs.closeError = errors.Join(fs.closeError, fs.db.Close())
+		}
+		if fs.dir != "" {
+			fs.closeError = errors.Join(fs.closeError, os.RemoveAll(fs.dir))
+		}
+	})
+	return fs.closeError
+}
+
+// RoundTrip passes all requests directly to the fixture HTTP handler.
+func (fs *fixtureServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	rw := newFixtureResponseWriter()
+	fs.handler.ServeHTTP(rw, req)
+	return rw.response(req), nil
+}
+
+// fixtureResponseWriter buffers the response written by the fixture handler,
+// so it can be returned from RoundTrip.
+type fixtureResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+	code   int
+}
+
+func newFixtureResponseWriter() *fixtureResponseWriter {
+	return &fixtureResponseWriter{header: http.Header{}}
+}
+
+func (rw *fixtureResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *fixtureResponseWriter) Write(b []byte) (int, error) {
+	if rw.code == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.body.Write(b)
+}
+
+func (rw *fixtureResponseWriter) WriteHeader(code int) {
+	if rw.code == 0 {
+		rw.code = code
+	}
+}
+
+func (rw *fixtureResponseWriter) response(req *http.Request) *http.Response {
+	if rw.code == 0 {
+		rw.code = http.StatusOK
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rw.code, http.StatusText(rw.code)),
+		StatusCode:    rw.code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rw.header,
+		Body:          io.NopCloser(&rw.body),
+		ContentLength: int64(rw.body.Len()),
+		Request:       req,
+	}
+}
+
+type fixtureResponse struct {
+	Data      any    `json:"data,omitempty"`
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type fixtureQueryData struct {
+	Result     any              `json:"result"`
+	Stats      stats.QueryStats `json:"stats,omitempty"`
+	ResultType parser.ValueType `json:"resultType"`
+}
+
+func (fs *fixtureServer) handleQuery(w http.ResponseWriter, r *http.Request) {
+	ts := fs.now
+	if v := r.FormValue("time"); v != "" {
+		var err error
+		if ts, err = parseFixtureTime(v); err != nil {
+			writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"time\": %s", err))
+			return
+		}
+	}
+
+	qry, err := fs.engine.NewInstantQuery(r.Context(), fs.queryable, nil, r.FormValue("query"), ts)
+	if err != nil {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"query\": %s", err))
+		return
+	}
+	fs.runQuery(w, r, qry)
+}
+
+func (fs *fixtureServer) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	start, err := parseFixtureTime(r.FormValue("start"))
+	if err != nil {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"start\": %s", err))
+		return
+	}
+	end, err := parseFixtureTime(r.FormValue("end"))
+	if err != nil {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"end\": %s", err))
+		return
+	}
+	if end.Before(start) {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, "end timestamp must not be before start time")
+		return
+	}
+	step, err := parseFixtureDuration(r.FormValue("step"))
+	if err != nil {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"step\": %s", err))
+		return
+	}
+	if step <= 0 {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, "zero or negative query resolution step widths are not accepted. Try a positive integer")
+		return
+	}
+	if end.Sub(start)/step > 11000 {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, "exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)")
+		return
+	}
+
+	qry, err := fs.engine.NewRangeQuery(r.Context(), fs.queryable, nil, r.FormValue("query"), start, end, step)
+	if err != nil {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"query\": %s", err))
+		return
+	}
+	fs.runQuery(w, r, qry)
+}
+
+func (fs *fixtureServer) runQuery(w http.ResponseWriter, r *http.Request, qry promql.Query) {
+	defer qry.Close()
+
+	res := qry.Exec(r.Context())
+	if res.Err != nil {
+		var errTimeout promql.ErrQueryTimeout
+		var errCanceled promql.ErrQueryCanceled
+		switch {
+		case errors.As(res.Err, &errTimeout):
+			writeFixtureError(w, http.StatusServiceUnavailable, v1.ErrTimeout, res.Err.Error())
+		case errors.As(res.Err, &errCanceled):
+			writeFixtureError(w, http.StatusServiceUnavailable, v1.ErrCanceled, res.Err.Error())
+		default:
+			writeFixtureError(w, http.StatusUnprocessableEntity, v1.ErrExec, res.Err.Error())
+		}
+		return
+	}
+
+	data := fixtureQueryData{
+		ResultType: res.Value.Type(),
+		Result:     res.Value,
+	}
+	if r.FormValue("stats") != "" {
+		data.Stats = stats.NewQueryStats(qry.Stats())
+	}
+	writeFixtureData(w, data)
+}
+
+// parseSeriesArgs decodes match[], start, end and limit parameters
+// used by all series and labels APIs.
+func (fs *fixtureServer) parseSeriesArgs(w http.ResponseWriter, r *http.Request) (matchers [][]*labels.Matcher, start, end time.Time, limit int, ok bool) {
+	start, end = time.Unix(0, 0), fs.now
+	var err error
+	if v := r.FormValue("start"); v != "" {
+		if start, err = parseFixtureTime(v); err != nil {
+			writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"start\": %s", err))
+			return nil, start, end, 0, false
+		}
+	}
+	if v := r.FormValue("end"); v != "" {
+		if end, err = parseFixtureTime(v); err != nil {
+			writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"end\": %s", err))
+			return nil, start, end, 0, false
+		}
+	}
+	if v := r.FormValue("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"limit\": %q", v))
+			return nil, start, end, 0, false
+		}
+	}
+	for _, s := range r.Form["match[]"] {
+		m, err := parser.ParseMetricSelector(s)
+		if err != nil {
+			writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, err.Error())
+			return nil, start, end, 0, false
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, start, end, limit, true
+}
+
+func (fs *fixtureServer) handleSeries(w http.ResponseWriter, r *http.Request) {
+	matchers, start, end, limit, ok := fs.parseSeriesArgs(w, r)
+	if !ok {
+		return
+	}
+	if len(matchers) == 0 {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, "no match[] parameter provided")
+		return
+	}
+
+	q, err := fs.queryable.Querier(start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		writeFixtureError(w, http.StatusInternalServerError, v1.ErrServer, err.Error())
+		return
+	}
+	defer q.Close()
+
+	hints := &storage.SelectHints{Start: start.UnixMilli(), End: end.UnixMilli(), Func: "series"}
+	var sets []storage.SeriesSet
+	for _, m := range matchers {
+		sets = append(sets, q.Select(r.Context(), true, hints, m...))
+	}
+	set := storage.NewMergeSeriesSet(sets, storage.ChainedSeriesMerge)
+
+	series := []labels.Labels{}
+	for set.Next() {
+		if limit > 0 && len(series) >= limit {
+			break
+		}
+		series = append(series, set.At().Labels())
+	}
+	if err = set.Err(); err != nil {
+		writeFixtureError(w, http.StatusUnprocessableEntity, v1.ErrExec, err.Error())
+		return
+	}
+	writeFixtureData(w, series)
+}
+
+func (fs *fixtureServer) handleLabelNames(w http.ResponseWriter, r *http.Request) {
+	matchers, start, end, limit, ok := fs.parseSeriesArgs(w, r)
+	if !ok {
+		return
+	}
+	fs.writeLabels(w, r, start, end, limit, matchers, func(q storage.Querier, m ...*labels.Matcher) ([]string, error) {
+		names, _, err := q.LabelNames(r.Context(), m...)
+		return names, err
+	})
+}
+
+func (fs *fixtureServer) handleLabelValues(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/label/"), "/values")
+	if !ok || !model.LabelName(name).IsValid() {
+		writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid label name: %q", name))
+		return
+	}
+	matchers, start, end, limit, ok := fs.parseSeriesArgs(w, r)
+	if !ok {
+		return
+	}
+	fs.writeLabels(w, r, start, end, limit, matchers, func(q storage.Querier, m ...*labels.Matcher) ([]string, error) {
+		values, _, err := q.LabelValues(r.Context(), name, m...)
+		return values, err
+	})
+}
+
+func (fs *fixtureServer) writeLabels(
+	w http.ResponseWriter, _ *http.Request,
+	start, end time.Time, limit int,
+	matchers [][]*labels.Matcher,
+	fn func(storage.Querier, ...*labels.Matcher) ([]string, error),
+) {
+	q, err := fs.queryable.Querier(start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		writeFixtureError(w, http.StatusInternalServerError, v1.ErrServer, err.Error())
+		return
+	}
+	defer q.Close()
+
+	if len(matchers) == 0 {
+		matchers = append(matchers, nil)
+	}
+	uniq := map[string]struct{}{}
+	for _, m := range matchers {
+		vals, err := fn(q, m...)
+		if err != nil {
+			writeFixtureError(w, http.StatusUnprocessableEntity, v1.ErrExec, err.Error())
+			return
+		}
+		for _, v := range vals {
+			uniq[v] = struct{}{}
+		}
+	}
+
+	values := make([]string, 0, len(uniq))
+	for v := range uniq {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	writeFixtureData(w, values)
+}
+
+func (fs *fixtureServer) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	metric := r.FormValue("metric")
+	if metric == "" {
+		writeFixtureData(w, fs.metadata)
+		return
+	}
+
+	data := map[string][]v1.Metadata{}
+	if m, ok := fs.metadata[metric]; ok {
+		data[metric] = m
+	}
+	writeFixtureData(w, data)
+}
+
+func (fs *fixtureServer) handleFlags(w http.ResponseWriter, _ *http.Request) {
+	writeFixtureData(w, fs.flags)
+}
+
+func (fs *fixtureServer) handleConfig(w http.ResponseWriter, _ *http.Request) {
+	writeFixtureData(w, map[string]string{"yaml": fs.config})
+}
+
+// handleRules always returns an empty list, fixture servers don't evaluate any rules.
+func (fs *fixtureServer) handleRules(w http.ResponseWriter, _ *http.Request) {
+	writeFixtureData(w, map[string][]RuleGroup{"groups": {}})
+}
+
+// handleTSDBStatus calculates cardinality statistics from all
+// loaded series, there's no head block to get them from.
+func (fs *fixtureServer) handleTSDBStatus(w http.ResponseWriter, r *http.Request) {
+	limit := 10
+	if v := r.FormValue("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			writeFixtureError(w, http.StatusBadRequest, v1.ErrBadData, fmt.Sprintf("invalid parameter \"limit\": %q", v))
+			return
+		}
+	}
+
+	q, err := fs.queryable.Querier(0, fs.now.UnixMilli())
+	if err != nil {
+		writeFixtureError(w, http.StatusInternalServerError, v1.ErrServer, err.Error())
+		return
+	}
+	defer q.Close()
+
+	var status v1.TSDBResult
+	seriesByName := map[string]uint64{}
+	seriesByPair := map[string]uint64{}
+	valuesByName := map[string]map[string]struct{}{}
+	set := q.Select(r.Context(), false, nil, labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+"))
+	for set.Next() {
+		status.HeadStats.NumSeries++
+		set.At().Labels().Range(func(l labels.Label) {
+			if l.Name == labels.MetricName {
+				seriesByName[l.Value]++
+			}
+			seriesByPair[l.Name+"="+l.Value]++
+			if _, ok := valuesByName[l.Name]; !ok {
+				valuesByName[l.Name] = map[string]struct{}{}
+			}
+			valuesByName[l.Name][l.Value] = struct{}{}
+		})
+	}
+	if err = set.Err(); err != nil {
+		writeFixtureError(w, http.StatusUnprocessableEntity, v1.ErrExec, err.Error())
+		return
+	}
+
+	valueCounts := make(map[string]uint64, len(valuesByName))
+	for name, values := range valuesByName {
+		valueCounts[name] = uint64(len(values))
+	}
+	status.HeadStats.NumLabelPairs = len(seriesByPair)
+	status.SeriesCountByMetricName = topFixtureStats(seriesByName, limit)
+	status.LabelValueCountByLabelName = topFixtureStats(valueCounts, limit)
+	status.SeriesCountByLabelValuePair = topFixtureStats(seriesByPair, limit)
+	status.MemoryInBytesByLabelName = []v1.Stat{}
+	writeFixtureData(w, status)
+}
+
+func topFixtureStats(m map[string]uint64, limit int) []v1.Stat {
+	stats := make([]v1.Stat, 0, len(m))
+	for name, value := range m {
+		stats = append(stats, v1.Stat{Name: name, Value: value})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Value != stats[j].Value {
+			return stats[i].Value > stats[j].Value
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
+
+func writeFixtureData(w http.ResponseWriter, data any) {
+	writeFixtureResponse(w, http.StatusOK, fixtureResponse{Status: "success", Data: data})
+}
+
+func writeFixtureError(w http.ResponseWriter, code int, errType v1.ErrorType, msg string) {
+	writeFixtureResponse(w, code, fixtureResponse{Status: "error", ErrorType: string(errType), Error: msg})
+}
+
+func writeFixtureResponse(w http.ResponseWriter, code int, resp fixtureResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode fixture response", slog.Any("err", err))
+	}
+}
+
+func parseFixtureTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, ns := math.Modf(t)
+		return time.Unix(int64(sec), int64(math.Round(ns*1000)*1000000)).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+func parseFixtureDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(d * float64(time.Second)), nil
+	}
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
+
+// NewFixturePrometheus returns a Prometheus server that doesn't make any
+// network requests but instead evaluates all queries locally, using the
+// PromQL engine and data loaded from fixture files.
+func NewFixturePrometheus(name string, opts FixtureOptions, timeout time.Duration, concurrency int) (*Prometheus, error) {
+	fs, err := newFixtureServer(opts, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := FixtureURIPrefix + name
+	prom := Prometheus{
+		name:        name,
+		unsafeURI:   uri,
+		publicURI:   uri,
+		safeURI:     uri,
+		timeout:     timeout,
+		client:      http.Client{Transport: fs},
+		locker:      newPartitionLocker((&sync.Mutex{})),
+		rateLimiter: ratelimit.NewUnlimited(),
+		concurrency: concurrency,
+		fixture:     fs,
+	}
+
+	return &prom, nil
+}
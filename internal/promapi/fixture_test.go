@@ -0,0 +1,199 @@
+package promapi_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func writeFixtureFile(t *testing.T, dir, name, content string) string {
+	p := path.Join(dir, name)
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	return p
+}
+
+func newFixtureGroup(t *testing.T, opts promapi.FixtureOptions) *promapi.FailoverGroup {
+	prom, err := promapi.NewFixturePrometheus("fixture", opts, time.Second*5, 4)
+	require.NoError(t, err)
+	fg := promapi.NewFailoverGroup("fixture", "", []*promapi.Prometheus{prom}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	t.Cleanup(func() { fg.Close(reg) })
+	return fg
+}
+
+func TestFixturePrometheus(t *testing.T) {
+	dir := t.TempDir()
+	opts := promapi.FixtureOptions{
+		Exposition: []string{writeFixtureFile(t, dir, "metrics.txt", `# HELP http_requests_total Total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{job="api",code="200"} 100
+http_requests_total{job="api",code="500"} 5
+# TYPE temperature gauge
+temperature{job="sensor"} 21.5
+`)},
+		Series: []string{writeFixtureFile(t, dir, "series.txt", `# comment
+up{job="api"} 1x10
+up{job="sensor"} 0 1 _ 1
+`)},
+		Flags:  writeFixtureFile(t, dir, "flags.json", `{"storage.tsdb.retention.time": "30d"}`),
+		Config: writeFixtureFile(t, dir, "prometheus.yml", "global:\n  scrape_interval: 15s\n"),
+		Range:  time.Hour,
+		Step:   time.Minute,
+	}
+	fg := newFixtureGroup(t, opts)
+	ctx := context.Background()
+
+	t.Run("query", func(t *testing.T) {
+		qr, err := fg.Query(ctx, `sum(http_requests_total) by (job)`)
+		require.NoError(t, err)
+		require.Equal(t, "fixture://fixture", qr.URI)
+		require.Len(t, qr.Series, 1)
+		require.Equal(t, `{job="api"}`, qr.Series[0].Labels.String())
+		require.InDelta(t, 105.0, qr.Series[0].Value, 0.0001)
+		require.Positive(t, qr.Stats.Samples.TotalQueryableSamples)
+	})
+
+	t.Run("query series", func(t *testing.T) {
+		qr, err := fg.Query(ctx, `up`)
+		require.NoError(t, err)
+		require.Len(t, qr.Series, 2)
+	})
+
+	t.Run("query missing", func(t *testing.T) {
+		qr, err := fg.Query(ctx, `missing_metric`)
+		require.NoError(t, err)
+		require.Empty(t, qr.Series)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		_, err := fg.Query(ctx, `sum(`)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "bad_data: invalid parameter \"query\"")
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		qr, err := fg.RangeQuery(ctx, `temperature`, promapi.NewRelativeRange(time.Hour*2, time.Minute))
+		require.NoError(t, err)
+		require.Len(t, qr.Series.Ranges, 1)
+		require.Equal(t, `{__name__="temperature", job="sensor"}`, qr.Series.Ranges[0].Labels.String())
+		require.True(t, qr.Series.Ranges[0].Start.After(qr.Series.From))
+	})
+
+	t.Run("metadata from exposition", func(t *testing.T) {
+		mr, err := fg.Metadata(ctx, "http_requests_total")
+		require.NoError(t, err)
+		require.Equal(t, []v1.Metadata{{Type: v1.MetricTypeCounter, Help: "Total number of HTTP requests."}}, mr.Metadata)
+
+		mr, err = fg.Metadata(ctx, "temperature")
+		require.NoError(t, err)
+		require.Equal(t, []v1.Metadata{{Type: v1.MetricTypeGauge}}, mr.Metadata)
+
+		mr, err = fg.Metadata(ctx, "up")
+		require.NoError(t, err)
+		require.Empty(t, mr.Metadata)
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		fr, err := fg.Flags(ctx)
+		require.NoError(t, err)
+		require.Equal(t, v1.FlagsResult{"storage.tsdb.retention.time": "30d"}, fr.Flags)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cr, err := fg.Config(ctx)
+		require.NoError(t, err)
+		require.Equal(t, time.Second*15, cr.Config.Global.ScrapeInterval)
+	})
+}
+
+func TestFixturePrometheusDefaults(t *testing.T) {
+	dir := t.TempDir()
+	fg := newFixtureGroup(t, promapi.FixtureOptions{
+		Exposition: []string{writeFixtureFile(t, dir, "metrics.txt", `# TYPE foo_seconds counter
+# HELP foo_seconds Foo counter.
+# UNIT foo_seconds seconds
+foo_seconds_total{job="a"} 1 1000
+# EOF
+`)},
+		Metadata: writeFixtureFile(t, dir, "metadata.json", `{"bar": [{"type": "gauge", "help": "Bar gauge.", "unit": ""}]}`),
+	})
+	ctx := context.Background()
+
+	mr, err := fg.Metadata(ctx, "bar")
+	require.NoError(t, err)
+	require.Equal(t, []v1.Metadata{{Type: v1.MetricTypeGauge, Help: "Bar gauge."}}, mr.Metadata)
+
+	mr, err = fg.Metadata(ctx, "foo_seconds")
+	require.NoError(t, err)
+	require.Equal(t, []v1.Metadata{{Type: v1.MetricTypeCounter, Help: "Foo counter.", Unit: "seconds"}}, mr.Metadata)
+
+	fr, err := fg.Flags(ctx)
+	require.NoError(t, err)
+	require.Empty(t, fr.Flags)
+
+	cr, err := fg.Config(ctx)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, cr.Config.Global.ScrapeInterval)
+	require.Equal(t, time.Minute, cr.Config.Global.EvaluationInterval)
+
+	// Samples with explicit timestamps are only visible at that time.
+	qr, err := fg.Query(ctx, `foo_seconds_total`)
+	require.NoError(t, err)
+	require.Empty(t, qr.Series)
+}
+
+func TestFixturePrometheusErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	type testCaseT struct {
+		opts promapi.FixtureOptions
+		err  string
+	}
+
+	testCases := []testCaseT{
+		{
+			opts: promapi.FixtureOptions{Exposition: []string{path.Join(dir, "missing.txt")}},
+			err:  `failed to load fixture exposition file "` + path.Join(dir, "missing.txt") + `": open ` + path.Join(dir, "missing.txt") + ": no such file or directory",
+		},
+		{
+			opts: promapi.FixtureOptions{Exposition: []string{writeFixtureFile(t, dir, "bad.txt", "foo{ 1\n")}},
+			err:  `failed to load fixture exposition file "` + path.Join(dir, "bad.txt") + `": expected label name, got "1" ("INVALID") while parsing: "foo{ 1"`,
+		},
+		{
+			opts: promapi.FixtureOptions{Series: []string{writeFixtureFile(t, dir, "bad.series", "foo 1\nbar{ 1x5\n")}},
+			err:  `failed to load fixture series file "` + path.Join(dir, "bad.series") + `": line 2: 1:6: parse error: unexpected character inside braces: '1'`,
+		},
+		{
+			opts: promapi.FixtureOptions{Metadata: writeFixtureFile(t, dir, "bad.json", "{")},
+			err:  `failed to load fixture metadata file "` + path.Join(dir, "bad.json") + `": unexpected end of JSON input`,
+		},
+		{
+			opts: promapi.FixtureOptions{Flags: writeFixtureFile(t, dir, "flags.json", "[]")},
+			err:  `failed to load fixture flags file "` + path.Join(dir, "flags.json") + `": json: cannot unmarshal array into Go value of type map[string]string`,
+		},
+		{
+			opts: promapi.FixtureOptions{Config: path.Join(dir, "prometheus.yml")},
+			err:  `failed to load fixture config file "` + path.Join(dir, "prometheus.yml") + `": open ` + path.Join(dir, "prometheus.yml") + ": no such file or directory",
+		},
+		{
+			opts: promapi.FixtureOptions{Blocks: path.Join(dir, "blocks")},
+			err:  "failed to open fixture blocks directory: stat " + path.Join(dir, "blocks") + ": no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.err, func(t *testing.T) {
+			_, err := promapi.NewFixturePrometheus("fixture", tc.opts, time.Second, 1)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -52,6 +52,10 @@ func (q flagsQuery) Run() queryResult {
 	return qr
 }
 
+func (q flagsQuery) Context() context.Context {
+	return q.ctx
+}
+
 func (q flagsQuery) Endpoint() string {
 	return "/api/v1/status/flags"
 }
@@ -96,7 +96,7 @@ func TestFlags(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(strings.TrimPrefix(tc.prefix, "/"), func(t *testing.T) {
 			fg := promapi.NewFailoverGroup("test", srv.URL+tc.prefix, []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL+tc.prefix, "", nil, tc.timeout, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL+tc.prefix, "", nil, tc.timeout, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
 			}, true, "up", nil, nil, nil)
 
 			reg := prometheus.NewRegistry()
@@ -58,6 +58,10 @@ func (q metadataQuery) Run() queryResult {
 	return qr
 }
 
+func (q metadataQuery) Context() context.Context {
+	return q.ctx
+}
+
 func (q metadataQuery) Endpoint() string {
 	return "/api/v1/metadata"
 }
@@ -121,7 +121,7 @@ func TestMetadata(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.metric, func(t *testing.T) {
 			fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL, "", nil, tc.timeout, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL, "", nil, tc.timeout, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
 			}, true, "up", nil, nil, nil)
 			reg := prometheus.NewRegistry()
 			fg.StartWorkers(reg)
@@ -30,12 +30,20 @@ var (
 		},
 		[]string{"name", "endpoint", "reason"},
 	)
+	prometheusQueryRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_query_retries_total",
+			Help: "Total number of retried prometheus queries",
+		},
+		[]string{"name", "endpoint", "code"},
+	)
 )
 
 func RegisterMetrics(reg *prometheus.Registry) {
 	reg.MustRegister(prometheusQueriesRunning)
 	reg.MustRegister(prometheusQueriesTotal)
 	reg.MustRegister(prometheusQueryErrorsTotal)
+	reg.MustRegister(prometheusQueryRetriesTotal)
 }
 
 func errReason(err error) string {
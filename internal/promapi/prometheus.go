@@ -38,6 +38,7 @@ func (qe QueryError) Unwrap() error {
 }
 
 type querier interface {
+	Context() context.Context
 	Endpoint() string
 	String() string
 	CacheKey() uint64
@@ -80,15 +81,45 @@ func sanitizeURI(s string) string {
 	if err != nil {
 		return s
 	}
+	var modified bool
 	if u.User != nil {
 		if _, pwdSet := u.User.Password(); pwdSet {
 			u.User = url.UserPassword(u.User.Username(), "xxx")
 		}
+		modified = true
+	}
+	if u.RawQuery != "" {
+		q := u.Query()
+		var redacted bool
+		for k := range q {
+			if isSecretParam(k) {
+				q.Set(k, "xxx")
+				redacted = true
+			}
+		}
+		if redacted {
+			u.RawQuery = q.Encode()
+			modified = true
+		}
+	}
+	if modified {
 		return u.String()
 	}
 	return s
 }
 
+var secretParams = []string{"token", "secret", "password", "passwd", "signature", "apikey", "api_key", "api-key", "credential"}
+
+func isSecretParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, sp := range secretParams {
+		if strings.Contains(name, sp) {
+			return true
+		}
+	}
+	return false
+}
+
 type Prometheus struct {
 	rateLimiter ratelimit.Limiter
 	headers     map[string]string
@@ -100,16 +131,30 @@ type Prometheus struct {
 	unsafeURI   string
 	safeURI     string
 	publicURI   string
+	fixture     *fixtureServer
+	proxy       string
+	retry       RetryPolicy
 	wg          sync.WaitGroup
 	timeout     time.Duration
 	concurrency int
+	rateLimit   int
 }
 
-func NewPrometheus(name, uri, publicURI string, headers map[string]string, timeout time.Duration, concurrency, rl int, tlsConf *tls.Config) *Prometheus {
+func NewPrometheus(name, uri, publicURI string, headers map[string]string, timeout time.Duration, concurrency, rl int, tlsConf *tls.Config, proxy *url.URL, auth Authenticator, retry RetryPolicy) *Prometheus {
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	if tlsConf != nil {
 		transport.TLSClientConfig = tlsConf
 	}
+	var proxyURI string
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+		proxyURI = proxy.String()
+	}
+
+	var rt http.RoundTripper = gzhttp.Transport(transport)
+	if auth != nil {
+		rt = auth.RoundTripper(rt)
+	}
 
 	uri = strings.TrimSuffix(uri, "/")
 	publicURI = strings.TrimSuffix(publicURI, "/")
@@ -124,10 +169,13 @@ func NewPrometheus(name, uri, publicURI string, headers map[string]string, timeo
 		safeURI:     sanitizeURI(uri),
 		headers:     headers,
 		timeout:     timeout,
-		client:      http.Client{Transport: gzhttp.Transport(transport)},
+		client:      http.Client{Transport: rt},
 		locker:      newPartitionLocker((&sync.Mutex{})),
 		rateLimiter: ratelimit.New(rl),
+		rateLimit:   rl,
 		concurrency: concurrency,
+		proxy:       proxyURI,
+		retry:       retry,
 	}
 
 	return &prom
@@ -141,6 +189,11 @@ func (prom *Prometheus) Close() {
 	slog.Debug("Stopping query workers", slog.String("name", prom.name), slog.String("uri", prom.safeURI))
 	close(prom.queries)
 	prom.wg.Wait()
+	if prom.fixture != nil {
+		if err := prom.fixture.Close(); err != nil {
+			slog.Error("Failed to close fixture storage", slog.String("name", prom.name), slog.Any("err", err))
+		}
+	}
 }
 
 func (prom *Prometheus) StartWorkers() {
@@ -202,23 +255,43 @@ func queryWorker(prom *Prometheus, queries chan queryRequest) {
 	}
 }
 
-func processJob(prom *Prometheus, job queryRequest) queryResult {
+func processJob(prom *Prometheus, job queryRequest) (result queryResult) {
+	span := startQuerySpan(prom, job.query)
+	cache := cacheDisabled
+	defer func() {
+		endQuerySpan(span, cache, result)
+	}()
+
 	cacheKey := job.query.CacheKey()
 	if prom.cache != nil {
 		if cached, ok := prom.cache.get(cacheKey, job.query.Endpoint()); ok {
+			cache = cacheMemory
 			return cached.(queryResult)
 		}
+		if cached, ok := prom.cache.load(job.query); ok {
+			cache = cacheDisk
+			return cached
+		}
+		cache = cacheMiss
 	}
 
 	prometheusQueriesTotal.WithLabelValues(prom.name, job.query.Endpoint()).Inc()
 	prometheusQueriesRunning.WithLabelValues(prom.name, job.query.Endpoint()).Inc()
 
-	prom.rateLimiter.Take()
-	result := job.query.Run()
+	cassette := currentCassette()
+	if cassette != nil && cassette.isReplay {
+		result = cassette.replay(prom, job.query)
+	} else {
+		result = runWithRetries(prom, job.query)
+		if cassette != nil {
+			cassette.record(prom, job.query, result)
+		}
+	}
 	prometheusQueriesRunning.WithLabelValues(prom.name, job.query.Endpoint()).Dec()
 
 	if result.err != nil {
-		if errors.Is(result.err, context.Canceled) {
+		// Don't report errors caused by the caller cancelling the query.
+		if errors.Is(result.err, context.Canceled) || job.query.Context().Err() != nil {
 			return result
 		}
 		prometheusQueryErrorsTotal.WithLabelValues(prom.name, job.query.Endpoint(), errReason(result.err)).Inc()
@@ -233,6 +306,7 @@ func processJob(prom *Prometheus, job queryRequest) queryResult {
 
 	if prom.cache != nil {
 		prom.cache.set(cacheKey, result, job.query.CacheTTL())
+		prom.cache.persist(job.query, result)
 	}
 
 	return result
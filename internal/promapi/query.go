@@ -63,6 +63,10 @@ func (q instantQuery) Run() queryResult {
 	return qr
 }
 
+func (q instantQuery) Context() context.Context {
+	return q.ctx
+}
+
 func (q instantQuery) Endpoint() string {
 	return "/api/v1/query"
 }
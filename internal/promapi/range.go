@@ -69,6 +69,10 @@ func (q rangeQuery) Run() queryResult {
 	return qr
 }
 
+func (q rangeQuery) Context() context.Context {
+	return q.ctx
+}
+
 func (q rangeQuery) Endpoint() string {
 	return "/api/v1/query_range"
 }
@@ -259,11 +263,11 @@ type RelativeRange struct {
 }
 
 func (rr RelativeRange) Start() time.Time {
-	return time.Now().Add(rr.lookback * -1)
+	return timeNow().Add(rr.lookback * -1)
 }
 
 func (rr RelativeRange) End() time.Time {
-	return time.Now()
+	return timeNow()
 }
 
 func (rr RelativeRange) Dur() time.Duration {
@@ -674,7 +674,7 @@ func TestRange(t *testing.T) {
 			defer srv.Close()
 
 			fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL, "", nil, tc.timeout, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL, "", nil, tc.timeout, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
 			}, true, "up", nil, nil, nil)
 			reg := prometheus.NewRegistry()
 			fg.StartWorkers(reg)
@@ -0,0 +1,128 @@
+package promapi
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// Zero value disables retries.
+type RetryPolicy struct {
+	RetryOn        []int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// backoff returns how long to wait before sending given retry attempt.
+// It uses exponential backoff with jitter, unless the server told us how long
+// to wait using Retry-After header.
+// Both values are capped by MaxBackoff.
+func (rp RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, rp.MaxBackoff)
+	}
+
+	delay := rp.InitialBackoff
+	for i := 1; i < attempt && delay < rp.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, rp.MaxBackoff)
+	if delay <= 0 {
+		return 0
+	}
+	// Use half of the delay as a fixed wait and randomise the other half,
+	// so we don't have all workers retrying at the same time.
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// shouldRetry returns true if a request that returned given error
+// can be retried.
+func (rp RetryPolicy) shouldRetry(attempt int, err error) (retryAfter time.Duration, code int, ok bool) {
+	if attempt >= rp.MaxAttempts {
+		return 0, 0, false
+	}
+	var se statusError
+	if !errors.As(err, &se) {
+		return 0, 0, false
+	}
+	if !slices.Contains(rp.RetryOn, se.code) {
+		return 0, 0, false
+	}
+	return se.retryAfter, se.code, true
+}
+
+// statusError is returned for all non-2xx responses.
+// It stores response code and the value of Retry-After header
+// so we can decide if the request should be retried.
+type statusError struct {
+	err        error
+	code       int
+	retryAfter time.Duration
+}
+
+func (se statusError) Error() string {
+	return se.err.Error()
+}
+
+func (se statusError) Unwrap() error {
+	return se.err
+}
+
+// parseRetryAfter decodes the value of Retry-After header,
+// which can be either a number of seconds or a HTTP date.
+func parseRetryAfter(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func runWithRetries(prom *Prometheus, q querier) (result queryResult) {
+	for attempt := 1; ; attempt++ {
+		prom.rateLimiter.Take()
+		result = q.Run()
+
+		retryAfter, code, ok := prom.retry.shouldRetry(attempt, result.err)
+		if !ok {
+			return result
+		}
+
+		delay := prom.retry.backoff(attempt, retryAfter)
+		prometheusQueryRetriesTotal.WithLabelValues(prom.name, q.Endpoint(), strconv.Itoa(code)).Inc()
+		slog.Warn(
+			"Retrying failed query",
+			slog.String("uri", prom.safeURI),
+			slog.String("query", q.String()),
+			slog.Int("code", code),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-q.Context().Done():
+			timer.Stop()
+			result.err = q.Context().Err()
+			return result
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package promapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	require.Equal(t, time.Duration(0), parseRetryAfter(""))
+	require.Equal(t, time.Duration(0), parseRetryAfter("foo"))
+	require.Equal(t, time.Duration(0), parseRetryAfter("-5"))
+	require.Equal(t, time.Second*5, parseRetryAfter("5"))
+	require.Equal(t, time.Duration(0), parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)))
+
+	d := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	require.Greater(t, d, time.Second*50)
+	require.LessOrEqual(t, d, time.Minute)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	rp := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Second * 10}
+
+	for attempt, maxDelay := range map[int]time.Duration{
+		1: time.Second,
+		2: time.Second * 2,
+		3: time.Second * 4,
+		4: time.Second * 8,
+		5: time.Second * 10,
+		9: time.Second * 10,
+	} {
+		for i := 0; i < 100; i++ {
+			delay := rp.backoff(attempt, 0)
+			require.GreaterOrEqual(t, delay, maxDelay/2, "attempt=%d", attempt)
+			require.LessOrEqual(t, delay, maxDelay, "attempt=%d", attempt)
+		}
+	}
+
+	require.Equal(t, time.Second*3, rp.backoff(1, time.Second*3))
+	require.Equal(t, time.Second*10, rp.backoff(1, time.Minute))
+	require.Equal(t, time.Duration(0), RetryPolicy{}.backoff(1, 0))
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	rp := RetryPolicy{MaxAttempts: 3, RetryOn: []int{429, 503}}
+
+	_, _, ok := rp.shouldRetry(1, nil)
+	require.False(t, ok)
+
+	_, _, ok = rp.shouldRetry(1, context.Canceled)
+	require.False(t, ok)
+
+	_, _, ok = rp.shouldRetry(1, statusError{err: APIError{}, code: 500})
+	require.False(t, ok)
+
+	retryAfter, code, ok := rp.shouldRetry(2, QueryError{err: statusError{err: APIError{}, code: 429, retryAfter: time.Second}})
+	require.True(t, ok)
+	require.Equal(t, 429, code)
+	require.Equal(t, time.Second, retryAfter)
+
+	_, _, ok = rp.shouldRetry(3, statusError{err: APIError{}, code: 503})
+	require.False(t, ok)
+
+	_, _, ok = RetryPolicy{}.shouldRetry(1, statusError{err: APIError{}, code: 503})
+	require.False(t, ok)
+}
+
+func TestRetries(t *testing.T) {
+	var requests atomic.Int64
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := requests.Add(1); {
+		case failing.Load(), n == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case n == 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"unavailable","error":"too many requests"}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}
+	}))
+	defer srv.Close()
+
+	retry := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond * 10,
+		RetryOn:        []int{429, 503},
+	}
+
+	prom := NewPrometheus("retries", srv.URL, "", nil, time.Second, 1, 100, nil, nil, nil, retry)
+	prom.StartWorkers()
+	defer prom.Close()
+
+	_, err := prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), requests.Load())
+	require.Equal(t, 1.0, testutil.ToFloat64(prometheusQueryRetriesTotal.WithLabelValues("retries", "/api/v1/query", "503")))
+	require.Equal(t, 1.0, testutil.ToFloat64(prometheusQueryRetriesTotal.WithLabelValues("retries", "/api/v1/query", "429")))
+
+	// All attempts fail.
+	requests.Store(0)
+	failing.Store(true)
+	_, err = prom.Query(context.Background(), "foo")
+	require.EqualError(t, err, "server_error: server error: 503")
+	require.Equal(t, int64(3), requests.Load())
+}
+
+func TestRetriesCanceled(t *testing.T) {
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	retry := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Minute,
+		RetryOn:        []int{503},
+	}
+
+	prom := NewPrometheus("canceled", srv.URL, "", nil, time.Second, 1, 100, nil, nil, nil, retry)
+	prom.StartWorkers()
+	defer prom.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+
+	start := time.Now()
+	_, err := prom.Query(ctx, "up")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second*10)
+	require.Equal(t, int64(1), requests.Load())
+}
+
+func TestProxy(t *testing.T) {
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	prom := NewPrometheus("proxy", "http://prometheus.example.com", "", nil, time.Second, 1, 100, nil, proxyURL, nil, RetryPolicy{})
+	prom.StartWorkers()
+	defer prom.Close()
+
+	_, err = prom.Query(context.Background(), "up")
+	require.NoError(t, err)
+	require.Equal(t, "http://prometheus.example.com/api/v1/query", proxied.Load())
+}
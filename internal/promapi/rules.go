@@ -0,0 +1,166 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+const (
+	RuleHealthOK      = "ok"
+	RuleHealthErr     = "err"
+	RuleHealthUnknown = "unknown"
+)
+
+type RulesResult struct {
+	URI       string
+	PublicURI string
+	Groups    []RuleGroup
+}
+
+// RuleGroup is a rule group as returned by the /api/v1/rules API.
+// Durations are in seconds.
+type RuleGroup struct {
+	LastEvaluation time.Time `json:"lastEvaluation"`
+	Name           string    `json:"name"`
+	File           string    `json:"file"`
+	Rules          []Rule    `json:"rules"`
+	Interval       float64   `json:"interval"`
+	EvaluationTime float64   `json:"evaluationTime"`
+	Limit          int       `json:"limit"`
+}
+
+// Rule is a single alerting or recording rule as returned by the
+// /api/v1/rules API.
+// Durations are in seconds.
+type Rule struct {
+	LastEvaluation time.Time         `json:"lastEvaluation"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+	Type           string            `json:"type"`
+	Name           string            `json:"name"`
+	Query          string            `json:"query"`
+	Health         string            `json:"health"`
+	LastError      string            `json:"lastError,omitempty"`
+	Duration       float64           `json:"duration,omitempty"`
+	KeepFiringFor  float64           `json:"keepFiringFor,omitempty"`
+	EvaluationTime float64           `json:"evaluationTime"`
+}
+
+type rulesQuery struct {
+	prom *Prometheus
+	ctx  context.Context
+}
+
+func (q rulesQuery) Run() queryResult {
+	slog.Debug("Getting prometheus rules", slog.String("uri", q.prom.safeURI))
+
+	ctx, cancel := q.prom.requestContext(q.ctx)
+	defer cancel()
+
+	var qr queryResult
+
+	resp, err := q.prom.doRequest(ctx, http.MethodGet, q.Endpoint(), url.Values{})
+	if err != nil {
+		qr.err = fmt.Errorf("failed to query Prometheus rules: %w", err)
+		return qr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		qr.err = tryDecodingAPIError(resp)
+		return qr
+	}
+
+	qr.value, qr.err = parseRules(resp.Body)
+	return qr
+}
+
+func (q rulesQuery) Context() context.Context {
+	return q.ctx
+}
+
+func (q rulesQuery) Endpoint() string {
+	return "/api/v1/rules"
+}
+
+func (q rulesQuery) String() string {
+	return "/api/v1/rules"
+}
+
+func (q rulesQuery) CacheKey() uint64 {
+	return hash(q.prom.unsafeURI, q.Endpoint())
+}
+
+// Rule health and evaluation times change all the time, so don't
+// keep them for long.
+func (q rulesQuery) CacheTTL() time.Duration {
+	return time.Minute
+}
+
+// Rules returns all rule groups currently loaded by Prometheus,
+// together with their health and evaluation statistics.
+func (p *Prometheus) Rules(ctx context.Context) (*RulesResult, error) {
+	slog.Debug("Scheduling Prometheus rules query", slog.String("uri", p.safeURI))
+
+	key := "/api/v1/rules"
+	p.locker.lock(key)
+	defer p.locker.unlock(key)
+
+	resultChan := make(chan queryResult)
+	p.queries <- queryRequest{
+		query:  rulesQuery{prom: p, ctx: ctx},
+		result: resultChan,
+	}
+
+	result := <-resultChan
+	if result.err != nil {
+		return nil, QueryError{err: result.err, msg: decodeError(result.err)}
+	}
+
+	return &RulesResult{
+		URI:       p.safeURI,
+		PublicURI: p.publicURI,
+		Groups:    result.value.([]RuleGroup),
+	}, nil
+}
+
+func parseRules(r io.Reader) (groups []RuleGroup, err error) {
+	defer dummyReadAll(r)
+
+	var resp struct {
+		Status    string `json:"status"`
+		ErrorType string `json:"errorType"`
+		Error     string `json:"error"`
+		Data      struct {
+			Groups []RuleGroup `json:"groups"`
+		} `json:"data"`
+	}
+	if err = json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, APIError{Status: resp.Status, ErrorType: v1.ErrBadResponse, Err: fmt.Sprintf("JSON parse error: %s", err)}
+	}
+
+	if resp.Status != "success" {
+		return nil, APIError{Status: resp.Status, ErrorType: decodeErrorType(resp.ErrorType), Err: resp.Error}
+	}
+
+	for _, group := range resp.Data.Groups {
+		for _, rule := range group.Rules {
+			if rule.Type != "alerting" && rule.Type != "recording" {
+				return nil, APIError{Status: resp.Status, ErrorType: v1.ErrBadResponse, Err: fmt.Sprintf("unknown rule type %q", rule.Type)}
+			}
+		}
+	}
+	if resp.Data.Groups == nil {
+		resp.Data.Groups = []RuleGroup{}
+	}
+
+	return resp.Data.Groups, nil
+}
@@ -0,0 +1,103 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestRules(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/default/api/v1/rules":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"groups":[
+{"name":"foo","file":"/etc/prometheus/rules.yml","interval":60,"limit":0,"evaluationTime":0.5,"lastEvaluation":"2024-01-01T00:00:00Z","rules":[
+	{"type":"recording","name":"sum:up","query":"sum(up)","labels":{"job":"foo"},"health":"ok","evaluationTime":0.1,"lastEvaluation":"2024-01-01T00:00:00Z"},
+	{"type":"alerting","name":"Down","query":"up == 0","duration":300,"annotations":{"summary":"down"},"health":"err","lastError":"query timed out","evaluationTime":0.4,"lastEvaluation":"2024-01-01T00:00:00Z","alerts":[],"state":"inactive"}
+]}
+]}}`))
+		case "/empty/api/v1/rules":
+			_, _ = w.Write([]byte(`{"status":"success","data":{}}`))
+		case "/badtype/api/v1/rules":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"groups":[{"name":"foo","rules":[{"type":"foo"}]}]}}`))
+		case "/error/api/v1/rules":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		case "/badjson/api/v1/rules":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"groups":{}}}`))
+		default:
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled path"}`))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rules, err := newSeriesTestGroup(t, srv.URL+"/default").Rules(ctx)
+	require.NoError(t, err)
+	require.Equal(t, promapi.RulesResult{
+		URI:       srv.URL + "/default",
+		PublicURI: srv.URL + "/default",
+		Groups: []promapi.RuleGroup{
+			{
+				Name:           "foo",
+				File:           "/etc/prometheus/rules.yml",
+				Interval:       60,
+				EvaluationTime: 0.5,
+				LastEvaluation: ts,
+				Rules: []promapi.Rule{
+					{
+						Type:           "recording",
+						Name:           "sum:up",
+						Query:          "sum(up)",
+						Labels:         map[string]string{"job": "foo"},
+						Health:         promapi.RuleHealthOK,
+						EvaluationTime: 0.1,
+						LastEvaluation: ts,
+					},
+					{
+						Type:           "alerting",
+						Name:           "Down",
+						Query:          "up == 0",
+						Duration:       300,
+						Annotations:    map[string]string{"summary": "down"},
+						Health:         promapi.RuleHealthErr,
+						LastError:      "query timed out",
+						EvaluationTime: 0.4,
+						LastEvaluation: ts,
+					},
+				},
+			},
+		},
+	}, *rules)
+
+	rules, err = newSeriesTestGroup(t, srv.URL+"/empty").Rules(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rules.Groups)
+
+	_, err = newSeriesTestGroup(t, srv.URL+"/badtype").Rules(ctx)
+	require.EqualError(t, err, `bad_response: unknown rule type "foo"`)
+
+	_, err = newSeriesTestGroup(t, srv.URL+"/error").Rules(ctx)
+	require.EqualError(t, err, "server_error: server error: 500")
+
+	_, err = newSeriesTestGroup(t, srv.URL+"/badjson").Rules(ctx)
+	require.EqualError(t, err, "bad_response: JSON parse error: json: cannot unmarshal object into Go struct field .data.groups of type []promapi.RuleGroup")
+}
+
+func TestFixtureRules(t *testing.T) {
+	fg := newFixtureGroup(t, promapi.FixtureOptions{})
+
+	rules, err := fg.Rules(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, rules.Groups)
+}
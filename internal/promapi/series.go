@@ -0,0 +1,406 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prymitive/current"
+)
+
+type SeriesResult struct {
+	URI       string
+	PublicURI string
+	Series    []labels.Labels
+}
+
+type LabelNamesResult struct {
+	URI       string
+	PublicURI string
+	Names     []string
+}
+
+type LabelValuesResult struct {
+	URI       string
+	PublicURI string
+	Values    []string
+}
+
+// seriesParams holds arguments shared by all series and labels API queries.
+// Start and end times are truncated to full minutes, so repeated queries
+// for the same relative time range can be served from the cache.
+type seriesParams struct {
+	start   time.Time
+	end     time.Time
+	matches []string
+	limit   int
+}
+
+func newSeriesParams(matches []string, start, end time.Time, limit int) seriesParams {
+	return seriesParams{
+		matches: matches,
+		start:   start.Truncate(time.Minute),
+		end:     end.Truncate(time.Minute),
+		limit:   limit,
+	}
+}
+
+func (sp seriesParams) args() url.Values {
+	args := url.Values{}
+	for _, m := range sp.matches {
+		args.Add("match[]", m)
+	}
+	if !sp.start.IsZero() {
+		args.Set("start", formatTime(sp.start))
+	}
+	if !sp.end.IsZero() {
+		args.Set("end", formatTime(sp.end))
+	}
+	if sp.limit > 0 {
+		args.Set("limit", strconv.Itoa(sp.limit))
+	}
+	return args
+}
+
+func (sp seriesParams) String() string {
+	return strings.Join(sp.matches, ", ")
+}
+
+func (sp seriesParams) hash(uri, endpoint string) uint64 {
+	return hash(
+		append(
+			[]string{uri, endpoint, sp.start.Format(time.RFC3339), sp.end.Format(time.RFC3339), strconv.Itoa(sp.limit)},
+			sp.matches...,
+		)...,
+	)
+}
+
+type seriesQuery struct {
+	ctx    context.Context
+	prom   *Prometheus
+	params seriesParams
+}
+
+func (q seriesQuery) Run() queryResult {
+	slog.Debug(
+		"Getting prometheus series",
+		slog.String("uri", q.prom.safeURI),
+		slog.String("match", q.params.String()),
+	)
+
+	ctx, cancel := q.prom.requestContext(q.ctx)
+	defer cancel()
+
+	var qr queryResult
+	resp, err := q.prom.doRequest(ctx, http.MethodGet, q.Endpoint(), q.params.args())
+	if err != nil {
+		qr.err = fmt.Errorf("failed to query Prometheus series: %w", err)
+		return qr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		qr.err = tryDecodingAPIError(resp)
+		return qr
+	}
+
+	qr.value, qr.err = streamSeries(resp.Body)
+	return qr
+}
+
+func (q seriesQuery) Context() context.Context {
+	return q.ctx
+}
+
+func (q seriesQuery) Endpoint() string {
+	return "/api/v1/series"
+}
+
+func (q seriesQuery) String() string {
+	return q.params.String()
+}
+
+func (q seriesQuery) CacheKey() uint64 {
+	return q.params.hash(q.prom.unsafeURI, q.Endpoint())
+}
+
+func (q seriesQuery) CacheTTL() time.Duration {
+	return time.Minute * 5
+}
+
+// Series returns all time series matching any of given selectors between start and end.
+// If limit is greater than zero then at most limit series will be returned.
+func (p *Prometheus) Series(ctx context.Context, matches []string, start, end time.Time, limit int) (*SeriesResult, error) {
+	params := newSeriesParams(matches, start, end, limit)
+	slog.Debug("Scheduling Prometheus series query", slog.String("uri", p.safeURI), slog.String("match", params.String()))
+
+	key := fmt.Sprintf("/api/v1/series/%d", params.hash("", ""))
+	p.locker.lock(key)
+	defer p.locker.unlock(key)
+
+	resultChan := make(chan queryResult)
+	p.queries <- queryRequest{
+		query:  seriesQuery{prom: p, ctx: ctx, params: params},
+		result: resultChan,
+	}
+
+	result := <-resultChan
+	if result.err != nil {
+		return nil, QueryError{err: result.err, msg: decodeError(result.err)}
+	}
+
+	return &SeriesResult{
+		URI:       p.safeURI,
+		PublicURI: p.publicURI,
+		Series:    result.value.([]labels.Labels),
+	}, nil
+}
+
+type labelNamesQuery struct {
+	ctx    context.Context
+	prom   *Prometheus
+	params seriesParams
+}
+
+func (q labelNamesQuery) Run() queryResult {
+	slog.Debug(
+		"Getting prometheus label names",
+		slog.String("uri", q.prom.safeURI),
+		slog.String("match", q.params.String()),
+	)
+
+	ctx, cancel := q.prom.requestContext(q.ctx)
+	defer cancel()
+
+	var qr queryResult
+	resp, err := q.prom.doRequest(ctx, http.MethodGet, q.Endpoint(), q.params.args())
+	if err != nil {
+		qr.err = fmt.Errorf("failed to query Prometheus label names: %w", err)
+		return qr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		qr.err = tryDecodingAPIError(resp)
+		return qr
+	}
+
+	qr.value, qr.err = streamStrings(resp.Body)
+	return qr
+}
+
+func (q labelNamesQuery) Context() context.Context {
+	return q.ctx
+}
+
+func (q labelNamesQuery) Endpoint() string {
+	return "/api/v1/labels"
+}
+
+func (q labelNamesQuery) String() string {
+	return q.params.String()
+}
+
+func (q labelNamesQuery) CacheKey() uint64 {
+	return q.params.hash(q.prom.unsafeURI, q.Endpoint())
+}
+
+func (q labelNamesQuery) CacheTTL() time.Duration {
+	return time.Minute * 5
+}
+
+// LabelNames returns names of all labels present on time series matching
+// any of given selectors between start and end.
+func (p *Prometheus) LabelNames(ctx context.Context, matches []string, start, end time.Time, limit int) (*LabelNamesResult, error) {
+	params := newSeriesParams(matches, start, end, limit)
+	slog.Debug("Scheduling Prometheus label names query", slog.String("uri", p.safeURI), slog.String("match", params.String()))
+
+	key := fmt.Sprintf("/api/v1/labels/%d", params.hash("", ""))
+	p.locker.lock(key)
+	defer p.locker.unlock(key)
+
+	resultChan := make(chan queryResult)
+	p.queries <- queryRequest{
+		query:  labelNamesQuery{prom: p, ctx: ctx, params: params},
+		result: resultChan,
+	}
+
+	result := <-resultChan
+	if result.err != nil {
+		return nil, QueryError{err: result.err, msg: decodeError(result.err)}
+	}
+
+	return &LabelNamesResult{
+		URI:       p.safeURI,
+		PublicURI: p.publicURI,
+		Names:     result.value.([]string),
+	}, nil
+}
+
+type labelValuesQuery struct {
+	ctx    context.Context
+	prom   *Prometheus
+	name   string
+	params seriesParams
+}
+
+func (q labelValuesQuery) Run() queryResult {
+	slog.Debug(
+		"Getting prometheus label values",
+		slog.String("uri", q.prom.safeURI),
+		slog.String("label", q.name),
+		slog.String("match", q.params.String()),
+	)
+
+	ctx, cancel := q.prom.requestContext(q.ctx)
+	defer cancel()
+
+	var qr queryResult
+	resp, err := q.prom.doRequest(ctx, http.MethodGet, "/api/v1/label/"+q.name+"/values", q.params.args())
+	if err != nil {
+		qr.err = fmt.Errorf("failed to query Prometheus label values: %w", err)
+		return qr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		qr.err = tryDecodingAPIError(resp)
+		return qr
+	}
+
+	qr.value, qr.err = streamStrings(resp.Body)
+	return qr
+}
+
+func (q labelValuesQuery) Context() context.Context {
+	return q.ctx
+}
+
+// Endpoint doesn't include the label name, so it can be safely used in metric labels.
+func (q labelValuesQuery) Endpoint() string {
+	return "/api/v1/label/:name/values"
+}
+
+func (q labelValuesQuery) String() string {
+	return q.name + " " + q.params.String()
+}
+
+func (q labelValuesQuery) CacheKey() uint64 {
+	return q.params.hash(q.prom.unsafeURI, q.Endpoint()+"/"+q.name)
+}
+
+func (q labelValuesQuery) CacheTTL() time.Duration {
+	return time.Minute * 5
+}
+
+// LabelValues returns all values of given label present on time series matching
+// any of given selectors between start and end.
+func (p *Prometheus) LabelValues(ctx context.Context, name string, matches []string, start, end time.Time, limit int) (*LabelValuesResult, error) {
+	params := newSeriesParams(matches, start, end, limit)
+	slog.Debug("Scheduling Prometheus label values query", slog.String("uri", p.safeURI), slog.String("label", name), slog.String("match", params.String()))
+
+	key := fmt.Sprintf("/api/v1/label/%s/values/%d", name, params.hash("", ""))
+	p.locker.lock(key)
+	defer p.locker.unlock(key)
+
+	resultChan := make(chan queryResult)
+	p.queries <- queryRequest{
+		query:  labelValuesQuery{prom: p, ctx: ctx, name: name, params: params},
+		result: resultChan,
+	}
+
+	result := <-resultChan
+	if result.err != nil {
+		return nil, QueryError{err: result.err, msg: decodeError(result.err)}
+	}
+
+	return &LabelValuesResult{
+		URI:       p.safeURI,
+		PublicURI: p.publicURI,
+		Values:    result.value.([]string),
+	}, nil
+}
+
+func streamSeries(r io.Reader) (series []labels.Labels, err error) {
+	defer dummyReadAll(r)
+
+	var status, errType, errText string
+	series = []labels.Labels{}
+	var metric model.Metric
+	decoder := current.Object(
+		current.Key("status", current.Value(func(s string, isNil bool) {
+			status = s
+		})),
+		current.Key("error", current.Value(func(s string, isNil bool) {
+			errText = s
+		})),
+		current.Key("errorType", current.Value(func(s string, isNil bool) {
+			errType = s
+		})),
+		current.Key("data", current.Array(
+			&metric,
+			func() {
+				series = append(series, MetricToLabels(metric))
+				metric = model.Metric{}
+			},
+		)),
+	)
+
+	dec := json.NewDecoder(r)
+	if err = decoder.Stream(dec); err != nil {
+		return nil, APIError{Status: status, ErrorType: v1.ErrBadResponse, Err: fmt.Sprintf("JSON parse error: %s", err)}
+	}
+
+	if status != "success" {
+		return nil, APIError{Status: status, ErrorType: decodeErrorType(errType), Err: errText}
+	}
+
+	return series, nil
+}
+
+func streamStrings(r io.Reader) (values []string, err error) {
+	defer dummyReadAll(r)
+
+	var status, errType, errText string
+	values = []string{}
+	var value string
+	decoder := current.Object(
+		current.Key("status", current.Value(func(s string, isNil bool) {
+			status = s
+		})),
+		current.Key("error", current.Value(func(s string, isNil bool) {
+			errText = s
+		})),
+		current.Key("errorType", current.Value(func(s string, isNil bool) {
+			errType = s
+		})),
+		current.Key("data", current.Array(
+			&value,
+			func() {
+				values = append(values, value)
+				value = ""
+			},
+		)),
+	)
+
+	dec := json.NewDecoder(r)
+	if err = decoder.Stream(dec); err != nil {
+		return nil, APIError{Status: status, ErrorType: v1.ErrBadResponse, Err: fmt.Sprintf("JSON parse error: %s", err)}
+	}
+
+	if status != "success" {
+		return nil, APIError{Status: status, ErrorType: decodeErrorType(errType), Err: errText}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,203 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newSeriesTestServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.Form.Get("start") == "" || r.Form.Get("end") == "" {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"missing start or end"}`))
+			return
+		}
+
+		match := strings.Join(r.Form["match[]"], ",")
+		w.Header().Set("Content-Type", "application/json")
+		switch match {
+		case "foo":
+			switch r.URL.Path {
+			case "/api/v1/series":
+				if r.Form.Get("limit") == "1" {
+					_, _ = w.Write([]byte(`{"status":"success","data":[{"__name__":"foo","job":"a"}]}`))
+				} else {
+					_, _ = w.Write([]byte(`{"status":"success","data":[{"__name__":"foo","job":"a"},{"__name__":"foo","job":"b"}]}`))
+				}
+			case "/api/v1/labels":
+				_, _ = w.Write([]byte(`{"status":"success","data":["__name__","job"]}`))
+			case "/api/v1/label/job/values":
+				_, _ = w.Write([]byte(`{"status":"success","data":["a","b"]}`))
+			default:
+				w.WriteHeader(404)
+			}
+		case "empty":
+			_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+		case "error":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		case "badjson":
+			_, _ = w.Write([]byte(`{"status":"success","data":{}`))
+		default:
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled match"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSeriesTestGroup(t *testing.T, uri string) *promapi.FailoverGroup {
+	fg := promapi.NewFailoverGroup("test", uri, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", uri, "", nil, time.Second, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	t.Cleanup(func() { fg.Close(reg) })
+	return fg
+}
+
+func TestSeries(t *testing.T) {
+	srv := newSeriesTestServer(t)
+	fg := newSeriesTestGroup(t, srv.URL)
+	ctx := context.Background()
+	end := time.Now()
+	start := end.Add(time.Hour * -1)
+
+	sr, err := fg.Series(ctx, []string{"foo"}, start, end, 0)
+	require.NoError(t, err)
+	require.Equal(t, promapi.SeriesResult{
+		URI:       srv.URL,
+		PublicURI: srv.URL,
+		Series: []labels.Labels{
+			labels.FromStrings(labels.MetricName, "foo", "job", "a"),
+			labels.FromStrings(labels.MetricName, "foo", "job", "b"),
+		},
+	}, *sr)
+
+	sr, err = fg.Series(ctx, []string{"foo"}, start, end, 1)
+	require.NoError(t, err)
+	require.Len(t, sr.Series, 1)
+
+	sr, err = fg.Series(ctx, []string{"empty"}, start, end, 0)
+	require.NoError(t, err)
+	require.Empty(t, sr.Series)
+
+	_, err = fg.Series(ctx, []string{"error"}, start, end, 0)
+	require.EqualError(t, err, "server_error: server error: 500")
+
+	_, err = fg.Series(ctx, []string{"badjson"}, start, end, 0)
+	require.EqualError(t, err, "bad_response: JSON parse error: invalid token at offset 28 decoded by Array[model.Metric], expected [, got {")
+
+	_, err = fg.Series(ctx, []string{"bar"}, start, end, 0)
+	require.EqualError(t, err, "bad_data: unhandled match")
+}
+
+func TestLabelNames(t *testing.T) {
+	srv := newSeriesTestServer(t)
+	fg := newSeriesTestGroup(t, srv.URL)
+	ctx := context.Background()
+	end := time.Now()
+	start := end.Add(time.Hour * -1)
+
+	lr, err := fg.LabelNames(ctx, []string{"foo"}, start, end, 0)
+	require.NoError(t, err)
+	require.Equal(t, promapi.LabelNamesResult{
+		URI:       srv.URL,
+		PublicURI: srv.URL,
+		Names:     []string{"__name__", "job"},
+	}, *lr)
+
+	lr, err = fg.LabelNames(ctx, []string{"empty"}, start, end, 0)
+	require.NoError(t, err)
+	require.Empty(t, lr.Names)
+
+	_, err = fg.LabelNames(ctx, []string{"error"}, start, end, 0)
+	require.EqualError(t, err, "server_error: server error: 500")
+}
+
+func TestLabelValues(t *testing.T) {
+	srv := newSeriesTestServer(t)
+	fg := newSeriesTestGroup(t, srv.URL)
+	ctx := context.Background()
+	end := time.Now()
+	start := end.Add(time.Hour * -1)
+
+	lr, err := fg.LabelValues(ctx, "job", []string{"foo"}, start, end, 0)
+	require.NoError(t, err)
+	require.Equal(t, promapi.LabelValuesResult{
+		URI:       srv.URL,
+		PublicURI: srv.URL,
+		Values:    []string{"a", "b"},
+	}, *lr)
+
+	lr, err = fg.LabelValues(ctx, "job", []string{"empty"}, start, end, 0)
+	require.NoError(t, err)
+	require.Empty(t, lr.Values)
+
+	_, err = fg.LabelValues(ctx, "instance", []string{"foo"}, start, end, 0)
+	require.EqualError(t, err, "client_error: client error: 404")
+}
+
+func TestFixtureSeries(t *testing.T) {
+	dir := t.TempDir()
+	fg := newFixtureGroup(t, promapi.FixtureOptions{
+		Exposition: []string{writeFixtureFile(t, dir, "metrics.txt", `# TYPE http_requests_total counter
+http_requests_total{job="api",code="200"} 100
+http_requests_total{job="api",code="500"} 5
+http_requests_total{job="web",code="200"} 1
+`)},
+	})
+	ctx := context.Background()
+	end := time.Now()
+	start := end.Add(time.Hour * -1)
+
+	sr, err := fg.Series(ctx, []string{`http_requests_total{code="200"}`}, start, end, 0)
+	require.NoError(t, err)
+	require.Equal(t, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "http_requests_total", "code", "200", "job", "api"),
+		labels.FromStrings(labels.MetricName, "http_requests_total", "code", "200", "job", "web"),
+	}, sr.Series)
+
+	sr, err = fg.Series(ctx, []string{`http_requests_total`}, start, end, 1)
+	require.NoError(t, err)
+	require.Len(t, sr.Series, 1)
+
+	sr, err = fg.Series(ctx, []string{`http_requests_total`}, start.Add(time.Hour*-24), start.Add(time.Hour*-23), 0)
+	require.NoError(t, err)
+	require.Empty(t, sr.Series)
+
+	_, err = fg.Series(ctx, []string{`http_requests_total{`}, start, end, 0)
+	require.ErrorContains(t, err, "bad_data: ")
+
+	ln, err := fg.LabelNames(ctx, []string{`http_requests_total`}, start, end, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"__name__", "code", "job"}, ln.Names)
+
+	lv, err := fg.LabelValues(ctx, "job", []string{`http_requests_total{code="500"}`}, start, end, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api"}, lv.Values)
+
+	lv, err = fg.LabelValues(ctx, "job", []string{`http_requests_total`}, start, end, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api"}, lv.Values)
+
+	lv, err = fg.LabelValues(ctx, "instance", []string{`http_requests_total`}, start, end, 0)
+	require.NoError(t, err)
+	require.Empty(t, lv.Values)
+}
@@ -0,0 +1,52 @@
+package promapi
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	cacheMemory   = "memory"
+	cacheDisk     = "disk"
+	cacheMiss     = "miss"
+	cacheDisabled = "disabled"
+)
+
+var tracer = otel.Tracer("github.com/cloudflare/pint/internal/promapi")
+
+func startQuerySpan(prom *Prometheus, q querier) trace.Span {
+	_, span := tracer.Start(
+		q.Context(),
+		"promapi.query",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("prometheus.name", prom.name),
+			attribute.String("prometheus.uri", prom.safeURI),
+			attribute.String("prometheus.endpoint", q.Endpoint()),
+			attribute.String("prometheus.query", q.String()),
+		),
+	)
+	return span
+}
+
+func endQuerySpan(span trace.Span, cache string, result queryResult) {
+	span.SetAttributes(attribute.String("prometheus.cache", cache))
+	if result.err != nil {
+		span.RecordError(result.err)
+		span.SetStatus(codes.Error, result.err.Error())
+	} else {
+		if samples, ok := result.value.([]Sample); ok {
+			span.SetAttributes(attribute.Int("prometheus.response.series", len(samples)))
+		}
+		span.SetAttributes(
+			attribute.Int("prometheus.stats.samples.total", result.stats.Samples.TotalQueryableSamples),
+			attribute.Int("prometheus.stats.samples.peak", result.stats.Samples.PeakSamples),
+			attribute.Float64("prometheus.stats.timings.eval_total", result.stats.Timings.EvalTotalTime),
+			attribute.Float64("prometheus.stats.timings.exec_total", result.stats.Timings.ExecTotalTime),
+			attribute.Float64("prometheus.stats.timings.exec_queue", result.stats.Timings.ExecQueueTime),
+		)
+	}
+	span.End()
+}
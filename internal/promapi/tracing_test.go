@@ -0,0 +1,105 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestQueryTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	defer func() {
+		_ = tp.Shutdown(context.Background())
+	}()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.Form.Get("query") {
+		case "error":
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"bad input data"}`))
+		default:
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{
+				"status":"success",
+				"data":{
+					"resultType":"vector",
+					"result":[{"metric":{},"value":[1614859502.068,"1"]}],
+					"stats":{"timings":{"execTotalTime":0.5},"samples":{"totalQueryableSamples":10,"peakSamples":5}}
+				}
+			}`))
+		}
+	}))
+	defer srv.Close()
+
+	fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, srv.URL, nil, time.Second, 1, 100, nil, nil, nil, promapi.RetryPolicy{}),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	defer fg.Close(reg)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	_, err := fg.Query(ctx, "up")
+	require.NoError(t, err)
+	_, err = fg.Query(ctx, "up")
+	require.NoError(t, err)
+	_, err = fg.Query(ctx, "error")
+	require.Error(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+
+	attrs := func(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+
+	for _, span := range spans[:3] {
+		require.Equal(t, "promapi.query", span.Name)
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+		a := attrs(span)
+		require.Equal(t, "test", a["prometheus.name"].AsString())
+		require.Equal(t, srv.URL, a["prometheus.uri"].AsString())
+		require.Equal(t, "/api/v1/query", a["prometheus.endpoint"].AsString())
+	}
+
+	a := attrs(spans[0])
+	require.Equal(t, "up", a["prometheus.query"].AsString())
+	require.Equal(t, "miss", a["prometheus.cache"].AsString())
+	require.Equal(t, int64(1), a["prometheus.response.series"].AsInt64())
+	require.Equal(t, int64(10), a["prometheus.stats.samples.total"].AsInt64())
+	require.Equal(t, int64(5), a["prometheus.stats.samples.peak"].AsInt64())
+	require.Equal(t, 0.5, a["prometheus.stats.timings.exec_total"].AsFloat64())
+	require.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	a = attrs(spans[1])
+	require.Equal(t, "up", a["prometheus.query"].AsString())
+	require.Equal(t, "memory", a["prometheus.cache"].AsString())
+
+	a = attrs(spans[2])
+	require.Equal(t, "error", a["prometheus.query"].AsString())
+	require.Equal(t, "miss", a["prometheus.cache"].AsString())
+	require.Equal(t, codes.Error, spans[2].Status.Code)
+	require.Len(t, spans[2].Events, 1)
+
+	require.Equal(t, "parent", spans[3].Name)
+}
@@ -0,0 +1,125 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+type TSDBStatusResult struct {
+	URI       string
+	PublicURI string
+	Status    v1.TSDBResult
+}
+
+type tsdbStatusQuery struct {
+	prom  *Prometheus
+	ctx   context.Context
+	limit int
+}
+
+func (q tsdbStatusQuery) Run() queryResult {
+	slog.Debug("Getting prometheus TSDB status", slog.String("uri", q.prom.safeURI), slog.Int("limit", q.limit))
+
+	ctx, cancel := q.prom.requestContext(q.ctx)
+	defer cancel()
+
+	var qr queryResult
+
+	args := url.Values{}
+	if q.limit > 0 {
+		args.Set("limit", strconv.Itoa(q.limit))
+	}
+	resp, err := q.prom.doRequest(ctx, http.MethodGet, q.Endpoint(), args)
+	if err != nil {
+		qr.err = fmt.Errorf("failed to query Prometheus TSDB status: %w", err)
+		return qr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		qr.err = tryDecodingAPIError(resp)
+		return qr
+	}
+
+	qr.value, qr.err = parseTSDBStatus(resp.Body)
+	return qr
+}
+
+func (q tsdbStatusQuery) Context() context.Context {
+	return q.ctx
+}
+
+func (q tsdbStatusQuery) Endpoint() string {
+	return "/api/v1/status/tsdb"
+}
+
+func (q tsdbStatusQuery) String() string {
+	return "/api/v1/status/tsdb"
+}
+
+func (q tsdbStatusQuery) CacheKey() uint64 {
+	return hash(q.prom.unsafeURI, q.Endpoint(), strconv.Itoa(q.limit))
+}
+
+func (q tsdbStatusQuery) CacheTTL() time.Duration {
+	return time.Minute * 10
+}
+
+// TSDBStatus returns cardinality statistics of the TSDB head block.
+// Every list of statistics will include at most limit entries, sorted
+// by value, but older Prometheus versions might ignore the limit and
+// always return top 10 entries.
+func (p *Prometheus) TSDBStatus(ctx context.Context, limit int) (*TSDBStatusResult, error) {
+	slog.Debug("Scheduling Prometheus TSDB status query", slog.String("uri", p.safeURI), slog.Int("limit", limit))
+
+	key := fmt.Sprintf("/api/v1/status/tsdb/%d", limit)
+	p.locker.lock(key)
+	defer p.locker.unlock(key)
+
+	resultChan := make(chan queryResult)
+	p.queries <- queryRequest{
+		query:  tsdbStatusQuery{prom: p, ctx: ctx, limit: limit},
+		result: resultChan,
+	}
+
+	result := <-resultChan
+	if result.err != nil {
+		return nil, QueryError{err: result.err, msg: decodeError(result.err)}
+	}
+
+	return &TSDBStatusResult{
+		URI:       p.safeURI,
+		PublicURI: p.publicURI,
+		Status:    result.value.(v1.TSDBResult),
+	}, nil
+}
+
+// The response is always small, there's no need to stream it.
+func parseTSDBStatus(r io.Reader) (status v1.TSDBResult, err error) {
+	defer dummyReadAll(r)
+
+	var resp struct {
+		Status    string        `json:"status"`
+		ErrorType string        `json:"errorType"`
+		Error     string        `json:"error"`
+		Data      v1.TSDBResult `json:"data"`
+	}
+	if err = json.NewDecoder(r).Decode(&resp); err != nil {
+		return status, APIError{Status: resp.Status, ErrorType: v1.ErrBadResponse, Err: fmt.Sprintf("JSON parse error: %s", err)}
+	}
+
+	if resp.Status != "success" {
+		return status, APIError{Status: resp.Status, ErrorType: decodeErrorType(resp.ErrorType), Err: resp.Error}
+	}
+
+	return resp.Data, nil
+}
@@ -0,0 +1,113 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestTSDBStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/default/api/v1/status/tsdb":
+			if r.FormValue("limit") != "100" {
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"bad limit"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"success","data":{
+"headStats":{"numSeries":1000,"numLabelPairs":50,"chunkCount":2000,"minTime":1,"maxTime":2},
+"seriesCountByMetricName":[{"name":"foo","value":800},{"name":"bar","value":200}],
+"labelValueCountByLabelName":[{"name":"instance","value":40},{"name":"job","value":2}],
+"memoryInBytesByLabelName":[],
+"seriesCountByLabelValuePair":[{"name":"job=a","value":600}]
+}}`))
+		case "/error/api/v1/status/tsdb":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		case "/thanos/api/v1/status/tsdb":
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte("404 page not found\n"))
+		case "/badjson/api/v1/status/tsdb":
+			_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+		default:
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled path"}`))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	fg := newSeriesTestGroup(t, srv.URL+"/default")
+	ts, err := fg.TSDBStatus(ctx, 100)
+	require.NoError(t, err)
+	require.Equal(t, promapi.TSDBStatusResult{
+		URI:       srv.URL + "/default",
+		PublicURI: srv.URL + "/default",
+		Status: v1.TSDBResult{
+			HeadStats: v1.TSDBHeadStats{NumSeries: 1000, NumLabelPairs: 50, ChunkCount: 2000, MinTime: 1, MaxTime: 2},
+			SeriesCountByMetricName: []v1.Stat{
+				{Name: "foo", Value: 800},
+				{Name: "bar", Value: 200},
+			},
+			LabelValueCountByLabelName: []v1.Stat{
+				{Name: "instance", Value: 40},
+				{Name: "job", Value: 2},
+			},
+			MemoryInBytesByLabelName: []v1.Stat{},
+			SeriesCountByLabelValuePair: []v1.Stat{
+				{Name: "job=a", Value: 600},
+			},
+		},
+	}, *ts)
+
+	_, err = fg.TSDBStatus(ctx, 10)
+	require.EqualError(t, err, "bad_data: bad limit")
+
+	_, err = newSeriesTestGroup(t, srv.URL+"/error").TSDBStatus(ctx, 100)
+	require.EqualError(t, err, "server_error: server error: 500")
+	require.False(t, promapi.IsUnsupportedError(err))
+
+	_, err = newSeriesTestGroup(t, srv.URL+"/thanos").TSDBStatus(ctx, 100)
+	require.EqualError(t, err, "client_error: client error: 404")
+	require.True(t, promapi.IsUnsupportedError(err))
+
+	_, err = newSeriesTestGroup(t, srv.URL+"/badjson").TSDBStatus(ctx, 100)
+	require.EqualError(t, err, "bad_response: JSON parse error: json: cannot unmarshal array into Go struct field .data of type v1.TSDBResult")
+}
+
+func TestFixtureTSDBStatus(t *testing.T) {
+	dir := t.TempDir()
+	fg := newFixtureGroup(t, promapi.FixtureOptions{
+		Exposition: []string{writeFixtureFile(t, dir, "metrics.txt", `# TYPE http_requests_total counter
+http_requests_total{job="api",code="200"} 100
+http_requests_total{job="api",code="500"} 5
+http_requests_total{job="web",code="200"} 1
+up{job="api"} 1
+`)},
+	})
+
+	ts, err := fg.TSDBStatus(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, 4, ts.Status.HeadStats.NumSeries)
+	require.Equal(t, []v1.Stat{
+		{Name: "http_requests_total", Value: 3},
+		{Name: "up", Value: 1},
+	}, ts.Status.SeriesCountByMetricName)
+	require.Equal(t, []v1.Stat{
+		{Name: "__name__", Value: 2},
+		{Name: "code", Value: 2},
+	}, ts.Status.LabelValueCountByLabelName)
+	require.Equal(t, []v1.Stat{
+		{Name: "__name__=http_requests_total", Value: 3},
+		{Name: "job=api", Value: 3},
+	}, ts.Status.SeriesCountByLabelValuePair)
+}
@@ -0,0 +1,233 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+const (
+	AlertmanagerAlertName = "PintProblem"
+	alertmanagerAlertsAPI = "/api/v2/alerts"
+)
+
+// AlertmanagerAlert is the alert payload accepted by Alertmanager
+// https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml
+type AlertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+func (a AlertmanagerAlert) fingerprint() string {
+	keys := make([]string, 0, len(a.Labels))
+	for k := range a.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf strings.Builder
+	for _, k := range keys {
+		buf.WriteString(k)
+		buf.WriteRune('\xff')
+		buf.WriteString(a.Labels[k])
+		buf.WriteRune('\xff')
+	}
+	return buf.String()
+}
+
+func NewAlertmanagerReporter(uri string, timeout time.Duration, headers, labels map[string]string, minSeverity checks.Severity, ttl time.Duration) *AlertmanagerReporter {
+	return &AlertmanagerReporter{
+		uri:         strings.TrimSuffix(uri, "/"),
+		timeout:     timeout,
+		headers:     headers,
+		labels:      labels,
+		minSeverity: minSeverity,
+		ttl:         ttl,
+		active:      map[string]AlertmanagerAlert{},
+		now:         time.Now,
+	}
+}
+
+// AlertmanagerReporter sends all problems as alerts to Alertmanager.
+// Alerts for problems that are no longer reported are sent again with
+// endsAt set to the current time, so Alertmanager resolves them.
+type AlertmanagerReporter struct {
+	active      map[string]AlertmanagerAlert
+	headers     map[string]string
+	labels      map[string]string
+	now         func() time.Time
+	uri         string
+	timeout     time.Duration
+	ttl         time.Duration
+	minSeverity checks.Severity
+}
+
+// ResumeFrom copies all active alerts sent by another reporter, so they're
+// resolved by this one once the problem is gone, even if alert labels or
+// the Alertmanager URI changed.
+func (am *AlertmanagerReporter) ResumeFrom(prev *AlertmanagerReporter) {
+	for fp, alert := range prev.active {
+		am.active[fp] = alert
+	}
+}
+
+func (am *AlertmanagerReporter) Submit(summary Summary) error {
+	now := am.now()
+
+	current := map[string]AlertmanagerAlert{}
+	keys := []string{}
+	for _, report := range summary.reports {
+		if report.Problem.Severity < am.minSeverity {
+			continue
+		}
+		alert := am.makeAlert(report)
+		fp := alert.fingerprint()
+		if prev, ok := current[fp]; ok {
+			// Multiple problems with the same labels are merged into a single alert.
+			alert.Annotations = mergeAnnotations(prev.Annotations, alert.Annotations)
+		} else {
+			keys = append(keys, fp)
+		}
+		alert.StartsAt = now
+		if prev, ok := am.active[fp]; ok {
+			alert.StartsAt = prev.StartsAt
+		}
+		alert.EndsAt = now.Add(am.ttl)
+		current[fp] = alert
+	}
+
+	alerts := make([]AlertmanagerAlert, 0, len(current)+len(am.active))
+	sort.Strings(keys)
+	for _, fp := range keys {
+		alerts = append(alerts, current[fp])
+	}
+
+	resolved := map[string]AlertmanagerAlert{}
+	for fp, alert := range am.active {
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		alert.EndsAt = now
+		resolved[fp] = alert
+	}
+	keys = keys[:0]
+	for fp := range resolved {
+		keys = append(keys, fp)
+	}
+	sort.Strings(keys)
+	for _, fp := range keys {
+		alerts = append(alerts, resolved[fp])
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if err := am.send(alerts); err != nil {
+		// Keep resolved alerts around so we try to resolve them on next run.
+		for fp, alert := range resolved {
+			current[fp] = alert
+		}
+		am.active = current
+		return fmt.Errorf("failed to send alerts to Alertmanager: %w", err)
+	}
+	slog.Info(
+		"Sent alerts to Alertmanager",
+		slog.Int("firing", len(current)),
+		slog.Int("resolved", len(resolved)),
+	)
+	am.active = current
+
+	return nil
+}
+
+func (am AlertmanagerReporter) makeAlert(report Report) AlertmanagerAlert {
+	labels := make(map[string]string, len(am.labels)+6)
+	for k, v := range am.labels {
+		labels[k] = v
+	}
+	labels["alertname"] = AlertmanagerAlertName
+	labels["filename"] = report.SourcePath
+	labels["reporter"] = report.Problem.Reporter
+	labels["severity"] = strings.ToLower(report.Problem.Severity.String())
+	if name := report.Rule.Name(); name != "" {
+		labels["name"] = name
+	}
+	if report.Owner != "" {
+		labels["owner"] = report.Owner
+	}
+
+	annotations := map[string]string{
+		"summary": report.Problem.Text,
+	}
+	if report.Problem.Details != "" {
+		annotations["details"] = report.Problem.Details
+	}
+
+	return AlertmanagerAlert{
+		Labels:      labels,
+		Annotations: annotations,
+	}
+}
+
+func mergeAnnotations(a, b map[string]string) map[string]string {
+	merged := make(map[string]string, len(a))
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range b {
+		if cur, ok := merged[k]; ok {
+			if slices.Contains(strings.Split(cur, "\n\n"), v) {
+				continue
+			}
+			merged[k] = cur + "\n\n" + v
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+func (am AlertmanagerReporter) send(alerts []AlertmanagerAlert) error {
+	payload, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	slog.Debug("Sending alerts to Alertmanager", slog.String("uri", am.uri), slog.Int("alerts", len(alerts)))
+
+	req, err := http.NewRequest(http.MethodPost, am.uri+alertmanagerAlertsAPI, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range am.headers {
+		req.Header.Set(k, v)
+	}
+
+	netClient := &http.Client{
+		Timeout: am.timeout,
+	}
+
+	resp, err := netClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s request failed with %s: %s", http.MethodPost, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestAlertmanagerReporter(t *testing.T) {
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- alert: TargetIsDown
+  expr: up == 0
+- record: target:up
+  expr: up
+`))
+
+	down := Report{
+		ReportedPath: "foo.yml",
+		SourcePath:   "foo.yml",
+		Owner:        "alice",
+		Rule:         mockRules[0],
+		Problem: checks.Problem{
+			Reporter: "mock",
+			Text:     "mock text",
+			Details:  "mock details",
+			Severity: checks.Bug,
+		},
+	}
+	down2 := down
+	down2.Problem.Text = "another mock text"
+	up := Report{
+		ReportedPath: "bar.yml",
+		SourcePath:   "bar.yml",
+		Rule:         mockRules[1],
+		Problem: checks.Problem{
+			Reporter: "mock",
+			Text:     "mock text",
+			Severity: checks.Fatal,
+		},
+	}
+	warning := up
+	warning.Problem.Severity = checks.Warning
+
+	type requestT struct {
+		header string
+		alerts []AlertmanagerAlert
+	}
+
+	var requests []requestT
+	var code int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/v2/alerts", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var alerts []AlertmanagerAlert
+		require.NoError(t, json.Unmarshal(body, &alerts))
+		requests = append(requests, requestT{header: r.Header.Get("X-Auth"), alerts: alerts})
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte("mock error"))
+	}))
+	defer srv.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	am := NewAlertmanagerReporter(
+		srv.URL+"/",
+		time.Second*5,
+		map[string]string{"X-Auth": "secret"},
+		map[string]string{"source": "pint"},
+		checks.Bug,
+		time.Hour,
+	)
+	am.now = func() time.Time { return now }
+
+	downLabels := map[string]string{
+		"alertname": "PintProblem",
+		"filename":  "foo.yml",
+		"name":      "TargetIsDown",
+		"owner":     "alice",
+		"reporter":  "mock",
+		"severity":  "bug",
+		"source":    "pint",
+	}
+	upLabels := map[string]string{
+		"alertname": "PintProblem",
+		"filename":  "bar.yml",
+		"name":      "target:up",
+		"reporter":  "mock",
+		"severity":  "fatal",
+		"source":    "pint",
+	}
+
+	// No problems and nothing to resolve, no request is sent.
+	code = http.StatusOK
+	require.NoError(t, am.Submit(NewSummary(nil)))
+	require.Empty(t, requests)
+
+	// Two problems are firing, warnings are ignored.
+	require.NoError(t, am.Submit(NewSummary([]Report{down, down2, up, warning})))
+	require.Len(t, requests, 1)
+	require.Equal(t, "secret", requests[0].header)
+	require.Equal(t, []AlertmanagerAlert{
+		{
+			Labels:      upLabels,
+			Annotations: map[string]string{"summary": "mock text"},
+			StartsAt:    start,
+			EndsAt:      start.Add(time.Hour),
+		},
+		{
+			Labels:      downLabels,
+			Annotations: map[string]string{"summary": "mock text\n\nanother mock text", "details": "mock details"},
+			StartsAt:    start,
+			EndsAt:      start.Add(time.Hour),
+		},
+	}, requests[0].alerts)
+
+	// One problem is resolved, the other one is refreshed.
+	now = start.Add(time.Minute)
+	requests = nil
+	require.NoError(t, am.Submit(NewSummary([]Report{down})))
+	require.Len(t, requests, 1)
+	require.Equal(t, []AlertmanagerAlert{
+		{
+			Labels:      downLabels,
+			Annotations: map[string]string{"summary": "mock text", "details": "mock details"},
+			StartsAt:    start,
+			EndsAt:      now.Add(time.Hour),
+		},
+		{
+			Labels:      upLabels,
+			Annotations: map[string]string{"summary": "mock text"},
+			StartsAt:    start,
+			EndsAt:      now,
+		},
+	}, requests[0].alerts)
+
+	// Alertmanager fails, resolved alert is retried on next run.
+	now = start.Add(time.Minute * 2)
+	requests = nil
+	code = http.StatusInternalServerError
+	require.EqualError(t, am.Submit(NewSummary(nil)), "failed to send alerts to Alertmanager: POST request failed with 500 Internal Server Error: mock error")
+	require.Len(t, requests, 1)
+	require.Len(t, requests[0].alerts, 1)
+	require.Equal(t, now, requests[0].alerts[0].EndsAt)
+
+	now = start.Add(time.Minute * 3)
+	requests = nil
+	code = http.StatusOK
+	require.NoError(t, am.Submit(NewSummary(nil)))
+	require.Len(t, requests, 1)
+	require.Equal(t, []AlertmanagerAlert{
+		{
+			Labels:      downLabels,
+			Annotations: map[string]string{"summary": "mock text", "details": "mock details"},
+			StartsAt:    start,
+			EndsAt:      now,
+		},
+	}, requests[0].alerts)
+
+	// Everything is resolved, no request is sent.
+	requests = nil
+	require.NoError(t, am.Submit(NewSummary(nil)))
+	require.Empty(t, requests)
+}
+
+func TestAlertmanagerReporterConnectionError(t *testing.T) {
+	am := NewAlertmanagerReporter("http://127.0.0.1:1", time.Second, nil, nil, checks.Information, time.Hour)
+	err := am.Submit(NewSummary([]Report{{
+		SourcePath: "foo.yml",
+		Problem:    checks.Problem{Reporter: "mock", Text: "mock", Severity: checks.Bug},
+	}}))
+	require.ErrorContains(t, err, "failed to send alerts to Alertmanager: ")
+}
+
+func TestAlertmanagerReporterResumeFrom(t *testing.T) {
+	var requests [][]AlertmanagerAlert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var alerts []AlertmanagerAlert
+		require.NoError(t, json.Unmarshal(body, &alerts))
+		requests = append(requests, alerts)
+	}))
+	defer srv.Close()
+
+	report := Report{
+		SourcePath: "foo.yml",
+		Problem:    checks.Problem{Reporter: "mock", Text: "mock", Severity: checks.Bug},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := NewAlertmanagerReporter(srv.URL, time.Second*5, nil, map[string]string{"env": "old"}, checks.Information, time.Hour)
+	prev.now = func() time.Time { return start }
+	require.NoError(t, prev.Submit(NewSummary([]Report{report})))
+	require.Len(t, requests, 1)
+
+	// Labels changed, so the old alert must be resolved by the new reporter.
+	now := start.Add(time.Minute)
+	am := NewAlertmanagerReporter(srv.URL, time.Second*5, nil, map[string]string{"env": "new"}, checks.Information, time.Hour)
+	am.now = func() time.Time { return now }
+	am.ResumeFrom(prev)
+	require.NoError(t, am.Submit(NewSummary([]Report{report})))
+	require.Len(t, requests, 2)
+	require.Len(t, requests[1], 2)
+	require.Equal(t, "new", requests[1][0].Labels["env"])
+	require.Equal(t, now, requests[1][0].StartsAt)
+	require.Equal(t, now.Add(time.Hour), requests[1][0].EndsAt)
+	require.Equal(t, "old", requests[1][1].Labels["env"])
+	require.Equal(t, start, requests[1][1].StartsAt)
+	require.Equal(t, now, requests[1][1].EndsAt)
+}
@@ -0,0 +1,167 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+type jsonLines struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+type jsonReport struct {
+	ReportedPath  string    `json:"reportedPath"`
+	SourcePath    string    `json:"sourcePath"`
+	Owner         string    `json:"owner,omitempty"`
+	ModifiedLines []int     `json:"modifiedLines,omitempty"`
+	RuleLines     jsonLines `json:"ruleLines"`
+	Lines         jsonLines `json:"lines"`
+	Reporter      string    `json:"reporter"`
+	Text          string    `json:"text"`
+	Details       string    `json:"details,omitempty"`
+	Severity      string    `json:"severity"`
+	Anchor        string    `json:"anchor,omitempty"`
+}
+
+type jsonSummary struct {
+	TruncatedChecks map[string]int `json:"truncatedChecks,omitempty"`
+	Reports         []jsonReport   `json:"reports"`
+	OfflineChecks   int64          `json:"offlineChecks"`
+	OnlineChecks    int64          `json:"onlineChecks"`
+	DurationMs      int64          `json:"durationMs"`
+	TotalEntries    int            `json:"totalEntries"`
+	CheckedEntries  int64          `json:"checkedEntries"`
+}
+
+func NewJSONReporter(output io.Writer) JSONReporter {
+	return JSONReporter{output: output}
+}
+
+// JSONReporter writes all reports and summary counters as JSON, results
+// of multiple pint runs can be merged later using ReadJSONSummary.
+type JSONReporter struct {
+	output io.Writer
+}
+
+func (jr JSONReporter) Submit(summary Summary) error {
+	js := jsonSummary{
+		TruncatedChecks: summary.TruncatedChecks,
+		Reports:         make([]jsonReport, 0, len(summary.reports)),
+		OfflineChecks:   summary.OfflineChecks,
+		OnlineChecks:    summary.OnlineChecks,
+		DurationMs:      summary.Duration.Milliseconds(),
+		TotalEntries:    summary.TotalEntries,
+		CheckedEntries:  summary.CheckedEntries,
+	}
+	for _, report := range summary.reports {
+		r := jsonReport{
+			ReportedPath:  report.ReportedPath,
+			SourcePath:    report.SourcePath,
+			Owner:         report.Owner,
+			ModifiedLines: report.ModifiedLines,
+			RuleLines:     jsonLines{First: report.Rule.Lines.First, Last: report.Rule.Lines.Last},
+			Lines:         jsonLines{First: report.Problem.Lines.First, Last: report.Problem.Lines.Last},
+			Reporter:      report.Problem.Reporter,
+			Text:          report.Problem.Text,
+			Details:       report.Problem.Details,
+			Severity:      jsonSeverity(report.Problem.Severity),
+		}
+		if report.Problem.Anchor == checks.AnchorBefore {
+			r.Anchor = "before"
+		}
+		js.Reports = append(js.Reports, r)
+	}
+
+	enc := json.NewEncoder(jr.output)
+	enc.SetIndent("", "  ")
+	return enc.Encode(js)
+}
+
+// jsonSeverity returns severity name accepted by checks.ParseSeverity.
+func jsonSeverity(s checks.Severity) string {
+	if s == checks.Information {
+		return "info"
+	}
+	return strings.ToLower(s.String())
+}
+
+// ReadJSONSummary reads a summary written by JSONReporter.
+// Reports will only have rule lines set, other rule fields are not stored.
+func ReadJSONSummary(r io.Reader) (summary Summary, err error) {
+	var js jsonSummary
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&js); err != nil {
+		return summary, err
+	}
+
+	summary.TruncatedChecks = js.TruncatedChecks
+	summary.OfflineChecks = js.OfflineChecks
+	summary.OnlineChecks = js.OnlineChecks
+	summary.Duration = time.Duration(js.DurationMs) * time.Millisecond
+	summary.TotalEntries = js.TotalEntries
+	summary.CheckedEntries = js.CheckedEntries
+	for _, jr := range js.Reports {
+		severity, err := checks.ParseSeverity(jr.Severity)
+		if err != nil {
+			return summary, err
+		}
+		var anchor checks.Anchor
+		switch jr.Anchor {
+		case "":
+			anchor = checks.AnchorAfter
+		case "before":
+			anchor = checks.AnchorBefore
+		default:
+			return summary, fmt.Errorf("unknown anchor value: %s", jr.Anchor)
+		}
+		summary.reports = append(summary.reports, Report{
+			ReportedPath:  jr.ReportedPath,
+			SourcePath:    jr.SourcePath,
+			Owner:         jr.Owner,
+			ModifiedLines: jr.ModifiedLines,
+			Rule: parser.Rule{
+				Lines: parser.LineRange{First: jr.RuleLines.First, Last: jr.RuleLines.Last},
+			},
+			Problem: checks.Problem{
+				Lines:    parser.LineRange{First: jr.Lines.First, Last: jr.Lines.Last},
+				Reporter: jr.Reporter,
+				Text:     jr.Text,
+				Details:  jr.Details,
+				Severity: severity,
+				Anchor:   anchor,
+			},
+		})
+	}
+	return summary, nil
+}
+
+// MergeSummaries combines summaries from multiple pint runs that each
+// checked a different subset of the same rules.
+// Every run loads all rules, so the number of parsed rules is not summed,
+// while counters of checked rules and executed checks are.
+func MergeSummaries(summaries ...Summary) (merged Summary) {
+	for _, s := range summaries {
+		merged.Report(s.reports...)
+		for name, count := range s.TruncatedChecks {
+			if merged.TruncatedChecks == nil {
+				merged.TruncatedChecks = map[string]int{}
+			}
+			merged.TruncatedChecks[name] += count
+		}
+		merged.OfflineChecks += s.OfflineChecks
+		merged.OnlineChecks += s.OnlineChecks
+		merged.CheckedEntries += s.CheckedEntries
+		merged.TotalEntries = max(merged.TotalEntries, s.TotalEntries)
+		merged.Duration = max(merged.Duration, s.Duration)
+	}
+	merged.SortReports()
+	return merged
+}
@@ -0,0 +1,110 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestJSONReporter(t *testing.T) {
+	first := Report{
+		ReportedPath:  "foo.yml",
+		SourcePath:    "foo.yml",
+		Owner:         "alice",
+		ModifiedLines: []int{2, 3},
+		Rule:          parser.Rule{Lines: parser.LineRange{First: 1, Last: 3}},
+		Problem: checks.Problem{
+			Lines:    parser.LineRange{First: 2, Last: 3},
+			Reporter: "mock",
+			Text:     "mock text",
+			Details:  "mock details",
+			Severity: checks.Bug,
+			Anchor:   checks.AnchorBefore,
+		},
+	}
+	second := Report{
+		ReportedPath: "bar.yml",
+		SourcePath:   "symlink.yml",
+		Rule:         parser.Rule{Lines: parser.LineRange{First: 5, Last: 6}},
+		Problem: checks.Problem{
+			Lines:    parser.LineRange{First: 6, Last: 6},
+			Reporter: "mock",
+			Text:     "mock info",
+			Severity: checks.Information,
+		},
+	}
+
+	shard1 := NewSummary([]Report{first})
+	shard1.TotalEntries = 4
+	shard1.CheckedEntries = 1
+	shard1.OnlineChecks = 2
+	shard1.OfflineChecks = 3
+	shard1.Duration = time.Second
+	shard1.TruncatedChecks = map[string]int{"promql/series": 1}
+
+	shard2 := NewSummary([]Report{second})
+	shard2.TotalEntries = 4
+	shard2.CheckedEntries = 3
+	shard2.OnlineChecks = 1
+	shard2.OfflineChecks = 5
+	shard2.Duration = time.Second * 3
+	shard2.TruncatedChecks = map[string]int{"promql/series": 2, "promql/cost": 1}
+
+	var summaries []Summary
+	for _, s := range []Summary{shard1, shard2} {
+		var buf bytes.Buffer
+		require.NoError(t, NewJSONReporter(&buf).Submit(s))
+		rs, err := ReadJSONSummary(&buf)
+		require.NoError(t, err)
+		require.Equal(t, s, rs)
+		summaries = append(summaries, rs)
+	}
+
+	merged := MergeSummaries(summaries...)
+	require.Equal(t, []Report{second, first}, merged.Reports())
+	require.Equal(t, 4, merged.TotalEntries)
+	require.Equal(t, int64(4), merged.CheckedEntries)
+	require.Equal(t, int64(3), merged.OnlineChecks)
+	require.Equal(t, int64(8), merged.OfflineChecks)
+	require.Equal(t, time.Second*3, merged.Duration)
+	require.Equal(t, map[string]int{"promql/series": 3, "promql/cost": 1}, merged.TruncatedChecks)
+}
+
+func TestReadJSONSummaryErrors(t *testing.T) {
+	type testCaseT struct {
+		input string
+		err   string
+	}
+
+	testCases := []testCaseT{
+		{
+			input: "{",
+			err:   "unexpected EOF",
+		},
+		{
+			input: `{"foo": 1}`,
+			err:   `json: unknown field "foo"`,
+		},
+		{
+			input: `{"reports": [{"severity": "bad"}]}`,
+			err:   "unknown severity: bad",
+		},
+		{
+			input: `{"reports": [{"severity": "bug", "anchor": "bad"}]}`,
+			err:   "unknown anchor value: bad",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := ReadJSONSummary(strings.NewReader(tc.input))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -49,12 +49,15 @@ func (r Report) isEqual(nr Report) bool {
 }
 
 type Summary struct {
-	reports        []Report
-	OfflineChecks  int64
-	OnlineChecks   int64
-	Duration       time.Duration
-	TotalEntries   int
-	CheckedEntries int64
+	reports []Report
+	// TruncatedChecks is the number of rules for which each check was
+	// cancelled because it exceeded its time budget.
+	TruncatedChecks map[string]int
+	OfflineChecks   int64
+	OnlineChecks    int64
+	Duration        time.Duration
+	TotalEntries    int
+	CheckedEntries  int64
 }
 
 func NewSummary(reports []Report) Summary {
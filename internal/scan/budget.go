@@ -0,0 +1,80 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+// budgetError is set as the cause of context cancellation when checks run for
+// longer than allowed by the budget block in the config file.
+type budgetError struct {
+	scope  string
+	budget time.Duration
+}
+
+func (be budgetError) Error() string {
+	return fmt.Sprintf("%stime budget of %s exceeded", be.scope, output.HumanizeDuration(be.budget))
+}
+
+func withBudget(ctx context.Context, scope string, budget time.Duration) (context.Context, context.CancelFunc) {
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, budget, budgetError{scope: scope, budget: budget})
+}
+
+// budgetExceeded returns the budget error if given context was cancelled
+// because a budget was exceeded.
+func budgetExceeded(ctx context.Context) (be budgetError, ok bool) {
+	if ctx.Err() == nil {
+		return be, false
+	}
+	ok = errors.As(context.Cause(ctx), &be)
+	return be, ok
+}
+
+func budgetReport(j job, be budgetError) reporter.Report {
+	return newJobReport(j, checks.Problem{
+		Lines:      j.entry.Rule.Lines,
+		Reporter:   j.check.Reporter(),
+		Text:       fmt.Sprintf("Check skipped: %s.", be),
+		Details:    "This check didn't complete in time and was cancelled, time limits can be configured using the `budget` block in the `checks` section of the config file.",
+		Severity:   checks.Warning,
+		Incomplete: true,
+	})
+}
+
+// truncatedChecks counts rules for which each check was cancelled or
+// skipped because of an exceeded budget.
+type truncatedChecks struct {
+	checks map[string]int
+	mtx    sync.Mutex
+}
+
+func (tc *truncatedChecks) add(name string) {
+	tc.mtx.Lock()
+	defer tc.mtx.Unlock()
+	if tc.checks == nil {
+		tc.checks = map[string]int{}
+	}
+	tc.checks[name]++
+}
+
+func (tc *truncatedChecks) names() []string {
+	tc.mtx.Lock()
+	defer tc.mtx.Unlock()
+	names := make([]string, 0, len(tc.checks))
+	for name := range tc.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
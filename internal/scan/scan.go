@@ -0,0 +1,339 @@
+package scan
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+// Metrics are updated while checks are running.
+// Any nil field is replaced with a metric that is not exposed anywhere.
+type Metrics struct {
+	Checks            prometheus.Gauge
+	ChecksDone        prometheus.Gauge
+	CheckDuration     *prometheus.SummaryVec
+	RulesParsed       *prometheus.CounterVec
+	SeriesPlanMetrics prometheus.Counter
+	SeriesPlanHits    prometheus.Counter
+}
+
+func (m Metrics) withDefaults() Metrics {
+	if m.Checks == nil {
+		m.Checks = prometheus.NewGauge(prometheus.GaugeOpts{Name: "checks"})
+	}
+	if m.ChecksDone == nil {
+		m.ChecksDone = prometheus.NewGauge(prometheus.GaugeOpts{Name: "checks_done"})
+	}
+	if m.CheckDuration == nil {
+		m.CheckDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: "check_duration"}, []string{"check"})
+	}
+	if m.RulesParsed == nil {
+		m.RulesParsed = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "rules_parsed"}, []string{"kind"})
+	}
+	if m.SeriesPlanMetrics == nil {
+		m.SeriesPlanMetrics = prometheus.NewCounter(prometheus.CounterOpts{Name: "series_plan_metrics"})
+	}
+	if m.SeriesPlanHits == nil {
+		m.SeriesPlanHits = prometheus.NewCounter(prometheus.CounterOpts{Name: "series_plan_hits"})
+	}
+	return m
+}
+
+// Run runs all checks for entries from selected files, all other entries
+// are still passed to checks that need to see every rule.
+// If selected is nil then all entries are checked.
+// Batched series queries and external check commands are run once before
+// any check, and all checks are limited by time budgets from the config.
+func Run(ctx context.Context, workers int, gen *config.PrometheusGenerator, cfg config.Config, entries []discovery.Entry, selected map[string]struct{}, metrics Metrics) (summary reporter.Summary) {
+	ctx, span := tracer.Start(ctx, "pint.scan", trace.WithAttributes(attribute.Int("pint.entries", len(entries))))
+	defer span.End()
+
+	metrics = metrics.withDefaults()
+	metrics.Checks.Set(0)
+	metrics.ChecksDone.Set(0)
+
+	var budget time.Duration
+	if cfg.Checks != nil {
+		budget = cfg.Checks.RunBudget()
+	}
+	ctx, cancel := withBudget(ctx, "run ", budget)
+	defer cancel()
+
+	start := time.Now()
+
+	jobs := make(chan job, workers*5)
+	results := make(chan reporter.Report, workers*5)
+	wg := sync.WaitGroup{}
+	var truncated truncatedChecks
+
+	plan := checks.NewSeriesPlan()
+	ctx = context.WithValue(ctx, promapi.AllPrometheusServers, gen.Servers())
+	ctx = context.WithValue(ctx, checks.SeriesPlanKey, plan)
+	for _, s := range cfg.Check {
+		settings, _ := s.Decode()
+		key := checks.SettingsKey(s.Name)
+		ctx = context.WithValue(ctx, key, settings)
+	}
+
+	for w := 1; w <= workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker(ctx, jobs, results, &truncated, metrics)
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		wg.Wait()
+	}()
+
+	var onlineChecksCount, offlineChecksCount, checkedEntriesCount atomic.Int64
+	go func() {
+		defer close(jobs)
+
+		// Find all checks to run first, so we can plan any batched
+		// queries before the first check runs.
+		planned := make([]job, 0, len(entries))
+		for _, entry := range entries {
+			if selected != nil {
+				if _, ok := selected[entry.SourcePath]; !ok {
+					continue
+				}
+			}
+			switch {
+			case entry.State == discovery.Excluded:
+				continue
+			case entry.PathError != nil && entry.State == discovery.Removed:
+				continue
+			case entry.Rule.Error.Err != nil && entry.State == discovery.Removed:
+				continue
+			case entry.PathError == nil && entry.Rule.Error.Err == nil:
+				if entry.Rule.RecordingRule != nil {
+					metrics.RulesParsed.WithLabelValues(config.RecordingRuleType).Inc()
+					slog.Debug("Found recording rule",
+						slog.String("path", entry.SourcePath),
+						slog.String("record", entry.Rule.RecordingRule.Record.Value),
+						slog.String("lines", entry.Rule.Lines.String()),
+					)
+				}
+				if entry.Rule.AlertingRule != nil {
+					metrics.RulesParsed.WithLabelValues(config.AlertingRuleType).Inc()
+					slog.Debug("Found alerting rule",
+						slog.String("path", entry.SourcePath),
+						slog.String("alert", entry.Rule.AlertingRule.Alert.Value),
+						slog.String("lines", entry.Rule.Lines.String()),
+					)
+				}
+
+				checkedEntriesCount.Inc()
+				es := newEntrySpan(ctx, entry)
+				checkList := cfg.GetChecksForRule(ctx, gen, entry, entry.DisabledChecks)
+				for _, check := range checkList {
+					metrics.Checks.Inc()
+					check := check
+					if check.Meta().IsOnline {
+						onlineChecksCount.Inc()
+					} else {
+						offlineChecksCount.Inc()
+					}
+					if sc, ok := check.(checks.SeriesCheck); ok {
+						sc.Plan(plan, entry.Rule)
+					}
+					es.pending.Inc()
+					var budget time.Duration
+					if cfg.Checks != nil {
+						budget = cfg.Checks.CheckBudget(check.Reporter())
+					}
+					planned = append(planned, job{entry: entry, allEntries: entries, check: check, span: es, budget: budget})
+				}
+			default:
+				if entry.Rule.Error.Err != nil {
+					slog.Debug("Found invalid rule",
+						slog.String("path", entry.SourcePath),
+						slog.String("lines", entry.Rule.Lines.String()),
+					)
+					metrics.RulesParsed.WithLabelValues(config.InvalidRuleType).Inc()
+				}
+				es := newEntrySpan(ctx, entry)
+				es.pending.Inc()
+				planned = append(planned, job{entry: entry, allEntries: entries, check: nil, span: es})
+			}
+		}
+
+		resolveSeriesPlan(ctx, plan, metrics.SeriesPlanMetrics)
+		for _, report := range runExternalChecks(ctx, planned) {
+			results <- report
+		}
+		for _, j := range planned {
+			jobs <- j
+		}
+	}()
+
+	for result := range results {
+		summary.Report(result)
+	}
+	metrics.SeriesPlanHits.Add(float64(plan.Hits()))
+	summary.SortReports()
+	summary.Duration = time.Since(start)
+	summary.TotalEntries = len(entries)
+	summary.CheckedEntries = checkedEntriesCount.Load()
+	summary.OnlineChecks = onlineChecksCount.Load()
+	summary.OfflineChecks = offlineChecksCount.Load()
+	summary.TruncatedChecks = truncated.checks
+
+	if names := truncated.names(); len(names) > 0 {
+		slog.Warn("Some checks were cancelled because they exceeded their time budget", slog.Any("checks", names))
+	}
+
+	return summary
+}
+
+// resolveSeriesPlan runs all batched series queries needed by planned checks.
+func resolveSeriesPlan(ctx context.Context, plan *checks.SeriesPlan, resolved prometheus.Counter) {
+	start := time.Now()
+	plan.Resolve(ctx)
+	if metrics := plan.Metrics(); metrics > 0 {
+		slog.Debug(
+			"Resolved batched series queries",
+			slog.Int("metrics", metrics),
+			slog.String("duration", output.HumanizeDuration(time.Since(start))),
+		)
+		resolved.Add(float64(metrics))
+	}
+}
+
+// runExternalChecks runs the command of every external check used by planned
+// jobs once for all entries, checks will then only return cached results.
+// Command failures are reported once, for the first rule using that check.
+func runExternalChecks(ctx context.Context, planned []job) (reports []reporter.Report) {
+	done := map[string]struct{}{}
+	for _, j := range planned {
+		if _, ok := j.check.(checks.ExternalCheck); !ok {
+			continue
+		}
+		name := j.check.Reporter()
+		if _, ok := done[name]; ok {
+			continue
+		}
+		done[name] = struct{}{}
+
+		settings, ok := ctx.Value(checks.SettingsKey(name)).(*checks.ExternalCheckSettings)
+		if !ok {
+			continue
+		}
+		if err := settings.Run(ctx, name, j.allEntries); err != nil {
+			reports = append(reports, newJobReport(j, settings.FailureProblem(name, j.entry.Rule.Lines)))
+		}
+	}
+	return reports
+}
+
+type job struct {
+	check      checks.RuleChecker
+	span       *entrySpan
+	allEntries []discovery.Entry
+	entry      discovery.Entry
+	budget     time.Duration
+}
+
+func newJobReport(j job, problem checks.Problem) reporter.Report {
+	return reporter.Report{
+		ReportedPath:  j.entry.ReportedPath,
+		SourcePath:    j.entry.SourcePath,
+		ModifiedLines: j.entry.ModifiedLines,
+		Rule:          j.entry.Rule,
+		Problem:       problem,
+		Owner:         j.entry.Owner,
+	}
+}
+
+func worker(ctx context.Context, jobs <-chan job, results chan<- reporter.Report, truncated *truncatedChecks, metrics Metrics) {
+	for j := range jobs {
+		j := j
+
+		// Offline checks and rule errors don't send any queries, so they
+		// still run after the run budget was exceeded, only online checks
+		// are skipped.
+		be, overBudget := budgetExceeded(ctx)
+		isOnline := j.check != nil && j.check.Meta().IsOnline
+		if ctx.Err() != nil && (isOnline || !overBudget) {
+			if overBudget {
+				truncated.add(j.check.Reporter())
+				results <- budgetReport(j, be)
+			}
+			// Keep reading jobs so the sender is never blocked.
+			j.span.done()
+			metrics.ChecksDone.Inc()
+			continue
+		}
+
+		entryCtx := j.span.start()
+		if overBudget {
+			entryCtx = context.WithoutCancel(entryCtx)
+		}
+		runJob(entryCtx, j, results, truncated, metrics.CheckDuration)
+		j.span.done()
+		metrics.ChecksDone.Inc()
+	}
+}
+
+func runJob(ctx context.Context, j job, results chan<- reporter.Report, truncated *truncatedChecks, checkDuration *prometheus.SummaryVec) {
+	if problem, ok := checks.EntryErrorProblem(j.entry); ok {
+		results <- newJobReport(j, problem)
+		return
+	}
+
+	if j.entry.State == discovery.Unknown {
+		slog.Warn(
+			"Bug: unknown rule state",
+			slog.String("path", j.entry.ReportedPath),
+			slog.Int("line", j.entry.Rule.Lines.First),
+			slog.String("name", j.entry.Rule.Name()),
+		)
+	}
+
+	checkCtx, span := tracer.Start(
+		ctx,
+		"pint.check",
+		trace.WithAttributes(
+			attribute.String("pint.check", j.check.String()),
+			attribute.String("pint.check.reporter", j.check.Reporter()),
+			attribute.Bool("pint.check.online", j.check.Meta().IsOnline),
+		),
+	)
+	checkCtx, cancel := withBudget(checkCtx, "", j.budget)
+	start := time.Now()
+	problems := j.check.Check(checkCtx, j.entry.ReportedPath, j.entry.Rule, j.allEntries)
+	checkDuration.WithLabelValues(j.check.Reporter()).Observe(time.Since(start).Seconds())
+	be, isTruncated := budgetExceeded(checkCtx)
+	cancel()
+	span.SetAttributes(
+		attribute.Int("pint.problems", len(problems)),
+		attribute.Bool("pint.check.truncated", isTruncated),
+	)
+	span.End()
+	if isTruncated {
+		// Any problem reported by a cancelled check is likely caused
+		// by the cancellation itself, so only report exceeded budget.
+		truncated.add(j.check.Reporter())
+		results <- budgetReport(j, be)
+		return
+	}
+	for _, problem := range problems {
+		results <- newJobReport(j, problem)
+	}
+}
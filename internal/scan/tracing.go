@@ -0,0 +1,52 @@
+package scan
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
+
+	"github.com/cloudflare/pint/internal/discovery"
+)
+
+var tracer = otel.Tracer("github.com/cloudflare/pint/internal/scan")
+
+// entrySpan is a span covering all checks run for a single rule.
+// Checks for the same rule can run on different workers, so the span is
+// started by the first check and ended by the last one.
+type entrySpan struct {
+	parent  context.Context
+	ctx     context.Context
+	span    trace.Span
+	entry   discovery.Entry
+	once    sync.Once
+	pending atomic.Int64
+}
+
+func newEntrySpan(ctx context.Context, entry discovery.Entry) *entrySpan {
+	return &entrySpan{parent: ctx, entry: entry}
+}
+
+func (es *entrySpan) start() context.Context {
+	es.once.Do(func() {
+		es.ctx, es.span = tracer.Start(
+			es.parent,
+			"pint.rule",
+			trace.WithAttributes(
+				attribute.String("pint.path", es.entry.ReportedPath),
+				attribute.String("pint.rule.name", es.entry.Rule.Name()),
+				attribute.Int("pint.rule.line", es.entry.Rule.Lines.First),
+			),
+		)
+	})
+	return es.ctx
+}
+
+func (es *entrySpan) done() {
+	if es.pending.Dec() == 0 && es.span != nil {
+		es.span.End()
+	}
+}
@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"golang.org/x/exp/slices"
+
+	"github.com/cloudflare/pint/internal/config"
+)
+
+// Config controls which checks are run and how.
+// Use NewConfig or ParseConfig to create it, zero value is not usable.
+type Config struct {
+	cfg   config.Config
+	valid bool
+}
+
+// Prometheus server that online checks will query.
+type Prometheus struct {
+	// Name of this server, it must be unique.
+	Name string
+	// URI of this server, example: http://prometheus.example.com.
+	URI string
+	// Timeout for all queries, defaults to 2 minutes if empty.
+	Timeout string
+	// Include is a list of regexp patterns, only rule files with
+	// a matching path will be checked against this server.
+	Include []string
+	// Exclude is a list of regexp patterns, rule files with matching
+	// path will not be checked against this server.
+	Exclude []string
+	// Tags that can be used to match this server in disable comments.
+	Tags []string
+}
+
+// ConfigOption modifies config created by NewConfig.
+type ConfigOption func(*config.Config)
+
+// WithPrometheus adds a Prometheus server to the config.
+func WithPrometheus(p Prometheus) ConfigOption {
+	return func(cfg *config.Config) {
+		cfg.Prometheus = append(cfg.Prometheus, config.PrometheusConfig{
+			Name:    p.Name,
+			URI:     p.URI,
+			Timeout: p.Timeout,
+			Include: p.Include,
+			Exclude: p.Exclude,
+			Tags:    p.Tags,
+		})
+	}
+}
+
+// WithRelaxed sets the list of regexp patterns for rule files that
+// should be parsed in relaxed mode.
+// See pint docs for the parser config block for details.
+func WithRelaxed(patterns ...string) ConfigOption {
+	return func(cfg *config.Config) {
+		cfg.Parser.Relaxed = append(cfg.Parser.Relaxed, patterns...)
+	}
+}
+
+// WithEnabledChecks limits the list of checks to run, only checks on this
+// list will be enabled.
+func WithEnabledChecks(names ...string) ConfigOption {
+	return func(cfg *config.Config) {
+		cfg.Checks.Enabled = names
+	}
+}
+
+// WithDisabledChecks disables all checks on this list.
+func WithDisabledChecks(names ...string) ConfigOption {
+	return func(cfg *config.Config) {
+		cfg.Checks.Disabled = append(cfg.Checks.Disabled, names...)
+	}
+}
+
+// NewConfig creates a config with default values and applies all
+// options to it.
+func NewConfig(opts ...ConfigOption) (Config, error) {
+	cfg := config.Default()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return Config{cfg: cfg, valid: true}, nil
+}
+
+// ParseConfig creates a config from HCL source, it uses the same syntax
+// as pint config files.
+func ParseConfig(src []byte) (Config, error) {
+	cfg, err := config.Parse("pint.hcl", src)
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{cfg: cfg, valid: true}, nil
+}
+
+// CheckNames returns the list of names of all built-in checks.
+func CheckNames() []string {
+	return slices.Clone(config.Default().Checks.Enabled)
+}
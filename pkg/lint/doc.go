@@ -0,0 +1,13 @@
+// Package lint allows to run pint checks from other Go programs.
+//
+// It exposes a small API that wraps pint internals: a caller can build a
+// config, either programmatically or from HCL bytes using the same syntax
+// as pint config files, pass rule files content loaded into memory and
+// get back a list of problems found by pint checks.
+//
+// This package follows semantic versioning, any backward incompatible
+// changes to exported types and functions will only be made in a new
+// major release of pint. Problem texts, details and the list of checks
+// enabled by default are not part of that guarantee and can change
+// between any two releases.
+package lint
@@ -0,0 +1,73 @@
+package lint_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudflare/pint/pkg/lint"
+)
+
+func ExampleLint() {
+	cfg, err := lint.NewConfig(lint.WithDisabledChecks("promql/fragile"))
+	if err != nil {
+		panic(err)
+	}
+
+	files := []lint.File{
+		{
+			Path: "rules.yml",
+			Content: []byte(`groups:
+- name: example
+  rules:
+  - alert: Foo
+    expr: sum(up) by (job) == 0
+    annotations:
+      summary: '{{ $labels.instance }} is down'
+`),
+		},
+	}
+
+	problems, err := lint.Lint(context.Background(), cfg, files, lint.WithOffline())
+	if err != nil {
+		panic(err)
+	}
+	for _, p := range problems {
+		fmt.Printf("%s:%d %s: %s (%s)\n", p.Path, p.Lines.First, p.Severity, p.Text, p.Reporter)
+	}
+	// Output:
+	// rules.yml:7 Bug: Template is using `instance` label but the query removes it. (alerts/template)
+}
+
+func ExampleParseConfig() {
+	cfg, err := lint.ParseConfig([]byte(`
+rule {
+  match {
+    kind = "recording"
+  }
+  check "custom" "prefix" {
+    expression = "rule.name.startsWith('team_x:')"
+    message    = "Recording rule {{ $record }} must use team_x: prefix."
+  }
+}
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	files := []lint.File{
+		{
+			Path:    "rules.yml",
+			Content: []byte("groups:\n- name: example\n  rules:\n  - record: up:sum\n    expr: sum(up)\n"),
+		},
+	}
+
+	problems, err := lint.Lint(context.Background(), cfg, files, lint.WithOffline())
+	if err != nil {
+		panic(err)
+	}
+	for _, p := range problems {
+		fmt.Printf("%s:%d-%d %s: %s (%s)\n", p.Path, p.Lines.First, p.Lines.Last, p.Severity, p.Text, p.Reporter)
+	}
+	// Output:
+	// rules.yml:4-5 Bug: Recording rule up:sum must use team_x: prefix. (rule/custom)
+}
@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/scan"
+)
+
+// defaultWorkers is the number of checks run concurrently, it's the same
+// as the default value of the pint --workers flag.
+const defaultWorkers = 10
+
+var errInvalidConfig = errors.New("invalid config, use NewConfig or ParseConfig to create it")
+
+// File is a rule file to check.
+type File struct {
+	// Path of the file, it's used for matching config rules and for reporting.
+	Path string
+	// Content of the file, it must use the Prometheus rule file format.
+	Content []byte
+}
+
+// Option modifies how Lint works.
+type Option func(*options)
+
+type options struct {
+	offline bool
+}
+
+// WithOffline disables all checks that need to send queries
+// to Prometheus servers.
+func WithOffline() Option {
+	return func(o *options) {
+		o.offline = true
+	}
+}
+
+// Lint parses all files and runs enabled checks against every rule found.
+// Checks are run the same way pint lint runs them, including time budgets
+// set in the checks config block.
+// Returned problems are sorted by path, line and reporter.
+// An error is only returned if files cannot be read or checks cannot
+// be started, problems with rules are always reported as Problem.
+func Lint(ctx context.Context, cfg Config, files []File, opts ...Option) ([]Problem, error) {
+	if !cfg.valid {
+		return nil, errInvalidConfig
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Config is a copy but it contains slices that we might modify below.
+	pcfg := cfg.cfg
+	checksCfg := *pcfg.Checks
+	checksCfg.Disabled = append([]string{}, checksCfg.Disabled...)
+	pcfg.Checks = &checksCfg
+	if o.offline {
+		pcfg.DisableOnlineChecks()
+	}
+
+	finder := discovery.NewContentFinder(toDiscoveryFiles(files), git.NewPathFilter(nil, nil, compileRelaxed(pcfg)))
+	entries, err := finder.Find()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, config.CommandKey, config.LintCommand)
+
+	gen := config.NewPrometheusGenerator(pcfg, prometheus.NewRegistry())
+	defer gen.Stop()
+
+	if err = gen.GenerateStatic(); err != nil {
+		return nil, err
+	}
+	if !o.offline && len(entries) > 0 {
+		if err = gen.GenerateDynamic(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := scan.Run(ctx, defaultWorkers, gen, pcfg, entries, nil, scan.Metrics{})
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	problems := make([]Problem, 0, len(summary.Reports()))
+	for _, report := range summary.Reports() {
+		problems = append(problems, newProblem(report))
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Path != problems[j].Path {
+			return problems[i].Path < problems[j].Path
+		}
+		if problems[i].Lines.First != problems[j].Lines.First {
+			return problems[i].Lines.First < problems[j].Lines.First
+		}
+		return problems[i].Reporter < problems[j].Reporter
+	})
+
+	return problems, nil
+}
+
+func toDiscoveryFiles(files []File) []discovery.File {
+	df := make([]discovery.File, 0, len(files))
+	for _, f := range files {
+		df = append(df, discovery.File{Path: f.Path, Content: f.Content})
+	}
+	return df
+}
+
+func compileRelaxed(cfg config.Config) []*regexp.Regexp {
+	if cfg.Parser == nil {
+		return nil
+	}
+	return cfg.Parser.CompileRelaxed()
+}
@@ -0,0 +1,282 @@
+package lint_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/pkg/lint"
+)
+
+const regexpDetails = "See [Prometheus documentation](https://prometheus.io/docs/prometheus/latest/querying/basics/#time-series-selectors) for details on how vector selectors work."
+
+func TestLint(t *testing.T) {
+	type testCaseT struct {
+		title    string
+		config   func(t *testing.T) lint.Config
+		files    []lint.File
+		opts     []lint.Option
+		problems []lint.Problem
+		err      string
+	}
+
+	mustConfig := func(opts ...lint.ConfigOption) func(t *testing.T) lint.Config {
+		return func(t *testing.T) lint.Config {
+			cfg, err := lint.NewConfig(opts...)
+			require.NoError(t, err)
+			return cfg
+		}
+	}
+
+	mustParse := func(src string) func(t *testing.T) lint.Config {
+		return func(t *testing.T) lint.Config {
+			cfg, err := lint.ParseConfig([]byte(src))
+			require.NoError(t, err)
+			return cfg
+		}
+	}
+
+	testCases := []testCaseT{
+		{
+			title: "zero config",
+			config: func(_ *testing.T) lint.Config {
+				return lint.Config{}
+			},
+			err: "invalid config, use NewConfig or ParseConfig to create it",
+		},
+		{
+			title:    "no files",
+			config:   mustConfig(),
+			problems: []lint.Problem{},
+		},
+		{
+			title:  "valid rules",
+			config: mustConfig(lint.WithRelaxed(".*")),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("- record: foo\n  expr: sum(foo)\n")},
+			},
+			problems: []lint.Problem{},
+		},
+		{
+			title:  "strict mode",
+			config: mustConfig(),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("- record: foo\n  expr: sum(foo)\n")},
+			},
+			problems: []lint.Problem{
+				{
+					Path:     "rules.yml",
+					Lines:    lint.Lines{First: 1, Last: 1},
+					Reporter: "yaml/parse",
+					Text:     "YAML parser returned an error when reading this file: `cannot unmarshal !!seq into rulefmt.RuleGroups`.",
+					Details: `pint cannot read this file because YAML parser returned an error.
+This usually means that you have an indention error or the file doesn't have the YAML structure required by Prometheus for [recording](https://prometheus.io/docs/prometheus/latest/configuration/recording_rules/) and [alerting](https://prometheus.io/docs/prometheus/latest/configuration/alerting_rules/) rules.
+If this file is a template that will be rendered into valid YAML then you can instruct pint to ignore some lines using comments, see [pint docs](https://cloudflare.github.io/pint/ignoring.html).
+`,
+					Severity: lint.SeverityFatal,
+				},
+			},
+		},
+		{
+			title:  "problems sorted by path and line",
+			config: mustConfig(lint.WithRelaxed(".*")),
+			files: []lint.File{
+				{Path: "b.yml", Content: []byte("# pint file/owner bob\n- alert: foo\n  expr: foo{job=~\"bar\"} > 0\n")},
+				{Path: "a.yml", Content: []byte("- record: foo\n  expr: sum(foo)\n- alert: foo\n  expr: foo{job=~\"bar\"} > 0\n- alert: foo\n  expr: foo{job=~\"bar\"} > 0\n")},
+			},
+			problems: []lint.Problem{
+				{
+					Path:     "a.yml",
+					RuleName: "foo",
+					Lines:    lint.Lines{First: 4, Last: 4},
+					Reporter: "promql/regexp",
+					Text:     "Unnecessary regexp match on static string `job=~\"bar\"`, use `job=\"bar\"` instead.",
+					Details:  regexpDetails,
+					Severity: lint.SeverityBug,
+				},
+				{
+					Path:     "a.yml",
+					RuleName: "foo",
+					Lines:    lint.Lines{First: 6, Last: 6},
+					Reporter: "promql/regexp",
+					Text:     "Unnecessary regexp match on static string `job=~\"bar\"`, use `job=\"bar\"` instead.",
+					Details:  regexpDetails,
+					Severity: lint.SeverityBug,
+				},
+				{
+					Path:     "b.yml",
+					Owner:    "bob",
+					RuleName: "foo",
+					Lines:    lint.Lines{First: 3, Last: 3},
+					Reporter: "promql/regexp",
+					Text:     "Unnecessary regexp match on static string `job=~\"bar\"`, use `job=\"bar\"` instead.",
+					Details:  regexpDetails,
+					Severity: lint.SeverityBug,
+				},
+			},
+		},
+		{
+			title:  "disabled checks",
+			config: mustConfig(lint.WithRelaxed(".*"), lint.WithDisabledChecks("promql/regexp")),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("- alert: foo\n  expr: foo{job=~\"bar\"} > 0\n")},
+			},
+			problems: []lint.Problem{},
+		},
+		{
+			title:  "enabled checks",
+			config: mustConfig(lint.WithRelaxed(".*"), lint.WithEnabledChecks("promql/syntax")),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("- alert: foo\n  expr: foo{job=~\"bar\"} > 0\n")},
+			},
+			problems: []lint.Problem{},
+		},
+		{
+			title: "offline",
+			config: mustConfig(lint.WithRelaxed(".*"), lint.WithPrometheus(lint.Prometheus{
+				Name: "prom",
+				URI:  "http://127.0.0.1:1",
+			})),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("- record: foo\n  expr: sum(foo)\n")},
+			},
+			opts:     []lint.Option{lint.WithOffline()},
+			problems: []lint.Problem{},
+		},
+		{
+			title: "config from HCL",
+			config: mustParse(`
+parser {
+  relaxed = [".*"]
+}
+checks {
+  enabled = ["promql/regexp"]
+}
+`),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("# pint rule/owner bob\n- alert: foo\n  expr: foo{job=~\"bar\"} > 0\n")},
+			},
+			problems: []lint.Problem{
+				{
+					Path:     "rules.yml",
+					Owner:    "bob",
+					RuleName: "foo",
+					Lines:    lint.Lines{First: 3, Last: 3},
+					Reporter: "promql/regexp",
+					Text:     "Unnecessary regexp match on static string `job=~\"bar\"`, use `job=\"bar\"` instead.",
+					Details:  regexpDetails,
+					Severity: lint.SeverityBug,
+				},
+			},
+		},
+		{
+			title: "failing external check is reported once",
+			config: mustParse(`
+parser {
+  relaxed = [".*"]
+}
+check "external/broken" {
+  command = ["sh", "-c", "cat > /dev/null; echo boom >&2; exit 1"]
+}
+`),
+			files: []lint.File{
+				{Path: "rules.yml", Content: []byte("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n")},
+			},
+			problems: []lint.Problem{
+				{
+					Path:     "rules.yml",
+					RuleName: "foo",
+					Lines:    lint.Lines{First: 1, Last: 2},
+					Reporter: "external/broken",
+					Text:     "Failed to run external check `external/broken`: `command failed: exit status 1`.",
+					Details:  "Command stderr:\n\n```\nboom\n```",
+					Severity: lint.SeverityBug,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			problems, err := lint.Lint(context.Background(), tc.config(t), tc.files, tc.opts...)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.problems, problems)
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	_, err := lint.NewConfig(lint.WithPrometheus(lint.Prometheus{Name: "prom"}))
+	require.EqualError(t, err, "prometheus URI cannot be empty")
+
+	_, err = lint.NewConfig(lint.WithEnabledChecks("foo"))
+	require.EqualError(t, err, "unknown check name foo")
+
+	_, err = lint.ParseConfig([]byte("foo {}"))
+	require.EqualError(t, err, `pint.hcl:1,1-4: Unsupported block type; Blocks of type "foo" are not expected here.`)
+}
+
+func TestCheckNames(t *testing.T) {
+	names := lint.CheckNames()
+	require.Contains(t, names, "promql/syntax")
+	names[0] = "foo"
+	require.NotContains(t, lint.CheckNames(), "foo")
+}
+
+func TestProblemIsFailure(t *testing.T) {
+	require.False(t, lint.Problem{Severity: lint.SeverityInformation}.IsFailure())
+	require.False(t, lint.Problem{Severity: lint.SeverityWarning}.IsFailure())
+	require.True(t, lint.Problem{Severity: lint.SeverityBug}.IsFailure())
+	require.True(t, lint.Problem{Severity: lint.SeverityFatal}.IsFailure())
+}
+
+func TestLintBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	cfg, err := lint.ParseConfig([]byte(fmt.Sprintf(`
+prometheus "prom" {
+  uri = "%s"
+}
+parser {
+  relaxed = [".*"]
+}
+checks {
+  enabled = ["promql/series"]
+  budget {
+    check = "100ms"
+  }
+}
+`, srv.URL)))
+	require.NoError(t, err)
+
+	problems, err := lint.Lint(context.Background(), cfg, []lint.File{
+		{Path: "rules.yml", Content: []byte("- record: foo\n  expr: sum(foo)\n")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []lint.Problem{
+		{
+			Path:     "rules.yml",
+			RuleName: "foo",
+			Lines:    lint.Lines{First: 1, Last: 2},
+			Reporter: "promql/series",
+			Text:     "Check skipped: time budget of 100ms exceeded.",
+			Details:  "This check didn't complete in time and was cancelled, time limits can be configured using the `budget` block in the `checks` section of the config file.",
+			Severity: lint.SeverityWarning,
+		},
+	}, problems)
+}
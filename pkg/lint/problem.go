@@ -0,0 +1,83 @@
+package lint
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+// Severity of a problem.
+type Severity string
+
+const (
+	// SeverityInformation is only used for informational messages.
+	SeverityInformation Severity = "Information"
+	// SeverityWarning is a problem that should be looked at but doesn't
+	// need to be fixed.
+	SeverityWarning Severity = "Warning"
+	// SeverityBug is a problem that should be fixed.
+	SeverityBug Severity = "Bug"
+	// SeverityFatal is a problem that prevents the file from being checked,
+	// usually because it cannot be parsed.
+	SeverityFatal Severity = "Fatal"
+)
+
+// Lines is a range of lines in a file, both ends are inclusive.
+type Lines struct {
+	First int
+	Last  int
+}
+
+// Problem found by one of the checks.
+type Problem struct {
+	// Path of the file with the problem.
+	Path string
+	// Owner of the rule, if set via comments.
+	Owner string
+	// RuleName is either the alert or the record name, it's empty for
+	// problems that are not specific to a single rule.
+	RuleName string
+	// Lines of the file with the problem.
+	Lines Lines
+	// Reporter is the name of the check that reported this problem.
+	Reporter string
+	// Text is a short description of the problem.
+	Text string
+	// Details is an optional extra description, it can use Markdown.
+	Details string
+	// Severity of this problem.
+	Severity Severity
+}
+
+// IsFailure returns true for problems with Bug severity or higher.
+func (p Problem) IsFailure() bool {
+	return p.Severity == SeverityBug || p.Severity == SeverityFatal
+}
+
+func newProblem(report reporter.Report) Problem {
+	return Problem{
+		Path:     report.ReportedPath,
+		Owner:    report.Owner,
+		RuleName: report.Rule.Name(),
+		Lines: Lines{
+			First: report.Problem.Lines.First,
+			Last:  report.Problem.Lines.Last,
+		},
+		Reporter: report.Problem.Reporter,
+		Text:     report.Problem.Text,
+		Details:  report.Problem.Details,
+		Severity: newSeverity(report.Problem.Severity),
+	}
+}
+
+func newSeverity(s checks.Severity) Severity {
+	switch s {
+	case checks.Information:
+		return SeverityInformation
+	case checks.Warning:
+		return SeverityWarning
+	case checks.Bug:
+		return SeverityBug
+	default:
+		return SeverityFatal
+	}
+}